@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	r "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+)
+
+// runImport imports a CSV or JSON Lines catalog file and prints the report of
+// every row, the format defaults to the file extension and - reads stdin
+//
+//	go run ./cmd import [-format csv|jsonl] books.csv
+func runImport(db *sql.DB, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "format of the file, csv or jsonl")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [-format csv|jsonl] <file>")
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if *format == "ndjson" {
+			*format = string(entity.BookImportJSONL)
+		}
+	}
+	if !entity.BookImportFormat(*format).IsValid() {
+		return fmt.Errorf("unknown import format %q, use -format csv or -format jsonl", *format)
+	}
+
+	var file io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	bookUC := u.NewBookUseCase(r.NewBookRepository(db), r.NewAuthorRepository(db), r.NewSubjectRepository(db))
+
+	report, err := bookUC.ImportBooks(context.Background(), entity.BookImportFormat(*format), file)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tSTATUS\tBOOK\tREASON")
+	for _, row := range report.Rows {
+		var bookID int
+		var reason string
+		if row.Book != nil {
+			bookID = row.Book.ID
+		}
+		if row.Err != nil {
+			reason = row.Err.Error()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", row.Line, row.Status, bookID, reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d created, %d updated, %d rejected\n", report.Created, report.Updated, report.Rejected)
+
+	return nil
+}
@@ -17,9 +17,13 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/LuigiAzevedo/public-library-v2/config"
+	"github.com/LuigiAzevedo/public-library-v2/internal/auth"
 	r "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	handler "github.com/LuigiAzevedo/public-library-v2/internal/delivery/http"
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	u "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mail"
+	svc "github.com/LuigiAzevedo/public-library-v2/internal/ports/service"
 )
 
 func main() {
@@ -36,6 +40,14 @@ func main() {
 	}
 	defer db.Close()
 
+	// the import subcommand imports a catalog file instead of serving the API
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(db, os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("failed to import the books")
+		}
+		return
+	}
+
 	// configurations for the logger middleware
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	httplog.Configure(httplog.Options{Concise: true, TimeFieldFormat: time.DateTime})
@@ -44,25 +56,65 @@ func main() {
 
 	// middleware
 	router.Use(httplog.RequestLogger(log.Logger))
-	router.Use(middleware.Timeout(60 * time.Second))
+	router.Use(handler.Timeout(60 * time.Second))
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
 	router.Use(middleware.Recoverer)
 
+	// services DI
+	tokenService := auth.NewJWTService(config.TokenSecret, config.TokenDuration)
+	mailSender := setupMail(config)
+
 	// repositories DI
 	userRepo := r.NewUserRepository(db)
 	bookRepo := r.NewBookRepository(db)
+	copyRepo := r.NewCopyRepository(db)
+	authorRepo := r.NewAuthorRepository(db)
+	subjectRepo := r.NewSubjectRepository(db)
 	loanRepo := r.NewLoanRepository(db)
+	holdRepo := r.NewHoldRepository(db)
+	ledgerRepo := r.NewLedgerRepository(db)
+	resetRepo := r.NewResetTokenRepository(db)
+	txManager := r.NewTxManager(db)
+
+	// library lending rules
+	loanPolicy := u.LoanPolicy{
+		LoanPeriod:  days(config.LoanPeriodDays),
+		MaxRenewals: config.MaxRenewals,
+		HoldExpiry:  days(config.HoldExpiryDays),
+		FinePerDay:  config.FinePerDayCents,
+		MaxBalance:  config.MaxBalanceCents,
+		Categories: map[entity.Category]u.CategoryPolicy{
+			entity.CategoryGuest:   {MaxLoans: config.GuestMaxLoans, LoanPeriod: days(config.GuestLoanPeriodDays)},
+			entity.CategoryStudent: {MaxLoans: config.StudentMaxLoans, LoanPeriod: days(config.StudentLoanPeriodDays)},
+			entity.CategoryStaff:   {MaxLoans: config.StaffMaxLoans, LoanPeriod: days(config.StaffLoanPeriodDays)},
+		},
+	}
 
 	// usecase DI
+	authUC := u.NewAuthUseCase(userRepo, resetRepo, txManager, tokenService, mailSender, config.ResetTokenDuration)
 	userUC := u.NewUserUseCase(userRepo)
-	bookUC := u.NewBookUseCase(bookRepo)
-	loanUC := u.NewLoanUseCase(loanRepo, userRepo, bookRepo)
+	bookUC := u.NewBookUseCase(bookRepo, authorRepo, subjectRepo)
+	authorUC := u.NewAuthorUseCase(authorRepo)
+	subjectUC := u.NewSubjectUseCase(subjectRepo, bookRepo)
+	copyUC := u.NewCopyUseCase(copyRepo, bookRepo, holdRepo, txManager, loanPolicy)
+	loanUC := u.NewLoanUseCase(loanRepo, userRepo, bookRepo, copyRepo, holdRepo, ledgerRepo, txManager, loanPolicy)
+	holdUC := u.NewHoldUseCase(holdRepo, userRepo, bookRepo, txManager, loanPolicy)
+	fineUC := u.NewFineUseCase(ledgerRepo, userRepo, txManager)
+
+	// authenticates every request carrying a session token
+	router.Use(handler.Authenticate(authUC))
 
 	// HTTP handlers
+	handler.NewAuthHandler(router, authUC)
 	handler.NewBookHandler(router, bookUC)
+	handler.NewCopyHandler(router, copyUC)
+	handler.NewAuthorHandler(router, authorUC)
+	handler.NewSubjectHandler(router, subjectUC)
 	handler.NewUserHandler(router, userUC)
 	handler.NewLoanHandler(router, loanUC)
+	handler.NewHoldHandler(router, holdUC)
+	handler.NewFineHandler(router, fineUC)
 
 	server := newServer(config.ServeAddress, router)
 	go func() {
@@ -104,6 +156,21 @@ func setupDB(driver, url string) (*sql.DB, error) {
 	return db, nil
 }
 
+// setupMail chooses how emails are sent
+func setupMail(c config.AppConfig) svc.MailSender {
+	if c.SMTPHost == "" {
+		log.Warn().Msg("SMTP_HOST is empty, emails will be kept in memory and never delivered")
+		return mail.NewMemorySender()
+	}
+
+	return mail.NewSMTPSender(c.SMTPHost, c.SMTPPort, c.SMTPUsername, c.SMTPPassword, c.MailFrom)
+}
+
+// days converts a number of days from the configuration into a duration
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
 // newServer initiates a http server
 func newServer(addr string, r *chi.Mux) *http.Server {
 	return &http.Server{
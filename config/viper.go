@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,6 +11,39 @@ type AppConfig struct {
 	DbURL        string `mapstructure:"DB_URL"`
 	DbDriver     string `mapstructure:"DB_DRIVER"`
 	ServeAddress string `mapstructure:"SERVE_ADDRESS"`
+
+	// TokenSecret signs the session tokens, it must be kept private
+	TokenSecret string `mapstructure:"TOKEN_SECRET"`
+	// TokenDuration is how long a session token is valid after login
+	TokenDuration time.Duration `mapstructure:"TOKEN_DURATION"`
+	// ResetTokenDuration is how long a password reset token is valid after it is mailed
+	ResetTokenDuration time.Duration `mapstructure:"RESET_TOKEN_DURATION"`
+
+	// SMTP server used to send emails, they are kept in memory when SMTPHost is empty
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+
+	// LoanPeriodDays is how many days a patron can keep a borrowed book
+	LoanPeriodDays int `mapstructure:"LOAN_PERIOD_DAYS"`
+	// MaxRenewals is how many times a loan can be renewed
+	MaxRenewals int `mapstructure:"MAX_RENEWALS"`
+	// HoldExpiryDays is how many days a returned copy waits for the patron who held it
+	HoldExpiryDays int `mapstructure:"HOLD_EXPIRY_DAYS"`
+	// FinePerDayCents is charged for each day a book is returned late
+	FinePerDayCents int `mapstructure:"FINE_PER_DAY_CENTS"`
+	// MaxBalanceCents is the highest balance a patron can owe and still borrow
+	MaxBalanceCents int `mapstructure:"MAX_BALANCE_CENTS"`
+
+	// borrowing limits per patron category, a zero loan period uses LOAN_PERIOD_DAYS
+	GuestMaxLoans         int `mapstructure:"GUEST_MAX_LOANS"`
+	GuestLoanPeriodDays   int `mapstructure:"GUEST_LOAN_PERIOD_DAYS"`
+	StudentMaxLoans       int `mapstructure:"STUDENT_MAX_LOANS"`
+	StudentLoanPeriodDays int `mapstructure:"STUDENT_LOAN_PERIOD_DAYS"`
+	StaffMaxLoans         int `mapstructure:"STAFF_MAX_LOANS"`
+	StaffLoanPeriodDays   int `mapstructure:"STAFF_LOAN_PERIOD_DAYS"`
 }
 
 func LoadAppConfig(path string) (AppConfig, error) {
@@ -22,6 +56,21 @@ func LoadAppConfig(path string) (AppConfig, error) {
 	viper.SetConfigType("env")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("TOKEN_DURATION", "24h")
+	viper.SetDefault("RESET_TOKEN_DURATION", "30m")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("LOAN_PERIOD_DAYS", 14)
+	viper.SetDefault("MAX_RENEWALS", 2)
+	viper.SetDefault("HOLD_EXPIRY_DAYS", 3)
+	viper.SetDefault("FINE_PER_DAY_CENTS", 25)
+	viper.SetDefault("MAX_BALANCE_CENTS", 500)
+	viper.SetDefault("GUEST_MAX_LOANS", 2)
+	viper.SetDefault("GUEST_LOAN_PERIOD_DAYS", 7)
+	viper.SetDefault("STUDENT_MAX_LOANS", 5)
+	viper.SetDefault("STUDENT_LOAN_PERIOD_DAYS", 0)
+	viper.SetDefault("STAFF_MAX_LOANS", 10)
+	viper.SetDefault("STAFF_LOAN_PERIOD_DAYS", 28)
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			return AppConfig{}, fmt.Errorf("config file not found: %s", path)
@@ -34,5 +83,9 @@ func LoadAppConfig(path string) (AppConfig, error) {
 		return AppConfig{}, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if config.TokenSecret == "" {
+		return AppConfig{}, fmt.Errorf("TOKEN_SECRET is empty")
+	}
+
 	return config, nil
 }
@@ -0,0 +1,10 @@
+package auth
+
+import "errors"
+
+// Token Errors
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrInvalidToken   = errors.New("invalid token signature")
+	ErrExpiredToken   = errors.New("token has expired")
+)
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	svc "github.com/LuigiAzevedo/public-library-v2/internal/ports/service"
+)
+
+// header is the only JWT header issued and accepted
+var header = encode([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type jwtService struct {
+	secret   []byte
+	duration time.Duration
+}
+
+// payload holds the registered JWT claims
+type payload struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewJWTService creates a new instance of jwtService signing HS256 tokens valid for duration
+func NewJWTService(secret string, duration time.Duration) svc.TokenService {
+	return &jwtService{
+		secret:   []byte(secret),
+		duration: duration,
+	}
+}
+
+// Issue issues a signed token for the user
+func (j *jwtService) Issue(u *entity.User) (*entity.Token, error) {
+	now := time.Now()
+	expiresAt := now.Add(j.duration)
+
+	data, err := json.Marshal(payload{
+		Subject:   strconv.Itoa(u.ID),
+		Name:      u.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unsigned := header + "." + encode(data)
+
+	return &entity.Token{
+		Value:     unsigned + "." + j.sign(unsigned),
+		ExpiresAt: time.Unix(expiresAt.Unix(), 0),
+	}, nil
+}
+
+// Parse verifies the token signature and expiration and returns its claims
+func (j *jwtService) Parse(token string) (*entity.Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return nil, ErrMalformedToken
+	}
+
+	signature := j.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(signature), []byte(parts[2])) {
+		return nil, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	userID, err := strconv.Atoi(p.Subject)
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	claims := &entity.Claims{
+		UserID:    userID,
+		Username:  p.Name,
+		IssuedAt:  time.Unix(p.IssuedAt, 0),
+		ExpiresAt: time.Unix(p.ExpiresAt, 0),
+	}
+
+	if !time.Now().Before(claims.ExpiresAt) {
+		return nil, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+// sign returns the HMAC-SHA256 signature of the unsigned token
+func (j *jwtService) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(unsigned))
+
+	return encode(mac.Sum(nil))
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
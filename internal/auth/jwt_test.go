@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+func TestIssueAndParse(t *testing.T) {
+	service := NewJWTService("secret", time.Hour)
+	user := &entity.User{ID: 1, Username: "luigi"}
+
+	token, err := service.Issue(user)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(token.Value, "."), 3)
+
+	claims, err := service.Parse(token.Value)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, user.Username, claims.Username)
+	assert.Equal(t, token.ExpiresAt, claims.ExpiresAt)
+}
+
+func TestParse(t *testing.T) {
+	user := &entity.User{ID: 1, Username: "luigi"}
+
+	valid, err := NewJWTService("secret", time.Hour).Issue(user)
+	assert.NoError(t, err)
+
+	expired, err := NewJWTService("secret", -time.Hour).Issue(user)
+	assert.NoError(t, err)
+
+	otherSecret, err := NewJWTService("other", time.Hour).Issue(user)
+	assert.NoError(t, err)
+
+	parts := strings.Split(valid.Value, ".")
+
+	tests := map[string]struct {
+		token string
+		want  error
+	}{
+		"OK": {
+			token: valid.Value,
+			want:  nil,
+		},
+		"Expired": {
+			token: expired.Value,
+			want:  ErrExpiredToken,
+		},
+		"Wrong Secret": {
+			token: otherSecret.Value,
+			want:  ErrInvalidToken,
+		},
+		"Tampered Payload": {
+			token: parts[0] + "." + encode([]byte(`{"sub":"2","exp":9999999999}`)) + "." + parts[2],
+			want:  ErrInvalidToken,
+		},
+		"Other Algorithm": {
+			token: encode([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + ".",
+			want:  ErrMalformedToken,
+		},
+		"Malformed": {
+			token: "not-a-token",
+			want:  ErrMalformedToken,
+		},
+	}
+	service := NewJWTService("secret", time.Hour)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Parse(tc.token)
+			assert.Equal(t, tc.want, err)
+		})
+	}
+}
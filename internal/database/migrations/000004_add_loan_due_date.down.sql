@@ -0,0 +1,3 @@
+ALTER TABLE "loans" DROP COLUMN IF EXISTS "returned_at";
+
+ALTER TABLE "loans" DROP COLUMN IF EXISTS "due_date";
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE "loans" ADD COLUMN "due_date" timestamp;
+
+ALTER TABLE "loans" ADD COLUMN "returned_at" timestamp;
+
+UPDATE "loans" SET "due_date" = "created_at" + interval '14 days';
+
+ALTER TABLE "loans" ALTER COLUMN "due_date" SET NOT NULL;
+
+CREATE INDEX ON "loans" ("due_date");
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE "loans" DROP COLUMN IF EXISTS "renewed_at";
+
+ALTER TABLE "loans" DROP COLUMN IF EXISTS "renewals";
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE "loans" ADD COLUMN "renewals" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "loans" ADD COLUMN "renewed_at" timestamp;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "holds" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "book_id" int NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'waiting',
+  "expires_at" timestamp,
+  "updated_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "holds" ("user_id");
+
+CREATE INDEX ON "holds" ("book_id", "status");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("book_id") REFERENCES "books" ("id");
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "ledger_entries" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "loan_id" int,
+  "kind" varchar NOT NULL,
+  "amount" int NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "ledger_entries" ("user_id");
+
+ALTER TABLE "ledger_entries" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "ledger_entries" ADD FOREIGN KEY ("loan_id") REFERENCES "loans" ("id");
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "category" varchar NOT NULL DEFAULT 'guest';
\ No newline at end of file
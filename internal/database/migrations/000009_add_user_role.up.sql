@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'patron';
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "reset_tokens" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" int NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "used_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "reset_tokens" ("user_id");
+
+ALTER TABLE "reset_tokens" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
\ No newline at end of file
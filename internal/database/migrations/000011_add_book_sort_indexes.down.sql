@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "books_created_at_id_idx";
+
+DROP INDEX IF EXISTS "books_author_id_idx";
+
+DROP INDEX IF EXISTS "books_title_id_idx";
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE INDEX "books_title_id_idx" ON "books" ("title", "id");
+
+CREATE INDEX "books_author_id_idx" ON "books" ("author", "id");
+
+CREATE INDEX "books_created_at_id_idx" ON "books" ("created_at", "id");
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "books_search_vector_idx";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "search_vector";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "description";
\ No newline at end of file
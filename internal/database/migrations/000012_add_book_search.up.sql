@@ -0,0 +1,9 @@
+ALTER TABLE "books" ADD COLUMN "description" text NOT NULL DEFAULT '';
+
+ALTER TABLE "books" ADD COLUMN "search_vector" tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('simple', "title"), 'A') ||
+  setweight(to_tsvector('simple', "author"), 'B') ||
+  setweight(to_tsvector('simple', "description"), 'C')
+) STORED;
+
+CREATE INDEX "books_search_vector_idx" ON "books" USING GIN ("search_vector");
\ No newline at end of file
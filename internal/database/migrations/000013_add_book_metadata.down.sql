@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS "books_isbn_idx";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "cover_url";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "pages";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "language";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "published_year";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "publisher";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "isbn";
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE "books" ADD COLUMN "isbn" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "books" ADD COLUMN "publisher" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "books" ADD COLUMN "published_year" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "books" ADD COLUMN "language" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "books" ADD COLUMN "pages" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "books" ADD COLUMN "cover_url" varchar NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX "books_isbn_idx" ON "books" ("isbn") WHERE "isbn" <> '';
\ No newline at end of file
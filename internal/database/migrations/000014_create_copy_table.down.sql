@@ -0,0 +1,9 @@
+ALTER TABLE "books" ADD COLUMN "amount" int NOT NULL DEFAULT 0;
+
+UPDATE "books" SET "amount" = (SELECT COUNT(*) FROM "copies" WHERE "copies"."book_id" = "books"."id" AND "copies"."status" = 'available');
+
+ALTER TABLE "books" ALTER COLUMN "amount" DROP DEFAULT;
+
+ALTER TABLE "loans" DROP COLUMN IF EXISTS "copy_id";
+
+DROP TABLE IF EXISTS "copies";
\ No newline at end of file
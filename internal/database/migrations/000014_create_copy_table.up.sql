@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS "copies" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "book_id" int NOT NULL,
+  "barcode" varchar UNIQUE NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'available',
+  "location" varchar NOT NULL DEFAULT '',
+  "updated_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "copies" ("book_id", "status");
+
+ALTER TABLE "copies" ADD FOREIGN KEY ("book_id") REFERENCES "books" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "loans" ADD COLUMN "copy_id" int;
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("copy_id") REFERENCES "copies" ("id") ON DELETE SET NULL;
+
+INSERT INTO "copies" ("book_id", "barcode")
+SELECT "books"."id", 'B' || "books"."id" || '-' || n FROM "books", generate_series(1, "books"."amount") AS n;
+
+INSERT INTO "copies" ("book_id", "barcode", "status")
+SELECT "book_id", 'L' || "id", 'on_loan' FROM "loans" WHERE "is_returned" = false;
+
+UPDATE "loans" SET "copy_id" = "copies"."id" FROM "copies" WHERE "copies"."barcode" = 'L' || "loans"."id";
+
+ALTER TABLE "books" DROP COLUMN "amount";
\ No newline at end of file
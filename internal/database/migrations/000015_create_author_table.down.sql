@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "book_authors";
+
+DROP TABLE IF EXISTS "authors";
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS "authors" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "bio" text NOT NULL DEFAULT '',
+  "updated_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "authors" ("name");
+
+CREATE TABLE IF NOT EXISTS "book_authors" (
+  "book_id" int NOT NULL,
+  "author_id" int NOT NULL,
+  "role" varchar NOT NULL DEFAULT 'author',
+  "position" int NOT NULL,
+  PRIMARY KEY ("book_id", "position"),
+  UNIQUE ("book_id", "author_id", "role")
+);
+
+CREATE INDEX ON "book_authors" ("author_id");
+
+ALTER TABLE "book_authors" ADD FOREIGN KEY ("book_id") REFERENCES "books" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "book_authors" ADD FOREIGN KEY ("author_id") REFERENCES "authors" ("id") ON DELETE RESTRICT;
+
+INSERT INTO "authors" ("name")
+SELECT DISTINCT "author" FROM "books";
+
+INSERT INTO "book_authors" ("book_id", "author_id", "position")
+SELECT "books"."id", "authors"."id", 1 FROM "books" JOIN "authors" ON "authors"."name" = "books"."author";
\ No newline at end of file
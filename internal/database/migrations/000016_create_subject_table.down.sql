@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "book_subjects";
+
+DROP TABLE IF EXISTS "subjects";
\ No newline at end of file
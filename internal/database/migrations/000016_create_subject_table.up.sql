@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS "subjects" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "parent_id" int,
+  "updated_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "subjects_name_idx" ON "subjects" (COALESCE("parent_id", 0), LOWER("name"));
+
+CREATE INDEX ON "subjects" ("parent_id");
+
+ALTER TABLE "subjects" ADD FOREIGN KEY ("parent_id") REFERENCES "subjects" ("id") ON DELETE RESTRICT;
+
+CREATE TABLE IF NOT EXISTS "book_subjects" (
+  "book_id" int NOT NULL,
+  "subject_id" int NOT NULL,
+  PRIMARY KEY ("book_id", "subject_id")
+);
+
+CREATE INDEX ON "book_subjects" ("subject_id");
+
+ALTER TABLE "book_subjects" ADD FOREIGN KEY ("book_id") REFERENCES "books" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "book_subjects" ADD FOREIGN KEY ("subject_id") REFERENCES "subjects" ("id") ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "inventory_movements" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "book_id" int NOT NULL,
+  "user_id" int,
+  "kind" varchar NOT NULL,
+  "quantity" int NOT NULL,
+  "reason" varchar NOT NULL,
+  "barcodes" varchar[] NOT NULL DEFAULT '{}',
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "inventory_movements" ("book_id", "created_at");
+
+ALTER TABLE "inventory_movements" ADD FOREIGN KEY ("book_id") REFERENCES "books" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "inventory_movements" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
\ No newline at end of file
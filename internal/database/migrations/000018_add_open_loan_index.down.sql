@@ -0,0 +1,3 @@
+-- the duplicate open loans closed by the up migration stay returned, they can't
+-- be told apart from loans returned at the same time
+DROP INDEX IF EXISTS "loans_open_user_book_idx";
@@ -0,0 +1,16 @@
+-- a patron could borrow the same book twice before open loans were checked in the
+-- borrow transaction, the oldest open loan is kept and the newer ones are closed
+-- with their copies back on the shelves
+WITH "duplicates" AS (
+  UPDATE "loans" AS "l" SET "is_returned" = true, "returned_at" = now()
+  WHERE "l"."is_returned" = false AND EXISTS (
+    SELECT 1 FROM "loans" AS "o"
+    WHERE "o"."user_id" = "l"."user_id" AND "o"."book_id" = "l"."book_id" AND "o"."is_returned" = false
+    AND ("o"."created_at", "o"."id") < ("l"."created_at", "l"."id")
+  )
+  RETURNING "l"."copy_id"
+)
+UPDATE "copies" SET "status" = 'available', "updated_at" = now()
+WHERE "status" = 'on_loan' AND "id" IN (SELECT "copy_id" FROM "duplicates");
+
+CREATE UNIQUE INDEX "loans_open_user_book_idx" ON "loans" ("user_id", "book_id") WHERE "is_returned" = false;
@@ -0,0 +1,3 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "version";
+
+ALTER TABLE "books" DROP COLUMN IF EXISTS "version";
\ No newline at end of file
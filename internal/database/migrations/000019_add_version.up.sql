@@ -0,0 +1,3 @@
+ALTER TABLE "books" ADD COLUMN "version" int NOT NULL DEFAULT 1;
+
+ALTER TABLE "users" ADD COLUMN "version" int NOT NULL DEFAULT 1;
\ No newline at end of file
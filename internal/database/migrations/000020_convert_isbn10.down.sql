@@ -0,0 +1,2 @@
+-- the converted ISBNs stay ISBN-13, they can't be told apart from ISBN-13 sent as such
+SELECT 1;
@@ -0,0 +1,11 @@
+-- books store the ISBN-13 of an ISBN-10 so imports and lookups find them whichever
+-- form is sent, an ISBN-10 whose ISBN-13 is already on another book is left as it is
+UPDATE "books" SET "isbn" = "converted"."isbn", "version" = "books"."version" + 1, "updated_at" = now()
+FROM (
+  SELECT "id", "prefix" || (10 - (
+    SELECT SUM(substr("prefix", i, 1)::int * CASE WHEN i % 2 = 1 THEN 1 ELSE 3 END) FROM generate_series(1, 12) AS i
+  ) % 10) % 10 AS "isbn"
+  FROM (SELECT "id", '978' || left("isbn", 9) AS "prefix" FROM "books" WHERE length("isbn") = 10) AS "isbn10"
+) AS "converted"
+WHERE "books"."id" = "converted"."id"
+AND NOT EXISTS (SELECT 1 FROM "books" AS "other" WHERE "other"."isbn" = "converted"."isbn");
@@ -0,0 +1,3 @@
+-- the duplicate holds cancelled by the up migration stay cancelled, they can't
+-- be told apart from holds the patrons cancelled
+DROP INDEX IF EXISTS "holds_active_user_book_idx";
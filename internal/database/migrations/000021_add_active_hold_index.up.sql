@@ -0,0 +1,10 @@
+-- a patron could queue up twice for the same book when two holds were placed at
+-- the same time, the oldest active hold is kept and the newer ones are cancelled
+UPDATE "holds" AS "h" SET "status" = 'cancelled', "updated_at" = now()
+WHERE "h"."status" IN ('waiting', 'ready') AND EXISTS (
+  SELECT 1 FROM "holds" AS "o"
+  WHERE "o"."user_id" = "h"."user_id" AND "o"."book_id" = "h"."book_id" AND "o"."status" IN ('waiting', 'ready')
+  AND ("o"."created_at", "o"."id") < ("h"."created_at", "h"."id")
+);
+
+CREATE UNIQUE INDEX "holds_active_user_book_idx" ON "holds" ("user_id", "book_id") WHERE "status" IN ('waiting', 'ready');
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+const foreignKeyViolationCode = "23503"
+
+type authorRepository struct {
+	db *sql.DB
+}
+
+// NewAuthorRepository creates a new instance of authorRepository
+func NewAuthorRepository(db *sql.DB) ports.AuthorRepository {
+	return &authorRepository{
+		db: db,
+	}
+}
+
+// Get gets author data by id
+func (r *authorRepository) Get(ctx context.Context, id int) (*entity.Author, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM authors WHERE id = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, id)
+
+	a, err := scanAuthor(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrAuthorNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return a, nil
+}
+
+// List lists the authors in name order
+func (r *authorRepository) List(ctx context.Context) ([]*entity.Author, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM authors ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	authors := []*entity.Author{}
+	for rows.Next() {
+		a, err := scanAuthor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		authors = append(authors, a)
+	}
+
+	return authors, nil
+}
+
+// Create creates a new author
+func (r *authorRepository) Create(ctx context.Context, a *entity.Author) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO authors (name, bio) VALUES ($1, $2) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, a.Name, a.Bio).Scan(&a.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return a.ID, nil
+}
+
+// Update updates the name and bio of an author
+func (r *authorRepository) Update(ctx context.Context, a *entity.Author) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE authors SET name = $1, bio = $2, updated_at = NOW() WHERE id = $3")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, a.Name, a.Bio, a.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes an author by id, authors credited on books can't be deleted
+func (r *authorRepository) Delete(ctx context.Context, id int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "DELETE FROM authors WHERE id = $1")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == foreignKeyViolationCode {
+			return ports.ErrAuthorHasBooks
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+// scanAuthor scans an authors row into an author entity
+func scanAuthor(s scanner) (*entity.Author, error) {
+	var a entity.Author
+	var updatedAt sql.NullTime
+
+	err := s.Scan(&a.ID, &a.Name, &a.Bio, &updatedAt, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if updatedAt is not NULL
+	if updatedAt.Valid {
+		a.UpdatedAt = updatedAt.Time
+	}
+
+	return &a, nil
+}
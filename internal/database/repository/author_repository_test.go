@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuthorRepository(db)
+
+	author := &entity.Author{
+		ID:        1,
+		Name:      "Alex Edwards",
+		Bio:       "Go developer and writer",
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "bio", "updated_at", "created_at"}).
+			AddRow(author.ID, author.Name, author.Bio, nil, author.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM authors WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(author.ID).
+			WillReturnRows(rows)
+
+		gotAuthor, err := repo.Get(context.Background(), author.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, author, gotAuthor)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM authors WHERE id = \\$1").
+			WillReturnError(sql.ErrConnDone)
+
+		gotAuthor, err := repo.Get(context.Background(), author.ID)
+		assert.Error(t, err)
+		assert.Nil(t, gotAuthor)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM authors WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+
+		gotAuthor, err := repo.Get(context.Background(), 10)
+		assert.Equal(t, ports.ErrAuthorNotFound, err)
+		assert.Nil(t, gotAuthor)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListAuthors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuthorRepository(db)
+
+	authors := []*entity.Author{
+		{ID: 2, Name: "Alan Donovan", CreatedAt: time.Now()},
+		{ID: 1, Name: "Brian Kernighan", CreatedAt: time.Now()},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "bio", "updated_at", "created_at"}).
+			AddRow(authors[0].ID, authors[0].Name, authors[0].Bio, nil, authors[0].CreatedAt).
+			AddRow(authors[1].ID, authors[1].Name, authors[1].Bio, nil, authors[1].CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM authors ORDER BY name, id").
+			ExpectQuery().
+			WillReturnRows(rows)
+
+		gotAuthors, err := repo.List(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, authors, gotAuthors)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM authors").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotAuthors, err := repo.List(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, gotAuthors)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuthorRepository(db)
+
+	author := &entity.Author{Name: "Alex Edwards"}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO authors").
+			ExpectQuery().
+			WithArgs(author.Name, author.Bio).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		id, err := repo.Create(context.Background(), author)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO authors").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		id, err := repo.Create(context.Background(), author)
+		assert.Error(t, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuthorRepository(db)
+
+	author := &entity.Author{ID: 1, Name: "Alex Edwards", Bio: "Author of Let's Go"}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE authors SET name = \\$1, bio = \\$2, updated_at = NOW\\(\\) WHERE id = \\$3").
+			ExpectExec().
+			WithArgs(author.Name, author.Bio, author.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), author)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE authors").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Update(context.Background(), author)
+		assert.Equal(t, ports.ErrAuthorNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuthorRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM authors WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Delete(context.Background(), 1)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Has Books", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM authors WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(1).
+			WillReturnError(&pq.Error{Code: foreignKeyViolationCode})
+
+		err := repo.Delete(context.Background(), 1)
+		assert.Equal(t, ports.ErrAuthorHasBooks, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM authors WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(10).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Delete(context.Background(), 10)
+		assert.Equal(t, ports.ErrAuthorNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
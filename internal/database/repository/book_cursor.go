@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+// bookCursor points right after the last book of a page, it is tied to the sort
+// so the next page is read with the same order
+type bookCursor struct {
+	Sort  entity.BookSort `json:"s"`
+	Value string          `json:"v"`
+	ID    int             `json:"id"`
+}
+
+// encodeBookCursor creates an opaque cursor for the page after the book
+func encodeBookCursor(sort entity.BookSort, b *entity.Book) string {
+	c := bookCursor{Sort: sort, ID: b.ID}
+
+	switch sort.Field() {
+	case entity.BookSortTitle:
+		c.Value = b.Title
+	case entity.BookSortAuthor:
+		c.Value = b.Author
+	case entity.BookSortCreatedAt:
+		c.Value = b.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeBookCursor reads a cursor created for the same sort
+func decodeBookCursor(cursor string, sort entity.BookSort) (*bookCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ports.ErrInvalidCursor
+	}
+
+	var c bookCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sort {
+		return nil, ports.ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// sortValue returns the cursor value with the type of the sorted column
+func (c *bookCursor) sortValue() (any, error) {
+	if c.Sort.Field() != entity.BookSortCreatedAt {
+		return c.Value, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return nil, ports.ErrInvalidCursor
+	}
+
+	return t, nil
+}
@@ -3,18 +3,29 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"html"
+	"strings"
+
+	"github.com/lib/pq"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
-	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
+// bookColumns lists the books columns read into a book, in scanBook order,
+// the amount counts the available copies of the book
+const bookColumns = "id, title, author, " +
+	"(SELECT COUNT(*) FROM copies WHERE copies.book_id = books.id AND copies.status = 'available') AS amount, " +
+	"updated_at, created_at, description, isbn, publisher, published_year, language, pages, cover_url, version"
+
 type bookRepository struct {
 	db *sql.DB
 }
 
 // NewBookRepository creates a new instance of BookRepository
-func NewBookRepository(db *sql.DB) r.BookRepository {
+func NewBookRepository(db *sql.DB) ports.BookRepository {
 	return &bookRepository{
 		db: db,
 	}
@@ -22,160 +33,767 @@ func NewBookRepository(db *sql.DB) r.BookRepository {
 
 // Get gets book data by id
 func (r *bookRepository) Get(ctx context.Context, id int) (*entity.Book, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT * FROM books WHERE id = $1")
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT "+bookColumns+" FROM books WHERE id = $1")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	b := &entity.Book{}
-
 	row := stmt.QueryRowContext(ctx, id)
 
-	var updatedAt sql.NullTime
-	err = row.Scan(&b.ID, &b.Title, &b.Author, &b.Amount, &updatedAt, &b.CreatedAt)
+	b, err := scanBook(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, ErrBookNotFound
+			return nil, ports.ErrBookNotFound
 		} else {
 			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
 	}
 
-	// check if updatedAt is not NULL
-	if updatedAt.Valid {
-		b.UpdatedAt = updatedAt.Time
+	b.Authors, err = r.listAuthors(ctx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Subjects, err = r.listSubjects(ctx, b.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	return b, nil
 }
 
-// List list all books in the database
-func (r *bookRepository) List(ctx context.Context) ([]*entity.Book, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT * FROM books")
+// listAuthors lists the authors credited on a book in the order they are credited
+func (r *bookRepository) listAuthors(ctx context.Context, bookID int) ([]entity.BookAuthor, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT book_authors.author_id, authors.name, book_authors.role FROM book_authors "+
+		"JOIN authors ON authors.id = book_authors.author_id WHERE book_authors.book_id = $1 ORDER BY book_authors.position")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx)
+	rows, err := stmt.QueryContext(ctx, bookID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
 	}
+	defer rows.Close()
 
-	var books []*entity.Book
+	authors := []entity.BookAuthor{}
 	for rows.Next() {
-		var b entity.Book
-		var updatedAt sql.NullTime
+		var a entity.BookAuthor
 
-		err = rows.Scan(&b.ID, &b.Title, &b.Author, &b.Amount, &updatedAt, &b.CreatedAt)
+		err := rows.Scan(&a.AuthorID, &a.Name, &a.Role)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
 
-		// check if updatedAt is not NULL
-		if updatedAt.Valid {
-			b.UpdatedAt = updatedAt.Time
-		}
+		authors = append(authors, a)
+	}
 
-		books = append(books, &b)
+	return authors, nil
+}
+
+// listSubjects lists the subjects a book is tagged with in name order
+func (r *bookRepository) listSubjects(ctx context.Context, bookID int) ([]entity.Subject, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT subjects.* FROM book_subjects "+
+		"JOIN subjects ON subjects.id = book_subjects.subject_id WHERE book_subjects.book_id = $1 ORDER BY subjects.name, subjects.id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
+	defer stmt.Close()
 
-	if len(books) == 0 {
-		return nil, ErrBookNotFound
+	rows, err := stmt.QueryContext(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
 	}
+	defer rows.Close()
 
-	return books, nil
+	subjects := []entity.Subject{}
+	for rows.Next() {
+		s, err := scanSubject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		subjects = append(subjects, *s)
+	}
+
+	return subjects, nil
 }
 
-// Search searches books matching the sent query
-func (r *bookRepository) Search(ctx context.Context, query string) ([]*entity.Book, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT * FROM books WHERE LOWER(title) LIKE LOWER($1)")
+// List lists a page of books matching the query filters in the query order,
+// pages are read with keyset pagination so they stay fast deep into the catalog
+func (r *bookRepository) List(ctx context.Context, q entity.BookQuery) (*entity.BookPage, error) {
+	var conditions []string
+	var args []any
+
+	if q.Query != "" {
+		args = append(args, "%"+q.Query+"%")
+		conditions = append(conditions, fmt.Sprintf("(LOWER(title) LIKE LOWER($%d) OR LOWER(author) LIKE LOWER($%d))", len(args), len(args)))
+	}
+	if q.Title != "" {
+		args = append(args, "%"+q.Title+"%")
+		conditions = append(conditions, fmt.Sprintf("LOWER(title) LIKE LOWER($%d)", len(args)))
+	}
+	if q.Author != "" {
+		args = append(args, "%"+q.Author+"%")
+		conditions = append(conditions, fmt.Sprintf("LOWER(author) LIKE LOWER($%d)", len(args)))
+	}
+	if q.AuthorID != 0 {
+		args = append(args, q.AuthorID)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id AND book_authors.author_id = $%d)", len(args)))
+	}
+	if q.SubjectID != 0 {
+		// the subject tree holds the subject and every subject below it
+		args = append(args, q.SubjectID)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND book_subjects.subject_id IN "+
+			"(WITH RECURSIVE tree AS (SELECT id FROM subjects WHERE id = $%d UNION ALL SELECT subjects.id FROM subjects JOIN tree ON subjects.parent_id = tree.id) "+
+			"SELECT id FROM tree))", len(args)))
+	}
+	if q.Available {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM copies WHERE copies.book_id = books.id AND copies.status = 'available')")
+	}
+
+	var cursor *bookCursor
+	var cursorValue any
+	if q.Cursor != "" {
+		c, err := decodeBookCursor(q.Cursor, q.Sort)
+		if err != nil {
+			return nil, err
+		}
+
+		cursorValue, err = c.sortValue()
+		if err != nil {
+			return nil, err
+		}
+
+		cursor = c
+	}
+
+	// the total ignores the cursor, it counts every page
+	total, err := r.count(ctx, where(conditions), args)
+	if err != nil {
+		return nil, err
+	}
+
+	column := bookSortColumn(q.Sort)
+	direction, operator := "ASC", ">"
+	if q.Sort.Descending() {
+		direction, operator = "DESC", "<"
+	}
+
+	if cursor != nil {
+		args = append(args, cursorValue, cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, operator, len(args)-1, len(args)))
+	}
+
+	// one extra row tells whether there is a next page
+	args = append(args, q.Limit+1)
+	query := fmt.Sprintf("SELECT %s FROM books%s ORDER BY %s %s, id %s LIMIT $%d", bookColumns, where(conditions), column, direction, direction, len(args))
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx, "%"+query+"%")
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
 	}
+	defer rows.Close()
 
-	var books []*entity.Book
+	page := &entity.BookPage{Books: []*entity.Book{}, Total: total}
 	for rows.Next() {
-		var b entity.Book
-		var updatedAt sql.NullTime
-
-		err = rows.Scan(&b.ID, &b.Title, &b.Author, &b.Amount, &updatedAt, &b.CreatedAt)
+		b, err := scanBook(rows)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
 
-		// check if updatedAt is not NULL
-		if updatedAt.Valid {
-			b.UpdatedAt = updatedAt.Time
+		page.Books = append(page.Books, b)
+	}
+
+	if len(page.Books) > q.Limit {
+		page.Books = page.Books[:q.Limit]
+		page.NextCursor = encodeBookCursor(q.Sort, page.Books[q.Limit-1])
+	}
+
+	return page, nil
+}
+
+// Export lists the next batch of books after the book id in id order, along
+// with their authors and subjects. The export walks the catalog a batch at a
+// time with the last id of a batch as the cursor of the next one
+func (r *bookRepository) Export(ctx context.Context, afterID, limit int) ([]*entity.Book, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT "+bookColumns+" FROM books WHERE id > $1 ORDER BY id LIMIT $2")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	books := []*entity.Book{}
+	byID := make(map[int]*entity.Book)
+	for rows.Next() {
+		b, err := scanBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
 
-		books = append(books, &b)
+		b.Authors = []entity.BookAuthor{}
+		b.Subjects = []entity.Subject{}
+		books = append(books, b)
+		byID[b.ID] = b
 	}
 
 	if len(books) == 0 {
-		return nil, ErrBookNotFound
+		return books, nil
+	}
+
+	ids := make([]int64, 0, len(books))
+	for _, b := range books {
+		ids = append(ids, int64(b.ID))
+	}
+
+	if err := r.exportAuthors(ctx, ids, byID); err != nil {
+		return nil, err
+	}
+
+	if err := r.exportSubjects(ctx, ids, byID); err != nil {
+		return nil, err
 	}
 
 	return books, nil
 }
 
-// Create creates a new book
-func (r *bookRepository) Create(ctx context.Context, b *entity.Book) (int, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO books (title, author, amount) VALUES ($1, $2, $3) RETURNING id")
+// exportAuthors credits the authors of a batch of books in the order they are credited
+func (r *bookRepository) exportAuthors(ctx context.Context, ids []int64, books map[int]*entity.Book) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT book_authors.book_id, book_authors.author_id, authors.name, book_authors.role FROM book_authors "+
+		"JOIN authors ON authors.id = book_authors.author_id WHERE book_authors.book_id = ANY($1) ORDER BY book_authors.book_id, book_authors.position")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookID int
+		var a entity.BookAuthor
+
+		err := rows.Scan(&bookID, &a.AuthorID, &a.Name, &a.Role)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		if b, ok := books[bookID]; ok {
+			b.Authors = append(b.Authors, a)
+		}
+	}
+
+	return nil
+}
+
+// exportSubjects tags a batch of books with their subjects in name order
+func (r *bookRepository) exportSubjects(ctx context.Context, ids []int64, books map[int]*entity.Book) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT book_subjects.book_id, subjects.id, subjects.name FROM book_subjects "+
+		"JOIN subjects ON subjects.id = book_subjects.subject_id WHERE book_subjects.book_id = ANY($1) ORDER BY book_subjects.book_id, subjects.name, subjects.id")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookID int
+		var s entity.Subject
+
+		err := rows.Scan(&bookID, &s.ID, &s.Name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		if b, ok := books[bookID]; ok {
+			b.Subjects = append(b.Subjects, s)
+		}
+	}
+
+	return nil
+}
+
+// count counts the books matching the where clause
+func (r *bookRepository) count(ctx context.Context, where string, args []any) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT COUNT(*) FROM books"+where)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRowContext(ctx, b.Title, b.Author, b.Amount).Scan(&b.ID)
+	var total int
+
+	err = stmt.QueryRowContext(ctx, args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return total, nil
+}
+
+// the snippets mark the matched words with control characters that can't be
+// typed in a book, they become <mark> tags once the rest of the text is escaped
+const (
+	headlineStart = "\x02"
+	headlineStop  = "\x03"
+)
+
+// Search searches the words of the query in the title, author and description,
+// a book matches when it has every word or a word starting with it, the best
+// ranked matches come first with an HTML snippet highlighting the matched words
+func (r *bookRepository) Search(ctx context.Context, s entity.BookSearch) ([]*entity.BookMatch, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT "+bookColumns+", ts_rank(search_vector, query) AS rank, "+
+		"ts_headline('simple', concat_ws(' - ', title, author, NULLIF(description, '')), query, $3) "+
+		"FROM books, to_tsquery('simple', $1) AS query WHERE search_vector @@ query ORDER BY rank DESC, id LIMIT $2")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, prefixTSQuery(s.Terms()), s.Limit, "StartSel="+headlineStart+", StopSel="+headlineStop)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	var matches []*entity.BookMatch
+	for rows.Next() {
+		var m entity.BookMatch
+
+		m.Book, err = scanBook(rows, &m.Rank, &m.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+		m.Snippet = highlight(m.Snippet)
+
+		matches = append(matches, &m)
+	}
+
+	if len(matches) == 0 {
+		return nil, ports.ErrBookNotFound
+	}
+
+	return matches, nil
+}
+
+// Create creates a new book along with its first copies, its authors and subjects
+func (r *bookRepository) Create(ctx context.Context, b *entity.Book) (int, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+	}
+
+	err = insertBook(ctx, tx, b)
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return 0, fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return 0, err
+	}
+
+	err = insertBookAuthors(ctx, tx, b.ID, b.Authors)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return 0, fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return 0, err
+	}
+
+	err = insertBookSubjects(ctx, tx, b.ID, b.Subjects)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return 0, fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrCommit, err)
 	}
 
 	return b.ID, nil
 }
 
-// Update updates a book
+// Update updates a book, the amount is left to the copies and the authors and
+// subjects are replaced only when the book has a list of them, even an empty one
 func (r *bookRepository) Update(ctx context.Context, b *entity.Book) error {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE books SET title = $1, author = $2, amount = $3, updated_at = NOW() WHERE id = $4")
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
-		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+	}
+
+	err = updateBook(ctx, tx, b)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return err
 	}
-	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, b.Title, b.Author, b.Amount, b.ID)
+	if b.Authors != nil {
+		err = replaceBookAuthors(ctx, tx, b.ID, b.Authors)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+			}
+			return err
+		}
+	}
+
+	if b.Subjects != nil {
+		err = replaceBookSubjects(ctx, tx, b.ID, b.Subjects)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", ErrCommit, err)
+	}
+
+	return nil
+}
+
+// Patch writes the columns of the fields of the patch, taking their values
+// from the patched book, and replaces the authors or subjects it holds
+func (r *bookRepository) Patch(ctx context.Context, b *entity.Book, p *entity.BookPatch) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+	}
+
+	err = patchBook(ctx, tx, b, p)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return err
+	}
+
+	if p.Authors != nil {
+		err = replaceBookAuthors(ctx, tx, b.ID, b.Authors)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+			}
+			return err
+		}
+	}
+
+	if p.Subjects != nil {
+		err = replaceBookSubjects(ctx, tx, b.ID, b.Subjects)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", ErrCommit, err)
+	}
+
+	return nil
+}
+
+// Import creates or updates the books of the rows in a single transaction and
+// sets the status of every row. Rows with the ISBN of a book in the catalog
+// update the columns they supply and keep its copies, a row the database
+// rejects is rolled back alone and the other rows are still imported
+func (r *bookRepository) Import(ctx context.Context, rows []*entity.BookImportRow) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+	}
+
+	for _, row := range rows {
+		err := importRow(ctx, tx, row)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", ErrCommit, err)
+	}
+
+	return nil
+}
+
+// importRow imports a row behind a savepoint, a row breaking a constraint is
+// rolled back to it and rejected, the other errors fail the whole import
+func importRow(ctx context.Context, tx querier, row *entity.BookImportRow) error {
+	_, err := tx.ExecContext(ctx, "SAVEPOINT import_row")
 	if err != nil {
 		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	status, err := importBook(ctx, tx, row.Book, row.Fields)
 	if err != nil {
-		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+		if !rowRejected(err) {
+			return err
+		}
+
+		_, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row")
+		if rbErr != nil {
+			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+
+		row.Reject(err)
+		return nil
 	}
 
-	if rowsAffected == 0 {
-		return ErrBookNotFound
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	row.Status = status
+	return nil
+}
+
+// importBook updates the fields of the book with the same ISBN or creates the book
+func importBook(ctx context.Context, tx querier, b *entity.Book, p *entity.BookPatch) (entity.BookImportStatus, error) {
+	if b.ISBN != "" {
+		err := tx.QueryRowContext(ctx, "SELECT id, version FROM books WHERE isbn = $1", b.ISBN).Scan(&b.ID, &b.Version)
+		if err != nil && err != sql.ErrNoRows {
+			return "", fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+		}
+
+		if err == nil {
+			if err := patchBook(ctx, tx, b, p); err != nil {
+				return "", err
+			}
+			return entity.BookImportUpdated, nil
+		}
+	}
+
+	if p.Author == nil || b.Amount < 1 {
+		return "", ports.ErrNewBookIncomplete
+	}
+
+	if err := insertBook(ctx, tx, b); err != nil {
+		return "", err
+	}
+
+	return entity.BookImportCreated, nil
+}
+
+// rowRejected reports whether the error of an imported row is caused by the
+// row, like a duplicated ISBN or a value the columns don't accept
+func rowRejected(err error) bool {
+	switch err {
+	case ports.ErrNewBookIncomplete, ports.ErrISBNAlreadyExists, ports.ErrBarcodeAlreadyExists:
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// data exceptions and integrity constraint violations
+		class := pqErr.Code.Class()
+		return class == "22" || class == "23"
+	}
+
+	return false
+}
+
+// insertBook inserts a book along with its first copies, the copies are
+// labelled B<book id>-<n> until the librarians relabel them
+func insertBook(ctx context.Context, tx querier, b *entity.Book) error {
+	err := tx.QueryRowContext(ctx, "INSERT INTO books (title, author, description, isbn, publisher, published_year, language, pages, cover_url) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id",
+		b.Title, b.Author, b.Description, b.ISBN, b.Publisher, b.PublishedYear, b.Language, b.Pages, b.CoverURL).Scan(&b.ID)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrISBNAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO copies (book_id, barcode) SELECT $1::int, 'B' || $1::int || '-' || n FROM generate_series(1, $2::int) AS n", b.ID, b.Amount)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrBarcodeAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
 	}
 
 	return nil
 }
 
-// Delete deletes a book by id
-func (r *bookRepository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM books WHERE id = $1")
+// updateBook updates the columns of a book at the version it was read and
+// moves it to the next version, the amount is left to the copies
+func updateBook(ctx context.Context, tx querier, b *entity.Book) error {
+	err := tx.QueryRowContext(ctx, "UPDATE books SET title = $1, author = $2, description = $3, isbn = $4, publisher = $5, "+
+		"published_year = $6, language = $7, pages = $8, cover_url = $9, version = version + 1, updated_at = NOW() "+
+		"WHERE id = $10 AND version = $11 RETURNING version",
+		b.Title, b.Author, b.Description, b.ISBN, b.Publisher, b.PublishedYear, b.Language, b.Pages, b.CoverURL, b.ID, b.Version).Scan(&b.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return versionMismatch(ctx, tx, "books", b.ID, ports.ErrBookNotFound)
+		}
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrISBNAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// patchBook updates the patched columns of a book at the version it was read
+// and moves it to the next version, even when the patch only holds lists
+func patchBook(ctx context.Context, tx querier, b *entity.Book, p *entity.BookPatch) error {
+	var sets []string
+	var args []any
+
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if p.Title != nil {
+		set("title", b.Title)
+	}
+	if p.Author != nil {
+		set("author", b.Author)
+	}
+	if p.Description != nil {
+		set("description", b.Description)
+	}
+	if p.ISBN != nil {
+		set("isbn", b.ISBN)
+	}
+	if p.Publisher != nil {
+		set("publisher", b.Publisher)
+	}
+	if p.PublishedYear != nil {
+		set("published_year", b.PublishedYear)
+	}
+	if p.Language != nil {
+		set("language", b.Language)
+	}
+	if p.Pages != nil {
+		set("pages", b.Pages)
+	}
+	if p.CoverURL != nil {
+		set("cover_url", b.CoverURL)
+	}
+	sets = append(sets, "version = version + 1", "updated_at = NOW()")
+
+	args = append(args, b.ID, b.Version)
+	query := fmt.Sprintf("UPDATE books SET %s WHERE id = $%d AND version = $%d RETURNING version",
+		strings.Join(sets, ", "), len(args)-1, len(args))
+
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&b.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return versionMismatch(ctx, tx, "books", b.ID, ports.ErrBookNotFound)
+		}
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrISBNAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// replaceBookAuthors replaces the credits of a book with the authors
+func replaceBookAuthors(ctx context.Context, tx querier, bookID int, authors []entity.BookAuthor) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM book_authors WHERE book_id = $1", bookID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	return insertBookAuthors(ctx, tx, bookID, authors)
+}
+
+// replaceBookSubjects replaces the subjects of a book
+func replaceBookSubjects(ctx context.Context, tx querier, bookID int, subjects []entity.Subject) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM book_subjects WHERE book_id = $1", bookID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	return insertBookSubjects(ctx, tx, bookID, subjects)
+}
+
+// insertBookAuthors credits the authors on a book, their position keeps the credit order
+func insertBookAuthors(ctx context.Context, tx querier, bookID int, authors []entity.BookAuthor) error {
+	for i, a := range authors {
+		_, err := tx.ExecContext(ctx, "INSERT INTO book_authors (book_id, author_id, role, position) VALUES ($1, $2, $3, $4)",
+			bookID, a.AuthorID, a.Role, i+1)
+		if err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok && pqErr.Code == foreignKeyViolationCode {
+				return ports.ErrAuthorNotFound
+			}
+			return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+		}
+	}
+
+	return nil
+}
+
+// insertBookSubjects tags a book with the subjects
+func insertBookSubjects(ctx context.Context, tx querier, bookID int, subjects []entity.Subject) error {
+	for _, s := range subjects {
+		_, err := tx.ExecContext(ctx, "INSERT INTO book_subjects (book_id, subject_id) VALUES ($1, $2)", bookID, s.ID)
+		if err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok && pqErr.Code == foreignKeyViolationCode {
+				return ports.ErrSubjectNotFound
+			}
+			return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes a book by id at the version it was read
+func (r *bookRepository) Delete(ctx context.Context, id, version int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "DELETE FROM books WHERE id = $1 AND version = $2")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, id)
+	result, err := stmt.ExecContext(ctx, id, version)
 	if err != nil {
 		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
 	}
@@ -186,8 +804,66 @@ func (r *bookRepository) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return ErrBookNotFound
+		return versionMismatch(ctx, conn(ctx, r.db), "books", id, ports.ErrBookNotFound)
 	}
 
 	return nil
 }
+
+// bookSortColumn maps a sort to its column, only known columns reach the query
+func bookSortColumn(sort entity.BookSort) string {
+	switch sort.Field() {
+	case entity.BookSortAuthor:
+		return "author"
+	case entity.BookSortCreatedAt:
+		return "created_at"
+	default:
+		return "title"
+	}
+}
+
+// prefixTSQuery builds a tsquery where every term must match as a word prefix,
+// the terms only hold letters and digits so they can't inject tsquery operators
+func prefixTSQuery(terms []string) string {
+	for i, t := range terms {
+		terms[i] = t + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// highlight escapes the text of a snippet for HTML and marks its matched words
+func highlight(snippet string) string {
+	return strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// where joins the conditions into a where clause
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// scanBook scans a row selecting the bookColumns, extra columns selected after
+// them are scanned into dest
+func scanBook(s scanner, dest ...any) (*entity.Book, error) {
+	var b entity.Book
+	var updatedAt sql.NullTime
+
+	columns := []any{&b.ID, &b.Title, &b.Author, &b.Amount, &updatedAt, &b.CreatedAt,
+		&b.Description, &b.ISBN, &b.Publisher, &b.PublishedYear, &b.Language, &b.Pages, &b.CoverURL, &b.Version}
+
+	err := s.Scan(append(columns, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if updatedAt is not NULL
+	if updatedAt.Valid {
+		b.UpdatedAt = updatedAt.Time
+	}
+
+	return &b, nil
+}
@@ -3,15 +3,21 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
+// selectBooks matches the select of the book columns
+var selectBooks = regexp.QuoteMeta("SELECT " + bookColumns + " FROM books")
+
 func TestGetBook(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -20,22 +26,48 @@ func TestGetBook(t *testing.T) {
 	repo := NewBookRepository(db)
 
 	book := &entity.Book{
-		ID:        1,
-		Title:     "Let's Go Further!",
-		Author:    "Alex Edwards",
-		Amount:    5,
+		ID:     1,
+		Title:  "Let's Go Further!",
+		Author: "Alex Edwards",
+		Amount: 5,
+		BookMetadata: entity.BookMetadata{
+			ISBN:          "9781234567897",
+			Publisher:     "Alex Edwards",
+			PublishedYear: 2021,
+			Language:      "en",
+			Pages:         583,
+			Description:   "Advanced patterns for building APIs and web applications in Go",
+			CoverURL:      "https://example.com/covers/lets-go-further.jpg",
+		},
+		Authors: []entity.BookAuthor{
+			{AuthorID: 1, Name: "Alex Edwards", Role: entity.AuthorRoleAuthor},
+		},
+		Subjects: []entity.Subject{
+			{ID: 2, Name: "Go", ParentID: 1},
+		},
 		UpdatedAt: time.Time{},
 		CreatedAt: time.Now(),
 	}
 
+	listAuthors := "SELECT book_authors.author_id, authors.name, book_authors.role FROM book_authors"
+	listSubjects := "SELECT subjects.\\* FROM book_subjects JOIN subjects"
+
 	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at"}).
-			AddRow(book.ID, book.Title, book.Author, book.Amount, book.UpdatedAt, book.CreatedAt)
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at", "description", "isbn", "publisher", "published_year", "language", "pages", "cover_url", "version"}).
+			AddRow(book.ID, book.Title, book.Author, book.Amount, book.UpdatedAt, book.CreatedAt, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL, book.Version)
 
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE id = ").
+		mock.ExpectPrepare(selectBooks + " WHERE id = ").
 			ExpectQuery().
 			WithArgs(book.ID).
 			WillReturnRows(rows)
+		mock.ExpectPrepare(listAuthors).
+			ExpectQuery().
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"author_id", "name", "role"}).AddRow(1, "Alex Edwards", "author"))
+		mock.ExpectPrepare(listSubjects).
+			ExpectQuery().
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id", "updated_at", "created_at"}).AddRow(2, "Go", 1, nil, time.Time{}))
 
 		gotBook, err := repo.Get(context.Background(), book.ID)
 		assert.NoError(t, err)
@@ -44,7 +76,7 @@ func TestGetBook(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE id = ").
+		mock.ExpectPrepare(selectBooks + " WHERE id = ").
 			WillReturnError(sql.ErrConnDone)
 
 		gotBook, err := repo.Get(context.Background(), book.ID)
@@ -55,7 +87,7 @@ func TestGetBook(t *testing.T) {
 	})
 
 	t.Run("Query Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE id = ").
+		mock.ExpectPrepare(selectBooks + " WHERE id = ").
 			ExpectQuery().
 			WithArgs(book.ID).
 			WillReturnError(sql.ErrConnDone)
@@ -67,7 +99,7 @@ func TestGetBook(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Not Found", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE id = ").
+		mock.ExpectPrepare(selectBooks + " WHERE id = ").
 			ExpectQuery().
 			WithArgs(book.ID).
 			WillReturnError(sql.ErrNoRows)
@@ -76,6 +108,45 @@ func TestGetBook(t *testing.T) {
 		assert.Error(t, err)
 		assert.Empty(t, gotBook)
 
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Authors Failed", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at", "description", "isbn", "publisher", "published_year", "language", "pages", "cover_url", "version"}).
+			AddRow(book.ID, book.Title, book.Author, book.Amount, book.UpdatedAt, book.CreatedAt, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL, book.Version)
+
+		mock.ExpectPrepare(selectBooks + " WHERE id = ").
+			ExpectQuery().
+			WithArgs(book.ID).
+			WillReturnRows(rows)
+		mock.ExpectPrepare(listAuthors).
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotBook, err := repo.Get(context.Background(), book.ID)
+		assert.Error(t, err)
+		assert.Empty(t, gotBook)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Subjects Failed", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at", "description", "isbn", "publisher", "published_year", "language", "pages", "cover_url", "version"}).
+			AddRow(book.ID, book.Title, book.Author, book.Amount, book.UpdatedAt, book.CreatedAt, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL, book.Version)
+
+		mock.ExpectPrepare(selectBooks + " WHERE id = ").
+			ExpectQuery().
+			WithArgs(book.ID).
+			WillReturnRows(rows)
+		mock.ExpectPrepare(listAuthors).
+			ExpectQuery().
+			WillReturnRows(sqlmock.NewRows([]string{"author_id", "name", "role"}))
+		mock.ExpectPrepare(listSubjects).
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotBook, err := repo.Get(context.Background(), book.ID)
+		assert.Error(t, err)
+		assert.Empty(t, gotBook)
+
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -102,59 +173,241 @@ func TestListBook(t *testing.T) {
 			Amount:    10,
 			UpdatedAt: time.Time{},
 			CreatedAt: time.Now(),
+		}, {
+			ID:        3,
+			Title:     "Learning Go",
+			Author:    "Jon Bodner",
+			Amount:    1,
+			UpdatedAt: time.Time{},
+			CreatedAt: time.Now(),
 		},
 	}
 
-	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at"})
+	bookRows := func(books []*entity.Book) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at", "description", "isbn", "publisher", "published_year", "language", "pages", "cover_url", "version"})
 		for _, book := range books {
-			rows = rows.AddRow(book.ID, book.Title, book.Author, book.Amount, book.UpdatedAt, book.CreatedAt)
+			rows = rows.AddRow(book.ID, book.Title, book.Author, book.Amount, nil, book.CreatedAt, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL, book.Version)
 		}
+		return rows
+	}
+
+	var cursor string
 
-		mock.ExpectPrepare("SELECT \\* FROM books").
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books$").
 			ExpectQuery().
-			WillReturnRows(rows)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectPrepare(selectBooks + " ORDER BY title ASC, id ASC LIMIT \\$1").
+			ExpectQuery().
+			WithArgs(3).
+			WillReturnRows(bookRows(books))
+
+		page, err := repo.List(context.Background(), entity.BookQuery{Sort: entity.BookSortTitle, Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Equal(t, books[:2], page.Books)
+		assert.NotEmpty(t, page.NextCursor)
 
-		gotBooks, err := repo.List(context.Background())
+		cursor = page.NextCursor
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Next Page", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books$").
+			ExpectQuery().
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectPrepare(selectBooks+" WHERE \\(title, id\\) > \\(\\$1, \\$2\\) ORDER BY title ASC, id ASC LIMIT \\$3").
+			ExpectQuery().
+			WithArgs(books[1].Title, books[1].ID, 3).
+			WillReturnRows(bookRows(books[2:]))
 
+		page, err := repo.List(context.Background(), entity.BookQuery{Sort: entity.BookSortTitle, Limit: 2, Cursor: cursor})
 		assert.NoError(t, err)
-		assert.Equal(t, len(books), len(gotBooks))
+		assert.Equal(t, books[2:], page.Books)
+		assert.Empty(t, page.NextCursor)
 
-		for i := range gotBooks {
-			assert.Equal(t, books[i], gotBooks[i])
-		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Filters", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books WHERE LOWER\\(author\\) LIKE LOWER\\(\\$1\\) AND EXISTS \\(SELECT 1 FROM copies WHERE copies.book_id = books.id AND copies.status = 'available'\\)").
+			ExpectQuery().
+			WithArgs("%edwards%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectPrepare(selectBooks+" WHERE LOWER\\(author\\) LIKE LOWER\\(\\$1\\) AND EXISTS \\(SELECT 1 FROM copies WHERE copies.book_id = books.id AND copies.status = 'available'\\) ORDER BY created_at DESC, id DESC LIMIT \\$2").
+			ExpectQuery().
+			WithArgs("%edwards%", 11).
+			WillReturnRows(bookRows(books[:2]))
+
+		page, err := repo.List(context.Background(), entity.BookQuery{Author: "edwards", Available: true, Sort: "-created_at", Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+		assert.Equal(t, books[:2], page.Books)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Author ID", func(t *testing.T) {
+		byAuthor := "EXISTS \\(SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id AND book_authors.author_id = \\$1\\)"
+
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books WHERE " + byAuthor).
+			ExpectQuery().
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectPrepare(selectBooks+" WHERE "+byAuthor+" ORDER BY title ASC, id ASC LIMIT \\$2").
+			ExpectQuery().
+			WithArgs(7, 11).
+			WillReturnRows(bookRows(books[:1]))
+
+		page, err := repo.List(context.Background(), entity.BookQuery{AuthorID: 7, Sort: entity.BookSortTitle, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, books[:1], page.Books)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Subject ID", func(t *testing.T) {
+		bySubject := regexp.QuoteMeta("EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND book_subjects.subject_id IN " +
+			"(WITH RECURSIVE tree AS (SELECT id FROM subjects WHERE id = $1 UNION ALL SELECT subjects.id FROM subjects JOIN tree ON subjects.parent_id = tree.id) " +
+			"SELECT id FROM tree))")
+
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books WHERE " + bySubject).
+			ExpectQuery().
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectPrepare(selectBooks+" WHERE "+bySubject+" ORDER BY title ASC, id ASC LIMIT \\$2").
+			ExpectQuery().
+			WithArgs(3, 11).
+			WillReturnRows(bookRows(books[:1]))
+
+		page, err := repo.List(context.Background(), entity.BookQuery{SubjectID: 3, Sort: entity.BookSortTitle, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, books[:1], page.Books)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Search", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books WHERE \\(LOWER\\(title\\) LIKE LOWER\\(\\$1\\) OR LOWER\\(author\\) LIKE LOWER\\(\\$1\\)\\) AND LOWER\\(title\\) LIKE LOWER\\(\\$2\\)").
+			ExpectQuery().
+			WithArgs("%go%", "%let%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectPrepare(selectBooks+" WHERE .+ ORDER BY title ASC, id ASC LIMIT \\$3").
+			ExpectQuery().
+			WithArgs("%go%", "%let%", 11).
+			WillReturnRows(bookRows(books[:2]))
+
+		page, err := repo.List(context.Background(), entity.BookQuery{Query: "go", Title: "let", Sort: entity.BookSortTitle, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, books[:2], page.Books)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Cursor Of Another Sort", func(t *testing.T) {
+		page, err := repo.List(context.Background(), entity.BookQuery{Sort: entity.BookSortAuthor, Limit: 2, Cursor: cursor})
+		assert.Equal(t, ports.ErrInvalidCursor, err)
+		assert.Nil(t, page)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books").
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books").
 			WillReturnError(sql.ErrConnDone)
 
-		gotBook, err := repo.List(context.Background())
+		page, err := repo.List(context.Background(), entity.BookQuery{Sort: entity.BookSortTitle, Limit: 2})
 		assert.Error(t, err)
-		assert.Empty(t, gotBook)
+		assert.Nil(t, page)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Query Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books").
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books").
+			ExpectQuery().
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectPrepare(selectBooks + "").
 			ExpectQuery().
 			WillReturnError(sql.ErrConnDone)
 
-		gotBook, err := repo.List(context.Background())
+		page, err := repo.List(context.Background(), entity.BookQuery{Sort: entity.BookSortTitle, Limit: 2})
 		assert.Error(t, err)
-		assert.Empty(t, gotBook)
+		assert.Nil(t, page)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Empty book", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books").
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM books").
 			ExpectQuery().
-			WillReturnRows(&sqlmock.Rows{})
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectPrepare(selectBooks + "").
+			ExpectQuery().
+			WillReturnRows(bookRows(nil))
+
+		page, err := repo.List(context.Background(), entity.BookQuery{Sort: entity.BookSortTitle, Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, page.Total)
+		assert.Empty(t, page.Books)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExportBooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBookRepository(db)
+
+	createdAt := time.Now()
+	bookRows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at", "description", "isbn", "publisher", "published_year", "language", "pages", "cover_url", "version"}).
+		AddRow(3, "Let's Go", "Alex Edwards", 2, nil, createdAt, "", "", "", 0, "", 0, "", 1).
+		AddRow(5, "Learning Go", "Jon Bodner", 1, nil, createdAt, "", "", "", 0, "", 0, "", 1)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare(selectBooks+" WHERE id > \\$1 ORDER BY id LIMIT \\$2").
+			ExpectQuery().
+			WithArgs(2, 500).
+			WillReturnRows(bookRows)
+		mock.ExpectPrepare("SELECT book_authors.book_id, book_authors.author_id, authors.name, book_authors.role FROM book_authors .* WHERE book_authors.book_id = ANY\\(\\$1\\)").
+			ExpectQuery().
+			WithArgs("{3,5}").
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id", "name", "role"}).
+				AddRow(5, 2, "Jon Bodner", "author"))
+		mock.ExpectPrepare("SELECT book_subjects.book_id, subjects.id, subjects.name FROM book_subjects .* WHERE book_subjects.book_id = ANY\\(\\$1\\)").
+			ExpectQuery().
+			WithArgs("{3,5}").
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "id", "name"}).
+				AddRow(3, 2, "Programming"))
+
+		books, err := repo.Export(context.Background(), 2, 500)
+		assert.NoError(t, err)
+		assert.Len(t, books, 2)
+
+		assert.Equal(t, []entity.BookAuthor{}, books[0].Authors)
+		assert.Equal(t, []entity.Subject{{ID: 2, Name: "Programming"}}, books[0].Subjects)
+		assert.Equal(t, []entity.BookAuthor{{AuthorID: 2, Name: "Jon Bodner", Role: entity.AuthorRoleAuthor}}, books[1].Authors)
+		assert.Equal(t, []entity.Subject{}, books[1].Subjects)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Last Batch", func(t *testing.T) {
+		mock.ExpectPrepare(selectBooks+" WHERE id > \\$1 ORDER BY id LIMIT \\$2").
+			ExpectQuery().
+			WithArgs(5, 500).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		books, err := repo.Export(context.Background(), 5, 500)
+		assert.NoError(t, err)
+		assert.Empty(t, books)
 
-		gotBooks, err := repo.List(context.Background())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare(selectBooks).
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		books, err := repo.Export(context.Background(), 0, 500)
 		assert.Error(t, err)
-		assert.Nil(t, gotBooks)
+		assert.Nil(t, books)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -167,97 +420,115 @@ func TestSearchBooks(t *testing.T) {
 
 	repo := NewBookRepository(db)
 
-	books := []*entity.Book{
+	matches := []*entity.BookMatch{
 		{
-			ID:        1,
-			Title:     "Let's Go Further!",
-			Author:    "Alex Edwards",
-			Amount:    5,
-			UpdatedAt: time.Time{},
-			CreatedAt: time.Now(),
+			Book: &entity.Book{
+				ID:     1,
+				Title:  "Let's Go Further!",
+				Author: "Alex Edwards",
+				Amount: 5,
+				BookMetadata: entity.BookMetadata{
+					ISBN:          "9781234567897",
+					Publisher:     "Alex Edwards",
+					PublishedYear: 2021,
+					Language:      "en",
+					Pages:         583,
+					Description:   "Advanced patterns for building APIs and web applications in Go",
+					CoverURL:      "https://example.com/covers/lets-go-further.jpg",
+				},
+				UpdatedAt: time.Time{},
+				CreatedAt: time.Now(),
+			},
+			Rank:    0.6,
+			Snippet: "<mark>Let</mark>&#39;s <mark>Go</mark> Further! - Alex Edwards",
 		}, {
-			ID:        2,
-			Title:     "Let's Go!",
-			Author:    "Alex Edwards",
-			Amount:    10,
-			UpdatedAt: time.Time{},
-			CreatedAt: time.Now(),
+			Book: &entity.Book{
+				ID:        2,
+				Title:     "Let's Go!",
+				Author:    "Alex Edwards",
+				Amount:    10,
+				UpdatedAt: time.Time{},
+				CreatedAt: time.Now(),
+			},
+			Rank:    0.3,
+			Snippet: "<mark>Let</mark>&#39;s <mark>Go</mark>! - Alex Edwards",
 		},
 	}
 
+	// the snippets as read, with the matched words between the headline markers
+	headlines := []string{
+		"\x02Let\x03's \x02Go\x03 Further! - Alex Edwards",
+		"\x02Let\x03's \x02Go\x03! - Alex Edwards",
+	}
+	headlineOptions := "StartSel=\x02, StopSel=\x03"
+
+	search := entity.BookSearch{Query: "Let's Go", Limit: 10}
+	query := regexp.QuoteMeta("SELECT "+bookColumns) + ", ts_rank\\(search_vector, query\\) AS rank, ts_headline\\(.+\\) " +
+		"FROM books, to_tsquery\\('simple', \\$1\\) AS query WHERE search_vector @@ query ORDER BY rank DESC, id LIMIT \\$2"
+
 	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at"})
-		for _, book := range books {
-			rows = rows.AddRow(book.ID, book.Title, book.Author, book.Amount, book.UpdatedAt, book.CreatedAt)
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at", "description", "isbn", "publisher", "published_year", "language", "pages", "cover_url", "version", "rank", "ts_headline"})
+		for i, m := range matches {
+			b := m.Book
+			rows = rows.AddRow(b.ID, b.Title, b.Author, b.Amount, b.UpdatedAt, b.CreatedAt, b.Description, b.ISBN, b.Publisher, b.PublishedYear, b.Language, b.Pages, b.CoverURL, b.Version, m.Rank, headlines[i])
 		}
 
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE LOWER\\(title\\) LIKE LOWER\\(\\$1\\)").
+		mock.ExpectPrepare(query).
 			ExpectQuery().
-			WithArgs("%Let's Go%").
+			WithArgs("let:* & s:* & go:*", search.Limit, headlineOptions).
 			WillReturnRows(rows)
 
-		gotBooks, err := repo.Search(context.Background(), "Let's Go")
+		gotMatches, err := repo.Search(context.Background(), search)
 
 		assert.NoError(t, err)
-		assert.Equal(t, len(books), len(gotBooks))
-
-		for i := range gotBooks {
-			assert.Equal(t, books[i], gotBooks[i])
-		}
+		assert.Equal(t, matches, gotMatches)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE LOWER\\(title\\) LIKE LOWER\\(\\$1\\)").
+		mock.ExpectPrepare(query).
 			WillReturnError(sql.ErrConnDone)
 
-		gotBooks, err := repo.Search(context.Background(), "Let's Go")
+		gotMatches, err := repo.Search(context.Background(), search)
 
 		assert.Error(t, err)
-		assert.Empty(t, gotBooks)
+		assert.Empty(t, gotMatches)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Query Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE LOWER\\(title\\) LIKE LOWER\\(\\$1\\)").
+		mock.ExpectPrepare(query).
 			ExpectQuery().
 			WillReturnError(sql.ErrConnDone)
 
-		gotBooks, err := repo.Search(context.Background(), "Let's Go")
+		gotMatches, err := repo.Search(context.Background(), search)
 
 		assert.Error(t, err)
-		assert.Empty(t, gotBooks)
-
-		assert.NoError(t, mock.ExpectationsWereMet())
-	})
-	t.Run("Empty Book", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE LOWER\\(title\\) LIKE LOWER\\(\\$1\\)").
-			ExpectQuery().
-			WillReturnRows(&sqlmock.Rows{})
-
-		gotBooks, err := repo.Search(context.Background(), "Let's Go")
-
-		assert.Error(t, err)
-		assert.Empty(t, gotBooks)
+		assert.Empty(t, gotMatches)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Not Found", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM books WHERE LOWER\\(title\\) LIKE LOWER\\(\\$1\\)").
+		mock.ExpectPrepare(query).
 			ExpectQuery().
-			WithArgs("%Let's Go%").
+			WithArgs("let:* & s:* & go:*", search.Limit, headlineOptions).
 			WillReturnRows(&sqlmock.Rows{})
 
-		gotBooks, err := repo.Search(context.Background(), "Let's Go")
+		gotMatches, err := repo.Search(context.Background(), search)
 
-		assert.Error(t, err)
-		assert.Empty(t, gotBooks)
+		assert.Equal(t, ports.ErrBookNotFound, err)
+		assert.Empty(t, gotMatches)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
+func TestHighlight(t *testing.T) {
+	got := highlight("\x02Scripts\x03 <script>alert(1)</script> & \x02more\x03")
+	assert.Equal(t, "<mark>Scripts</mark> &lt;script&gt;alert(1)&lt;/script&gt; &amp; <mark>more</mark>", got)
+}
+
 func TestCreateBook(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -266,31 +537,44 @@ func TestCreateBook(t *testing.T) {
 	repo := NewBookRepository(db)
 
 	book := &entity.Book{
-		ID:        1,
-		Title:     "Let's Go Further!",
-		Author:    "Alex Edwards",
-		Amount:    5,
+		ID:     1,
+		Title:  "Let's Go Further!",
+		Author: "Alex Edwards",
+		Amount: 5,
+		BookMetadata: entity.BookMetadata{
+			ISBN:          "9781234567897",
+			Publisher:     "Alex Edwards",
+			PublishedYear: 2021,
+			Language:      "en",
+			Pages:         583,
+			Description:   "Advanced patterns for building APIs and web applications in Go",
+			CoverURL:      "https://example.com/covers/lets-go-further.jpg",
+		},
 		UpdatedAt: time.Time{},
 		CreatedAt: time.Now(),
 	}
 
-	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id"}).
-			AddRow(1)
+	insertBook := "INSERT INTO books \\(title, author, description, isbn, publisher, published_year, language, pages, cover_url\\)"
+	insertCopies := "INSERT INTO copies \\(book_id, barcode\\)"
 
-		mock.ExpectPrepare("INSERT INTO books").
-			ExpectQuery().
-			WithArgs(book.Title, book.Author, book.Amount).
-			WillReturnRows(rows)
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertBook).
+			WithArgs(book.Title, book.Author, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(insertCopies).
+			WithArgs(1, book.Amount).
+			WillReturnResult(sqlmock.NewResult(0, int64(book.Amount)))
+		mock.ExpectCommit()
 
 		id, err := repo.Create(context.Background(), book)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, id)
+		assert.Equal(t, 1, id)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("INSERT INTO books").
+	t.Run("Begin Failed", func(t *testing.T) {
+		mock.ExpectBegin().
 			WillReturnError(sql.ErrConnDone)
 
 		id, err := repo.Create(context.Background(), book)
@@ -299,10 +583,77 @@ func TestCreateBook(t *testing.T) {
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Query Failed", func(t *testing.T) {
-		mock.ExpectPrepare("INSERT INTO books").
-			ExpectQuery().
+	t.Run("Query Insert Books Failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertBook).
 			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		id, err := repo.Create(context.Background(), book)
+		assert.Error(t, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("ISBN Already Exists", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertBook).
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+		mock.ExpectRollback()
+
+		id, err := repo.Create(context.Background(), book)
+		assert.Equal(t, ports.ErrISBNAlreadyExists, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Author Not Found", func(t *testing.T) {
+		withAuthors := *book
+		withAuthors.Authors = []entity.BookAuthor{{AuthorID: 10, Role: entity.AuthorRoleAuthor}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertBook).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(insertCopies).
+			WillReturnResult(sqlmock.NewResult(0, int64(book.Amount)))
+		mock.ExpectExec("INSERT INTO book_authors").
+			WithArgs(1, 10, entity.AuthorRoleAuthor, 1).
+			WillReturnError(&pq.Error{Code: foreignKeyViolationCode})
+		mock.ExpectRollback()
+
+		id, err := repo.Create(context.Background(), &withAuthors)
+		assert.Equal(t, ports.ErrAuthorNotFound, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Subject Not Found", func(t *testing.T) {
+		withSubjects := *book
+		withSubjects.Subjects = []entity.Subject{{ID: 10}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertBook).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(insertCopies).
+			WillReturnResult(sqlmock.NewResult(0, int64(book.Amount)))
+		mock.ExpectExec("INSERT INTO book_subjects").
+			WithArgs(1, 10).
+			WillReturnError(&pq.Error{Code: foreignKeyViolationCode})
+		mock.ExpectRollback()
+
+		id, err := repo.Create(context.Background(), &withSubjects)
+		assert.Equal(t, ports.ErrSubjectNotFound, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Insert Copies Failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertBook).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(insertCopies).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
 
 		id, err := repo.Create(context.Background(), book)
 		assert.Error(t, err)
@@ -320,28 +671,112 @@ func TestUpdateBook(t *testing.T) {
 	repo := NewBookRepository(db)
 
 	book := &entity.Book{
-		ID:        1,
-		Title:     "Let's Go Further!",
-		Author:    "Alex Edwards",
-		Amount:    5,
+		ID:     1,
+		Title:  "Let's Go Further!",
+		Author: "Alex Edwards",
+		Amount: 5,
+		BookMetadata: entity.BookMetadata{
+			ISBN:          "9781234567897",
+			Publisher:     "Alex Edwards",
+			PublishedYear: 2021,
+			Language:      "en",
+			Pages:         583,
+			Description:   "Advanced patterns for building APIs and web applications in Go",
+			CoverURL:      "https://example.com/covers/lets-go-further.jpg",
+		},
 		UpdatedAt: time.Time{},
 		CreatedAt: time.Now(),
+		Version:   2,
 	}
 
+	updateBook := "UPDATE books SET title = \\$1, author = \\$2"
+	deleteAuthors := "DELETE FROM book_authors WHERE book_id = \\$1"
+	bookExists := "SELECT EXISTS \\(SELECT 1 FROM books WHERE id = \\$1\\)"
+	insertAuthor := "INSERT INTO book_authors \\(book_id, author_id, role, position\\)"
+
 	t.Run("OK", func(t *testing.T) {
-		mock.ExpectPrepare("UPDATE books").
-			ExpectExec().
-			WithArgs(book.Title, book.Author, book.Amount, book.ID).
-			WillReturnResult(sqlmock.NewResult(int64(book.ID), 1))
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
+			WithArgs(book.Title, book.Author, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL, book.ID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectCommit()
+
+		updated := *book
+		err := repo.Update(context.Background(), &updated)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, updated.Version)
 
-		err := repo.Update(context.Background(), book)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Replace Authors", func(t *testing.T) {
+		withAuthors := *book
+		withAuthors.Authors = []entity.BookAuthor{
+			{AuthorID: 1, Role: entity.AuthorRoleAuthor},
+			{AuthorID: 2, Role: entity.AuthorRoleTranslator},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectExec(deleteAuthors).
+			WithArgs(book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertAuthor).
+			WithArgs(book.ID, 1, entity.AuthorRoleAuthor, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertAuthor).
+			WithArgs(book.ID, 2, entity.AuthorRoleTranslator, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Update(context.Background(), &withAuthors)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Replace Subjects", func(t *testing.T) {
+		withSubjects := *book
+		withSubjects.Subjects = []entity.Subject{{ID: 2}, {ID: 3}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectExec("DELETE FROM book_subjects WHERE book_id = \\$1").
+			WithArgs(book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO book_subjects \\(book_id, subject_id\\)").
+			WithArgs(book.ID, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO book_subjects \\(book_id, subject_id\\)").
+			WithArgs(book.ID, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Update(context.Background(), &withSubjects)
 		assert.NoError(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+	t.Run("Author Not Found", func(t *testing.T) {
+		withAuthors := *book
+		withAuthors.Authors = []entity.BookAuthor{{AuthorID: 10, Role: entity.AuthorRoleAuthor}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectExec(deleteAuthors).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertAuthor).
+			WillReturnError(&pq.Error{Code: foreignKeyViolationCode})
+		mock.ExpectRollback()
+
+		err := repo.Update(context.Background(), &withAuthors)
+		assert.Equal(t, ports.ErrAuthorNotFound, err)
 
-	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("UPDATE books").
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Begin Failed", func(t *testing.T) {
+		mock.ExpectBegin().
 			WillReturnError(sql.ErrConnDone)
 
 		err := repo.Update(context.Background(), book)
@@ -349,10 +784,11 @@ func TestUpdateBook(t *testing.T) {
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("UPDATE books").
-			ExpectExec().
+	t.Run("Exec Update Books Failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
 			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
 
 		err := repo.Update(context.Background(), book)
 		assert.Error(t, err)
@@ -360,12 +796,292 @@ func TestUpdateBook(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Not Found", func(t *testing.T) {
-		mock.ExpectPrepare("UPDATE books").
-			ExpectExec().
-			WithArgs(book.Title, book.Author, book.Amount, book.ID).
-			WillReturnResult(sqlmock.NewResult(int64(book.ID), 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
+			WithArgs(book.Title, book.Author, book.Description, book.ISBN, book.Publisher, book.PublishedYear, book.Language, book.Pages, book.CoverURL, book.ID, 2).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(bookExists).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+
+		err := repo.Update(context.Background(), book)
+		assert.Equal(t, ports.ErrBookNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateBook).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(bookExists).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
 
 		err := repo.Update(context.Background(), book)
+		assert.Equal(t, ports.ErrVersionConflict, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPatchBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBookRepository(db)
+
+	book := &entity.Book{
+		ID:     1,
+		Title:  "Let's Go Further!",
+		Author: "Alex Edwards",
+		Amount: 5,
+		BookMetadata: entity.BookMetadata{
+			ISBN:  "9781234567897",
+			Pages: 583,
+		},
+		Version: 2,
+	}
+
+	title := book.Title
+	isbn := book.ISBN
+	authors := []entity.BookAuthor{{AuthorID: 1, Role: entity.AuthorRoleAuthor}}
+
+	bookExists := "SELECT EXISTS \\(SELECT 1 FROM books WHERE id = \\$1\\)"
+
+	t.Run("Patched Columns", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE books SET title = $1, isbn = $2, version = version + 1, updated_at = NOW() WHERE id = $3 AND version = $4 RETURNING version")).
+			WithArgs(book.Title, book.ISBN, book.ID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectCommit()
+
+		patched := *book
+		err := repo.Patch(context.Background(), &patched, &entity.BookPatch{Title: &title, ISBN: &isbn})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, patched.Version)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Replace Authors", func(t *testing.T) {
+		patched := *book
+		patched.Authors = authors
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE books SET version = version + 1, updated_at = NOW() WHERE id = $1 AND version = $2 RETURNING version")).
+			WithArgs(book.ID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectExec("DELETE FROM book_authors WHERE book_id = \\$1").
+			WithArgs(book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO book_authors \\(book_id, author_id, role, position\\)").
+			WithArgs(book.ID, 1, entity.AuthorRoleAuthor, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Patch(context.Background(), &patched, &entity.BookPatch{Authors: &authors})
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("ISBN Already Exists", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE books SET isbn = \\$1").
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+		mock.ExpectRollback()
+
+		err := repo.Patch(context.Background(), book, &entity.BookPatch{ISBN: &isbn})
+		assert.Equal(t, ports.ErrISBNAlreadyExists, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE books SET title = \\$1").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(bookExists).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+
+		err := repo.Patch(context.Background(), book, &entity.BookPatch{Title: &title})
+		assert.Equal(t, ports.ErrBookNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE books SET title = \\$1").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(bookExists).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err := repo.Patch(context.Background(), book, &entity.BookPatch{Title: &title})
+		assert.Equal(t, ports.ErrVersionConflict, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestImportBooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewBookRepository(db)
+
+	// the first row creates a book and the second only has a title and an ISBN
+	newRows := func() []*entity.BookImportRow {
+		title, author, amount := "Let's Go", "Alex Edwards", 2
+		further, isbn := "Let's Go Further!", "9781234567897"
+		return []*entity.BookImportRow{
+			{
+				Line:   2,
+				Book:   &entity.Book{Title: title, Author: author, Amount: amount},
+				Fields: &entity.BookPatch{Title: &title, Author: &author, Amount: &amount},
+			},
+			{
+				Line:   3,
+				Book:   &entity.Book{Title: further, Author: "Unknown", Amount: 1, BookMetadata: entity.BookMetadata{ISBN: isbn}},
+				Fields: &entity.BookPatch{Title: &further, ISBN: &isbn},
+			},
+		}
+	}
+
+	insertBook := "INSERT INTO books \\(title, author, description, isbn, publisher, published_year, language, pages, cover_url\\)"
+	insertCopies := "INSERT INTO copies \\(book_id, barcode\\)"
+	selectISBN := "SELECT id, version FROM books WHERE isbn = \\$1"
+	savepoint := "SAVEPOINT import_row"
+	release := "RELEASE SAVEPOINT import_row"
+	rollback := "ROLLBACK TO SAVEPOINT import_row"
+
+	t.Run("Created And Updated", func(t *testing.T) {
+		rows := newRows()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(insertBook).
+			WithArgs("Let's Go", "Alex Edwards", "", "", "", 0, "", 0, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(insertCopies).
+			WithArgs(3, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(release).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectISBN).
+			WithArgs("9781234567897").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 2))
+		// only the supplied columns are written, the author and the metadata are kept
+		mock.ExpectQuery("UPDATE books SET title = \\$1, isbn = \\$2, version = version \\+ 1, updated_at = NOW\\(\\) WHERE id = \\$3 AND version = \\$4 RETURNING version").
+			WithArgs("Let's Go Further!", "9781234567897", 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectExec(release).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Import(context.Background(), rows)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.BookImportCreated, rows[0].Status)
+		assert.Equal(t, entity.BookImportUpdated, rows[1].Status)
+		assert.Equal(t, 3, rows[0].Book.ID)
+		assert.Equal(t, 1, rows[1].Book.ID)
+		assert.Equal(t, 3, rows[1].Book.Version)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("New ISBN", func(t *testing.T) {
+		rows := newRows()[1:]
+		author, amount := "Alex Edwards", 1
+		rows[0].Fields.Author = &author
+		rows[0].Fields.Amount = &amount
+
+		mock.ExpectBegin()
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectISBN).
+			WithArgs("9781234567897").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(insertBook).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(insertCopies).
+			WithArgs(4, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(release).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Import(context.Background(), rows)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.BookImportCreated, rows[0].Status)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("New Book Incomplete", func(t *testing.T) {
+		rows := newRows()[1:]
+
+		mock.ExpectBegin()
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectISBN).
+			WithArgs("9781234567897").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec(rollback).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Import(context.Background(), rows)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.BookImportRejected, rows[0].Status)
+		assert.Equal(t, ports.ErrNewBookIncomplete, rows[0].Err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Row Rejected", func(t *testing.T) {
+		rows := newRows()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(insertBook).
+			WillReturnError(&pq.Error{Code: "22001"})
+		mock.ExpectExec(rollback).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectISBN).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 2))
+		mock.ExpectQuery("UPDATE books SET").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+		mock.ExpectExec(release).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Import(context.Background(), rows)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.BookImportRejected, rows[0].Status)
+		assert.Error(t, rows[0].Err)
+		assert.Equal(t, entity.BookImportUpdated, rows[1].Status)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Begin Failed", func(t *testing.T) {
+		mock.ExpectBegin().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.Import(context.Background(), newRows())
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Rolled Back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(insertBook).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(insertCopies).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(release).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectISBN).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repo.Import(context.Background(), newRows())
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -380,21 +1096,33 @@ func TestDeleteBook(t *testing.T) {
 	repo := NewBookRepository(db)
 
 	book := &entity.Book{
-		ID:        1,
-		Title:     "Let's Go Further!",
-		Author:    "Alex Edwards",
-		Amount:    5,
+		ID:     1,
+		Title:  "Let's Go Further!",
+		Author: "Alex Edwards",
+		Amount: 5,
+		BookMetadata: entity.BookMetadata{
+			ISBN:          "9781234567897",
+			Publisher:     "Alex Edwards",
+			PublishedYear: 2021,
+			Language:      "en",
+			Pages:         583,
+			Description:   "Advanced patterns for building APIs and web applications in Go",
+			CoverURL:      "https://example.com/covers/lets-go-further.jpg",
+		},
 		UpdatedAt: time.Time{},
 		CreatedAt: time.Now(),
+		Version:   2,
 	}
 
+	bookExists := "SELECT EXISTS \\(SELECT 1 FROM books WHERE id = \\$1\\)"
+
 	t.Run("OK", func(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM books WHERE id =").
 			ExpectExec().
-			WithArgs(book.ID).
+			WithArgs(book.ID, book.Version).
 			WillReturnResult(sqlmock.NewResult(int64(book.ID), 1))
 
-		err := repo.Delete(context.Background(), book.ID)
+		err := repo.Delete(context.Background(), book.ID, book.Version)
 		assert.NoError(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -403,7 +1131,7 @@ func TestDeleteBook(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM books WHERE id =").
 			WillReturnError(sql.ErrConnDone)
 
-		err := repo.Delete(context.Background(), book.ID)
+		err := repo.Delete(context.Background(), book.ID, book.Version)
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -413,7 +1141,7 @@ func TestDeleteBook(t *testing.T) {
 			ExpectExec().
 			WillReturnError(sql.ErrConnDone)
 
-		err := repo.Delete(context.Background(), book.ID)
+		err := repo.Delete(context.Background(), book.ID, book.Version)
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -421,11 +1149,28 @@ func TestDeleteBook(t *testing.T) {
 	t.Run("Not Found", func(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM books WHERE id =").
 			ExpectExec().
+			WithArgs(book.ID, book.Version).
+			WillReturnResult(sqlmock.NewResult(int64(book.ID), 0))
+		mock.ExpectQuery(bookExists).
 			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.Delete(context.Background(), book.ID, book.Version)
+		assert.Equal(t, ports.ErrBookNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM books WHERE id =").
+			ExpectExec().
+			WithArgs(book.ID, book.Version).
 			WillReturnResult(sqlmock.NewResult(int64(book.ID), 0))
+		mock.ExpectQuery(bookExists).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
-		err := repo.Delete(context.Background(), book.ID)
-		assert.Error(t, err)
+		err := repo.Delete(context.Background(), book.ID, book.Version)
+		assert.Equal(t, ports.ErrVersionConflict, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
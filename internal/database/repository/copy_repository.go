@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type copyRepository struct {
+	db *sql.DB
+}
+
+// NewCopyRepository creates a new instance of copyRepository
+func NewCopyRepository(db *sql.DB) ports.CopyRepository {
+	return &copyRepository{
+		db: db,
+	}
+}
+
+// Get gets copy data by id
+func (r *copyRepository) Get(ctx context.Context, id int) (*entity.Copy, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM copies WHERE id = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, id)
+
+	c, err := scanCopy(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrCopyNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return c, nil
+}
+
+// ListByBook lists the copies of a book in barcode order
+func (r *copyRepository) ListByBook(ctx context.Context, bookID int) ([]*entity.Copy, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM copies WHERE book_id = $1 ORDER BY barcode")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	copies := []*entity.Copy{}
+	for rows.Next() {
+		c, err := scanCopy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		copies = append(copies, c)
+	}
+
+	return copies, nil
+}
+
+// Create creates a new copy
+func (r *copyRepository) Create(ctx context.Context, c *entity.Copy) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO copies (book_id, barcode, status, location) VALUES ($1, $2, $3, $4) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, c.BookID, c.Barcode, c.Status, c.Location).Scan(&c.ID)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return 0, ports.ErrBarcodeAlreadyExists
+		}
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return c.ID, nil
+}
+
+// Update updates the barcode, status and location of a copy
+func (r *copyRepository) Update(ctx context.Context, c *entity.Copy) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE copies SET barcode = $1, status = $2, location = $3, updated_at = NOW() WHERE id = $4")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, c.Barcode, c.Status, c.Location, c.ID)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrBarcodeAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrCopyNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a copy by id
+func (r *copyRepository) Delete(ctx context.Context, id int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "DELETE FROM copies WHERE id = $1")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrCopyNotFound
+	}
+
+	return nil
+}
+
+// Lend puts one of the available copies of a book on loan and gets its id,
+// concurrent loans lock different copies so a copy is never lent twice
+func (r *copyRepository) Lend(ctx context.Context, bookID int) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE copies SET status = $1, updated_at = NOW() WHERE id = "+
+		"(SELECT id FROM copies WHERE book_id = $2 AND status = $3 ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var id int
+	err = stmt.QueryRowContext(ctx, entity.CopyOnLoan, bookID, entity.CopyAvailable).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ports.ErrNoCopyAvailable
+		}
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return id, nil
+}
+
+// Shelve puts a copy on loan back on the shelf, copies reported lost meanwhile stay lost
+func (r *copyRepository) Shelve(ctx context.Context, id int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE copies SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, entity.CopyAvailable, id, entity.CopyOnLoan)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	return nil
+}
+
+// Adjust applies a stock adjustment to the copies of a book and records it in
+// a single transaction. Acquired copies are labelled after the last B<book id>-<n>
+// barcode, restored copies are the oldest lost or in repair copies and the other
+// movements take the newest available copies not set aside for ready holds, so
+// copies on loan are never touched
+func (r *copyRepository) Adjust(ctx context.Context, m *entity.InventoryMovement) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+	}
+
+	err = adjustCopies(ctx, tx, m)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return err
+	}
+
+	err = recordMovement(ctx, tx, m)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", ErrCommit, err)
+	}
+
+	return nil
+}
+
+// RecordMovement records a change of the copies of a book made outside of Adjust
+func (r *copyRepository) RecordMovement(ctx context.Context, m *entity.InventoryMovement) error {
+	return recordMovement(ctx, conn(ctx, r.db), m)
+}
+
+// recordMovement inserts the movement and gets its id and creation time
+func recordMovement(ctx context.Context, q querier, m *entity.InventoryMovement) error {
+	err := q.QueryRowContext(ctx, "INSERT INTO inventory_movements (book_id, user_id, kind, quantity, reason, barcodes) "+
+		"VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6) RETURNING id, created_at",
+		m.BookID, m.UserID, m.Kind, m.Quantity, m.Reason, pq.Array(m.Barcodes)).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// adjustCopies adds, moves or deletes the copies of a movement and keeps their barcodes
+func adjustCopies(ctx context.Context, tx querier, m *entity.InventoryMovement) error {
+	// the newest available copies leave the shelves first, locked until the transaction
+	// ends, as many copies as there are ready holds stay on the shelves for them
+	available := "SELECT id FROM copies WHERE book_id = $1 AND status = 'available' ORDER BY id DESC " +
+		"LIMIT GREATEST(0, LEAST($2, (SELECT COUNT(*) FROM copies WHERE book_id = $1 AND status = 'available') - " +
+		"(SELECT COUNT(*) FROM holds WHERE book_id = $1 AND status = 'ready'))) FOR UPDATE"
+
+	var query string
+	var args []any
+	switch m.Kind {
+	case entity.MovementAcquired:
+		query = "WITH last AS (SELECT COALESCE(MAX(substring(barcode FROM '-([0-9]+)$')::int), 0) AS n FROM copies WHERE barcode LIKE 'B' || $1::int || '-%') " +
+			"INSERT INTO copies (book_id, barcode) SELECT $1::int, 'B' || $1::int || '-' || (last.n + i) FROM last, generate_series(1, $2::int) AS i RETURNING barcode"
+		args = []any{m.BookID, m.Quantity}
+	case entity.MovementRestored:
+		query = "UPDATE copies SET status = 'available', updated_at = NOW() WHERE id IN " +
+			"(SELECT id FROM copies WHERE book_id = $1 AND status IN ('lost', 'in_repair') ORDER BY id LIMIT $2 FOR UPDATE) RETURNING barcode"
+		args = []any{m.BookID, m.Quantity}
+	case entity.MovementWithdrawn:
+		query = "DELETE FROM copies WHERE id IN (" + available + ") RETURNING barcode"
+		args = []any{m.BookID, m.Quantity}
+	default:
+		query = "UPDATE copies SET status = $3, updated_at = NOW() WHERE id IN (" + available + ") RETURNING barcode"
+		args = []any{m.BookID, m.Quantity, m.Kind.CopyStatus()}
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrBarcodeAlreadyExists
+		}
+		if ok && pqErr.Code == foreignKeyViolationCode {
+			return ports.ErrBookNotFound
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	m.Barcodes = []string{}
+	for rows.Next() {
+		var barcode string
+		if err := rows.Scan(&barcode); err != nil {
+			return fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		m.Barcodes = append(m.Barcodes, barcode)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	if len(m.Barcodes) < m.Quantity {
+		return ports.ErrNotEnoughCopies
+	}
+
+	return nil
+}
+
+// ListMovements lists the stock adjustments of a book, the latest first
+func (r *copyRepository) ListMovements(ctx context.Context, bookID int) ([]*entity.InventoryMovement, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM inventory_movements WHERE book_id = $1 ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	movements := []*entity.InventoryMovement{}
+	for rows.Next() {
+		var m entity.InventoryMovement
+		var userID sql.NullInt64
+
+		err := rows.Scan(&m.ID, &m.BookID, &userID, &m.Kind, &m.Quantity, &m.Reason, pq.Array(&m.Barcodes), &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		// the librarian may have been deleted since
+		if userID.Valid {
+			m.UserID = int(userID.Int64)
+		}
+
+		movements = append(movements, &m)
+	}
+
+	return movements, nil
+}
+
+// scanCopy scans a copies row into a copy entity
+func scanCopy(s scanner) (*entity.Copy, error) {
+	var c entity.Copy
+	var updatedAt sql.NullTime
+
+	err := s.Scan(&c.ID, &c.BookID, &c.Barcode, &c.Status, &c.Location, &updatedAt, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if updatedAt is not NULL
+	if updatedAt.Valid {
+		c.UpdatedAt = updatedAt.Time
+	}
+
+	return &c, nil
+}
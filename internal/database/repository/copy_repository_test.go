@@ -0,0 +1,535 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+var copyRowColumns = []string{"id", "book_id", "barcode", "status", "location", "updated_at", "created_at"}
+
+func TestGetCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	c := &entity.Copy{
+		ID:        1,
+		BookID:    1,
+		Barcode:   "B1-1",
+		Status:    entity.CopyAvailable,
+		Location:  "A3",
+		UpdatedAt: time.Now(),
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows(copyRowColumns).
+			AddRow(c.ID, c.BookID, c.Barcode, c.Status, c.Location, c.UpdatedAt, c.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM copies WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(c.ID).
+			WillReturnRows(rows)
+
+		gotCopy, err := repo.Get(context.Background(), c.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, c, gotCopy)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM copies WHERE id = \\$1").
+			WillReturnError(sql.ErrConnDone)
+
+		gotCopy, err := repo.Get(context.Background(), c.ID)
+		assert.Error(t, err)
+		assert.Nil(t, gotCopy)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM copies WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+
+		gotCopy, err := repo.Get(context.Background(), 10)
+		assert.Equal(t, ports.ErrCopyNotFound, err)
+		assert.Nil(t, gotCopy)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListCopiesByBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	copies := []*entity.Copy{
+		{
+			ID:        1,
+			BookID:    1,
+			Barcode:   "B1-1",
+			Status:    entity.CopyAvailable,
+			CreatedAt: time.Now(),
+		},
+		{
+			ID:        2,
+			BookID:    1,
+			Barcode:   "B1-2",
+			Status:    entity.CopyInRepair,
+			Location:  "Bindery",
+			CreatedAt: time.Now(),
+		},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows(copyRowColumns).
+			AddRow(copies[0].ID, copies[0].BookID, copies[0].Barcode, copies[0].Status, copies[0].Location, nil, copies[0].CreatedAt).
+			AddRow(copies[1].ID, copies[1].BookID, copies[1].Barcode, copies[1].Status, copies[1].Location, nil, copies[1].CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM copies WHERE book_id = \\$1 ORDER BY barcode").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		gotCopies, err := repo.ListByBook(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, copies, gotCopies)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM copies WHERE book_id = \\$1").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotCopies, err := repo.ListByBook(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, gotCopies)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	c := &entity.Copy{
+		BookID:   1,
+		Barcode:  "B1-3",
+		Status:   entity.CopyAvailable,
+		Location: "A3",
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO copies").
+			ExpectQuery().
+			WithArgs(c.BookID, c.Barcode, c.Status, c.Location).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+		id, err := repo.Create(context.Background(), c)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Barcode Already Exists", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO copies").
+			ExpectQuery().
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		id, err := repo.Create(context.Background(), c)
+		assert.Equal(t, ports.ErrBarcodeAlreadyExists, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO copies").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		id, err := repo.Create(context.Background(), c)
+		assert.Error(t, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	c := &entity.Copy{
+		ID:       1,
+		BookID:   1,
+		Barcode:  "B1-1",
+		Status:   entity.CopyLost,
+		Location: "A3",
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE copies SET barcode = \\$1, status = \\$2, location = \\$3, updated_at = NOW\\(\\) WHERE id = \\$4").
+			ExpectExec().
+			WithArgs(c.Barcode, c.Status, c.Location, c.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), c)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE copies").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Update(context.Background(), c)
+		assert.Equal(t, ports.ErrCopyNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Barcode Already Exists", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE copies").
+			ExpectExec().
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		err := repo.Update(context.Background(), c)
+		assert.Equal(t, ports.ErrBarcodeAlreadyExists, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM copies WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Delete(context.Background(), 1)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM copies WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(10).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Delete(context.Background(), 10)
+		assert.Equal(t, ports.ErrCopyNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM copies WHERE id = \\$1").
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.Delete(context.Background(), 1)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLendCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	lend := "UPDATE copies SET status = \\$1, updated_at = NOW\\(\\) WHERE id = " +
+		"\\(SELECT id FROM copies WHERE book_id = \\$2 AND status = \\$3 ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED\\) RETURNING id"
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare(lend).
+			ExpectQuery().
+			WithArgs(entity.CopyOnLoan, 1, entity.CopyAvailable).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+		id, err := repo.Lend(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("No Copy Available", func(t *testing.T) {
+		mock.ExpectPrepare(lend).
+			ExpectQuery().
+			WithArgs(entity.CopyOnLoan, 2, entity.CopyAvailable).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		id, err := repo.Lend(context.Background(), 2)
+		assert.Equal(t, ports.ErrNoCopyAvailable, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare(lend).
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		id, err := repo.Lend(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestShelveCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	shelve := "UPDATE copies SET status = \\$1, updated_at = NOW\\(\\) WHERE id = \\$2 AND status = \\$3"
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare(shelve).
+			ExpectExec().
+			WithArgs(entity.CopyAvailable, 7, entity.CopyOnLoan).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Shelve(context.Background(), 7)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare(shelve).
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.Shelve(context.Background(), 7)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAdjustStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	insertCopies := "WITH last AS \\(SELECT COALESCE\\(MAX\\(substring\\(barcode FROM '-\\(\\[0-9\\]\\+\\)\\$'\\)::int\\), 0\\) AS n FROM copies .*\\) INSERT INTO copies \\(book_id, barcode\\)"
+	updateCopies := "UPDATE copies SET status = \\$3, updated_at = NOW\\(\\) WHERE id IN \\(SELECT id FROM copies WHERE book_id = \\$1 AND status = 'available' ORDER BY id DESC " +
+		"LIMIT GREATEST\\(0, LEAST\\(\\$2, .* - \\(SELECT COUNT\\(\\*\\) FROM holds WHERE book_id = \\$1 AND status = 'ready'\\)\\)\\) FOR UPDATE\\) RETURNING barcode"
+	restoreCopies := "UPDATE copies SET status = 'available', updated_at = NOW\\(\\) WHERE id IN \\(SELECT id FROM copies WHERE book_id = \\$1 AND status IN \\('lost', 'in_repair'\\)"
+	deleteCopies := "DELETE FROM copies WHERE id IN \\(SELECT id FROM copies WHERE book_id = \\$1 AND status = 'available'"
+	insertMovement := "INSERT INTO inventory_movements \\(book_id, user_id, kind, quantity, reason, barcodes\\) VALUES \\(\\$1, NULLIF\\(\\$2, 0\\), \\$3, \\$4, \\$5, \\$6\\)"
+
+	t.Run("Acquired", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementAcquired, Quantity: 2, Reason: "donation"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertCopies).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("B1-3").AddRow("B1-4"))
+		mock.ExpectQuery(insertMovement).
+			WithArgs(1, 2, entity.MovementAcquired, 2, "donation", `{"B1-3","B1-4"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.Adjust(context.Background(), m)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, m.ID)
+		assert.Equal(t, []string{"B1-3", "B1-4"}, m.Barcodes)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Damaged", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementDamaged, Quantity: 1, Reason: "water damage"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateCopies).
+			WithArgs(1, 1, entity.CopyInRepair).
+			WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("B1-2"))
+		mock.ExpectQuery(insertMovement).
+			WithArgs(1, 2, entity.MovementDamaged, 1, "water damage", `{"B1-2"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(6, time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.Adjust(context.Background(), m)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"B1-2"}, m.Barcodes)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Restored", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementRestored, Quantity: 1, Reason: "found in the stacks"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(restoreCopies).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("B1-1"))
+		mock.ExpectQuery(insertMovement).
+			WithArgs(1, 2, entity.MovementRestored, 1, "found in the stacks", `{"B1-1"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.Adjust(context.Background(), m)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"B1-1"}, m.Barcodes)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Enough Copies", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementWithdrawn, Quantity: 3, Reason: "weeding"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(deleteCopies).
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("B1-2"))
+		mock.ExpectRollback()
+
+		err := repo.Adjust(context.Background(), m)
+		assert.Equal(t, ports.ErrNotEnoughCopies, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Barcode Already Exists", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementAcquired, Quantity: 1, Reason: "donation"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertCopies).
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+		mock.ExpectRollback()
+
+		err := repo.Adjust(context.Background(), m)
+		assert.Equal(t, ports.ErrBarcodeAlreadyExists, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Insert Movement Failed", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementLost, Quantity: 1, Reason: "missing"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateCopies).
+			WithArgs(1, 1, entity.CopyLost).
+			WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("B1-1"))
+		mock.ExpectQuery(insertMovement).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repo.Adjust(context.Background(), m)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRecordMovement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	insertMovement := "INSERT INTO inventory_movements \\(book_id, user_id, kind, quantity, reason, barcodes\\)"
+
+	t.Run("OK", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementWithdrawn, Quantity: 1, Reason: "copy deleted", Barcodes: []string{"B1-2"}}
+
+		mock.ExpectQuery(insertMovement).
+			WithArgs(1, 2, entity.MovementWithdrawn, 1, "copy deleted", `{"B1-2"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(8, time.Now()))
+
+		err := repo.RecordMovement(context.Background(), m)
+		assert.NoError(t, err)
+		assert.Equal(t, 8, m.ID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementLost, Quantity: 1, Reason: "copy lost"}
+
+		mock.ExpectQuery(insertMovement).
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.RecordMovement(context.Background(), m)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListMovements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCopyRepository(db)
+
+	createdAt := time.Now()
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "book_id", "user_id", "kind", "quantity", "reason", "barcodes", "created_at"}).
+			AddRow(2, 1, 2, "lost", 1, "missing", `{"B1-2"}`, createdAt).
+			AddRow(1, 1, nil, "acquired", 2, "donation", `{"B1-1","B1-2"}`, createdAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM inventory_movements WHERE book_id = \\$1 ORDER BY created_at DESC, id DESC").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		movements, err := repo.ListMovements(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.InventoryMovement{
+			{ID: 2, BookID: 1, UserID: 2, Kind: entity.MovementLost, Quantity: 1, Reason: "missing", Barcodes: []string{"B1-2"}, CreatedAt: createdAt},
+			{ID: 1, BookID: 1, Kind: entity.MovementAcquired, Quantity: 2, Reason: "donation", Barcodes: []string{"B1-1", "B1-2"}, CreatedAt: createdAt},
+		}, movements)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM inventory_movements").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		movements, err := repo.ListMovements(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, movements)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
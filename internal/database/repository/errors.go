@@ -1,7 +1,5 @@
 package repository
 
-import "errors"
-
 // Error description
 const (
 	ErrPrepareStatement = "failed to prepare SQL statement"
@@ -13,11 +11,3 @@ const (
 	ErrCommit           = "failed to commit transaction"
 	ErrRetrieveRows     = "failed to retrieve rows affected"
 )
-
-// Repository Errors
-var (
-	ErrBookNotFound  = errors.New("book not found")
-	ErrLoanNotFound  = errors.New("user does not have any loans")
-	ErrUserNotFound  = errors.New("user not found")
-	ErrAlreadyExists = errors.New("username or email already exists")
-)
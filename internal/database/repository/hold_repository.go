@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type holdRepository struct {
+	db *sql.DB
+}
+
+// NewHoldRepository creates a new instance of holdRepository
+func NewHoldRepository(db *sql.DB) ports.HoldRepository {
+	return &holdRepository{
+		db: db,
+	}
+}
+
+// Get gets hold data by id
+func (r *holdRepository) Get(ctx context.Context, id int) (*entity.Hold, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM holds WHERE id = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, id)
+
+	h, err := scanHold(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return h, nil
+}
+
+// ListActive lists the waiting and ready holds of a book in queue order
+func (r *holdRepository) ListActive(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM holds WHERE book_id = $1 AND status IN ('waiting', 'ready') ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	holds := []*entity.Hold{}
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		holds = append(holds, h)
+	}
+
+	return holds, nil
+}
+
+// ListActiveForUpdate lists the waiting and ready holds of a book in queue
+// order and locks them until the transaction in the context ends
+func (r *holdRepository) ListActiveForUpdate(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM holds WHERE book_id = $1 AND status IN ('waiting', 'ready') ORDER BY created_at, id FOR UPDATE")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	holds := []*entity.Hold{}
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		holds = append(holds, h)
+	}
+
+	return holds, nil
+}
+
+// Create creates a new hold
+func (r *holdRepository) Create(ctx context.Context, h *entity.Hold) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO holds (user_id, book_id, status) VALUES ($1, $2, $3) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, h.UserID, h.BookID, h.Status).Scan(&h.ID)
+	if err != nil {
+		// the active holds index rejects a second active hold
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return 0, ports.ErrHoldActive
+		}
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return h.ID, nil
+}
+
+// Update updates the status and expiration of a hold
+func (r *holdRepository) Update(ctx context.Context, h *entity.Hold) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE holds SET status = $1, expires_at = $2, updated_at = NOW() WHERE id = $3")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var expiresAt sql.NullTime
+	if !h.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: h.ExpiresAt, Valid: true}
+	}
+
+	result, err := stmt.ExecContext(ctx, h.Status, expiresAt, h.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrHoldNotFound
+	}
+
+	return nil
+}
+
+// scanHold scans a holds row into a hold entity
+func scanHold(s scanner) (*entity.Hold, error) {
+	var h entity.Hold
+	var expiresAt, updatedAt sql.NullTime
+
+	err := s.Scan(&h.ID, &h.UserID, &h.BookID, &h.Status, &expiresAt, &updatedAt, &h.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if expiresAt and updatedAt are not NULL
+	if expiresAt.Valid {
+		h.ExpiresAt = expiresAt.Time
+	}
+	if updatedAt.Valid {
+		h.UpdatedAt = updatedAt.Time
+	}
+
+	return &h, nil
+}
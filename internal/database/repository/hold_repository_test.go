@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHoldRepository(db)
+
+	hold := &entity.Hold{
+		ID:        1,
+		UserID:    1,
+		BookID:    1,
+		Status:    entity.HoldReady,
+		ExpiresAt: time.Now().AddDate(0, 0, 3),
+		UpdatedAt: time.Now(),
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "status", "expires_at", "updated_at", "created_at"}).
+			AddRow(hold.ID, hold.UserID, hold.BookID, hold.Status, hold.ExpiresAt, hold.UpdatedAt, hold.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(hold.ID).
+			WillReturnRows(rows)
+
+		gotHold, err := repo.Get(context.Background(), hold.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, hold, gotHold)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE id = \\$1").
+			WillReturnError(sql.ErrConnDone)
+
+		gotHold, err := repo.Get(context.Background(), hold.ID)
+		assert.Error(t, err)
+		assert.Nil(t, gotHold)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+
+		gotHold, err := repo.Get(context.Background(), 10)
+		assert.Equal(t, ports.ErrHoldNotFound, err)
+		assert.Nil(t, gotHold)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListActiveHolds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHoldRepository(db)
+
+	holds := []*entity.Hold{
+		{
+			ID:        1,
+			UserID:    1,
+			BookID:    1,
+			Status:    entity.HoldReady,
+			ExpiresAt: time.Now().AddDate(0, 0, 3),
+			CreatedAt: time.Now(),
+		},
+		{
+			ID:        2,
+			UserID:    2,
+			BookID:    1,
+			Status:    entity.HoldWaiting,
+			CreatedAt: time.Now(),
+		},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "status", "expires_at", "updated_at", "created_at"}).
+			AddRow(holds[0].ID, holds[0].UserID, holds[0].BookID, holds[0].Status, holds[0].ExpiresAt, nil, holds[0].CreatedAt).
+			AddRow(holds[1].ID, holds[1].UserID, holds[1].BookID, holds[1].Status, nil, nil, holds[1].CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE book_id = \\$1 AND status IN \\('waiting', 'ready'\\) ORDER BY created_at, id").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		gotHolds, err := repo.ListActive(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, holds, gotHolds)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE book_id = \\$1").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotHolds, err := repo.ListActive(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, gotHolds)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Empty Queue", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE book_id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnRows(&sqlmock.Rows{})
+
+		gotHolds, err := repo.ListActive(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.Empty(t, gotHolds)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListActiveHoldsForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHoldRepository(db)
+
+	hold := &entity.Hold{
+		ID:        1,
+		UserID:    1,
+		BookID:    1,
+		Status:    entity.HoldWaiting,
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "status", "expires_at", "updated_at", "created_at"}).
+			AddRow(hold.ID, hold.UserID, hold.BookID, hold.Status, nil, nil, hold.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE book_id = \\$1 AND status IN \\('waiting', 'ready'\\) ORDER BY created_at, id FOR UPDATE").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		gotHolds, err := repo.ListActiveForUpdate(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Hold{hold}, gotHolds)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM holds WHERE book_id = \\$1").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotHolds, err := repo.ListActiveForUpdate(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, gotHolds)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHoldRepository(db)
+
+	hold := &entity.Hold{
+		UserID: 1,
+		BookID: 1,
+		Status: entity.HoldWaiting,
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO holds \\(user_id, book_id, status\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id").
+			ExpectQuery().
+			WithArgs(hold.UserID, hold.BookID, hold.Status).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		id, err := repo.Create(context.Background(), hold)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Active Hold", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO holds").
+			ExpectQuery().
+			WithArgs(hold.UserID, hold.BookID, hold.Status).
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		id, err := repo.Create(context.Background(), hold)
+		assert.Equal(t, ports.ErrHoldActive, err)
+		assert.Zero(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO holds").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		id, err := repo.Create(context.Background(), hold)
+		assert.Error(t, err)
+		assert.Zero(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewHoldRepository(db)
+
+	hold := &entity.Hold{
+		ID:        1,
+		Status:    entity.HoldReady,
+		ExpiresAt: time.Now().AddDate(0, 0, 3),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE holds SET status = \\$1, expires_at = \\$2, updated_at = NOW\\(\\) WHERE id = \\$3").
+			ExpectExec().
+			WithArgs(hold.Status, hold.ExpiresAt, hold.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), hold)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE holds").
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.Update(context.Background(), hold)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE holds").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Update(context.Background(), hold)
+		assert.Equal(t, ports.ErrHoldNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
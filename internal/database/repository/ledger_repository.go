@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type ledgerRepository struct {
+	db *sql.DB
+}
+
+// NewLedgerRepository creates a new instance of ledgerRepository
+func NewLedgerRepository(db *sql.DB) ports.LedgerRepository {
+	return &ledgerRepository{
+		db: db,
+	}
+}
+
+// List lists all charges and payments of a user from the oldest
+func (r *ledgerRepository) List(ctx context.Context, userID int) ([]*entity.LedgerEntry, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM ledger_entries WHERE user_id = $1 ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	entries := []*entity.LedgerEntry{}
+	for rows.Next() {
+		var e entity.LedgerEntry
+		var loanID sql.NullInt64
+
+		err = rows.Scan(&e.ID, &e.UserID, &loanID, &e.Kind, &e.Amount, &e.Note, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		// check if loanID is not NULL
+		if loanID.Valid {
+			e.LoanID = int(loanID.Int64)
+		}
+
+		entries = append(entries, &e)
+	}
+
+	return entries, nil
+}
+
+// Balance sums the fines minus the payments of a user
+func (r *ledgerRepository) Balance(ctx context.Context, userID int) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT COALESCE(SUM(CASE WHEN kind = 'payment' THEN -amount ELSE amount END), 0) FROM ledger_entries WHERE user_id = $1")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var balance int
+
+	err = stmt.QueryRowContext(ctx, userID).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return balance, nil
+}
+
+// Create creates a new ledger entry
+func (r *ledgerRepository) Create(ctx context.Context, e *entity.LedgerEntry) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO ledger_entries (user_id, loan_id, kind, amount, note) VALUES ($1, $2, $3, $4, $5) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var loanID sql.NullInt64
+	if e.LoanID > 0 {
+		loanID = sql.NullInt64{Int64: int64(e.LoanID), Valid: true}
+	}
+
+	err = stmt.QueryRowContext(ctx, e.UserID, loanID, e.Kind, e.Amount, e.Note).Scan(&e.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return e.ID, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+func TestListLedgerEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepository(db)
+
+	entries := []*entity.LedgerEntry{
+		{
+			ID:        1,
+			UserID:    1,
+			LoanID:    1,
+			Kind:      entity.EntryFine,
+			Amount:    75,
+			Note:      "returned 3 day(s) late",
+			CreatedAt: time.Now(),
+		},
+		{
+			ID:        2,
+			UserID:    1,
+			Kind:      entity.EntryPayment,
+			Amount:    75,
+			Note:      "cash",
+			CreatedAt: time.Now(),
+		},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "loan_id", "kind", "amount", "note", "created_at"}).
+			AddRow(entries[0].ID, entries[0].UserID, entries[0].LoanID, entries[0].Kind, entries[0].Amount, entries[0].Note, entries[0].CreatedAt).
+			AddRow(entries[1].ID, entries[1].UserID, nil, entries[1].Kind, entries[1].Amount, entries[1].Note, entries[1].CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM ledger_entries WHERE user_id = \\$1 ORDER BY created_at, id").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		gotEntries, err := repo.List(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, entries, gotEntries)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM ledger_entries WHERE user_id = \\$1").
+			WillReturnError(sql.ErrConnDone)
+
+		gotEntries, err := repo.List(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, gotEntries)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM ledger_entries WHERE user_id = \\$1").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotEntries, err := repo.List(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Nil(t, gotEntries)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COALESCE\\(SUM\\(.+\\), 0\\) FROM ledger_entries WHERE user_id = \\$1").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(150))
+
+		balance, err := repo.Balance(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 150, balance)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COALESCE").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		balance, err := repo.Balance(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Zero(t, balance)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateLedgerEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepository(db)
+
+	t.Run("Fine", func(t *testing.T) {
+		fine := &entity.LedgerEntry{UserID: 1, LoanID: 1, Kind: entity.EntryFine, Amount: 75}
+
+		mock.ExpectPrepare("INSERT INTO ledger_entries \\(user_id, loan_id, kind, amount, note\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id").
+			ExpectQuery().
+			WithArgs(fine.UserID, fine.LoanID, fine.Kind, fine.Amount, fine.Note).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		id, err := repo.Create(context.Background(), fine)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Payment", func(t *testing.T) {
+		payment := &entity.LedgerEntry{UserID: 1, Kind: entity.EntryPayment, Amount: 75, Note: "cash"}
+
+		mock.ExpectPrepare("INSERT INTO ledger_entries").
+			ExpectQuery().
+			WithArgs(payment.UserID, nil, payment.Kind, payment.Amount, payment.Note).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		id, err := repo.Create(context.Background(), payment)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO ledger_entries").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		id, err := repo.Create(context.Background(), &entity.LedgerEntry{UserID: 1, Amount: 75})
+		assert.Error(t, err)
+		assert.Zero(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
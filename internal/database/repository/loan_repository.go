@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
-	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 type loanRepository struct {
@@ -14,39 +16,76 @@ type loanRepository struct {
 }
 
 // NewLoanRepository creates a new instance of loanRepository
-func NewLoanRepository(db *sql.DB) r.LoanRepository {
+func NewLoanRepository(db *sql.DB) ports.LoanRepository {
 	return &loanRepository{
 		db: db,
 	}
 }
 
-// CheckNotReturned verify if a loan not returned exists in the database
-func (r *loanRepository) CheckNotReturned(ctx context.Context, userID int, bookID int) (bool, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT * FROM loans WHERE is_returned = false AND user_id = $1 AND book_id = $2")
+// Get gets loan data by id
+func (r *loanRepository) Get(ctx context.Context, id int) (*entity.Loan, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM loans WHERE id = $1")
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	l := &entity.Loan{}
+	row := stmt.QueryRowContext(ctx, id)
+
+	l, err := scanLoan(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrLoanIDNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return l, nil
+}
+
+// GetNotReturned gets the open loan of a user for a book, or nil if all books are returned
+func (r *loanRepository) GetNotReturned(ctx context.Context, userID int, bookID int) (*entity.Loan, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM loans WHERE is_returned = false AND user_id = $1 AND book_id = $2")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
 
 	row := stmt.QueryRowContext(ctx, userID, bookID)
 
-	err = row.Scan(&l.ID, &l.UserID, &l.BookID, &l.Is_returned, &l.CreatedAt)
+	l, err := scanLoan(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// all books are returned
-			return false, nil
+			return nil, nil
 		}
-		return false, fmt.Errorf("%s: %w", ErrScanData, err)
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return l, nil
+}
+
+// CountNotReturned counts the loans of a user that are not returned yet
+func (r *loanRepository) CountNotReturned(ctx context.Context, userID int) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT COUNT(*) FROM loans WHERE is_returned = false AND user_id = $1")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var count int
+
+	err = stmt.QueryRowContext(ctx, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrScanData, err)
 	}
 
-	return true, nil
+	return count, nil
 }
 
 // Search searches all books a user borrowed
 func (r *loanRepository) Search(ctx context.Context, userID int) ([]*entity.Loan, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT * FROM loans WHERE user_id = $1")
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM loans WHERE user_id = $1")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
@@ -59,79 +98,165 @@ func (r *loanRepository) Search(ctx context.Context, userID int) ([]*entity.Loan
 
 	var loans []*entity.Loan
 	for rows.Next() {
-		var l entity.Loan
-
-		err = rows.Scan(&l.ID, &l.UserID, &l.BookID, &l.Is_returned, &l.CreatedAt)
+		l, err := scanLoan(rows)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
 
-		loans = append(loans, &l)
+		loans = append(loans, l)
 	}
 
 	if len(loans) == 0 {
-		return nil, ErrLoanNotFound
+		return nil, ports.ErrLoanNotFound
 	}
 
 	return loans, nil
 }
 
-// BorrowTransaction borrows a book updating the book amount and creating a new loan
-func (r *loanRepository) BorrowTransaction(ctx context.Context, u *entity.User, b *entity.Book) error {
-	tx, err := r.db.Begin()
+// ListOverdue lists all loans not returned whose due date has passed
+func (r *loanRepository) ListOverdue(ctx context.Context) ([]*entity.Loan, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM loans WHERE is_returned = false AND due_date < NOW() ORDER BY due_date")
 	if err != nil {
-		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
+	defer stmt.Close()
 
-	_, err = tx.ExecContext(ctx, "UPDATE books SET amount = $1 WHERE id = $2", b.Amount, b.ID)
+	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	var loans []*entity.Loan
+	for rows.Next() {
+		l, err := scanLoan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
-		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+
+		loans = append(loans, l)
 	}
 
-	_, err = tx.ExecContext(ctx, "INSERT INTO loans (user_id, book_id) VALUES ($1, $2)", u.ID, b.ID)
+	if len(loans) == 0 {
+		return nil, ports.ErrNoOverdueLoans
+	}
+
+	return loans, nil
+}
+
+// Renew stores the new due date and renewal count of an open loan
+func (r *loanRepository) Renew(ctx context.Context, l *entity.Loan) error {
+	// the loan is only renewed from the renewal it was read at, a concurrent
+	// renewal or return of the same loan leaves no row to update
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE loans SET due_date = $1, renewals = $2, renewed_at = $3 WHERE id = $4 AND is_returned = false AND renewals = $5")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, l.DueDate, l.Renewals, l.RenewedAt, l.ID, l.Renewals-1)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
-		}
 		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("%s: %w", ErrCommit, err)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		// either the loan doesn't exist, it is returned or it was renewed
+		current, err := r.Get(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		if current.Is_returned {
+			return ports.ErrLoanReturned
+		}
+		return ports.ErrLoanRenewed
 	}
 
 	return nil
 }
 
-// ReturnTransaction returns a book updating the book amount and updating the existing loan
-func (r *loanRepository) ReturnTransaction(ctx context.Context, u *entity.User, b *entity.Book) error {
-	tx, err := r.db.Begin()
+// Create creates a new loan of a lent copy, a user can have a single open loan of a book
+func (r *loanRepository) Create(ctx context.Context, l *entity.Loan) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO loans (user_id, book_id, copy_id, due_date) VALUES ($1, $2, $3, $4) RETURNING id")
 	if err != nil {
-		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
+	defer stmt.Close()
 
-	_, err = tx.ExecContext(ctx, "UPDATE books SET amount = $1 WHERE id = $2", b.Amount, b.ID)
+	err = stmt.QueryRowContext(ctx, l.UserID, l.BookID, l.CopyID, l.DueDate).Scan(&l.ID)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		// the open loans index rejects a second open loan
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return 0, ports.ErrLoanNotReturned
 		}
-		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
 	}
 
-	_, err = tx.ExecContext(ctx, "UPDATE loans SET is_returned = $1 WHERE user_id = $2 AND book_id = $3", true, u.ID, b.ID)
+	return l.ID, nil
+}
+
+// Return closes an open loan and gets its copy and return time, a loan is
+// closed only once even when it is returned twice at the same time
+func (r *loanRepository) Return(ctx context.Context, l *entity.Loan) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE loans SET is_returned = true, returned_at = NOW() WHERE id = $1 AND is_returned = false RETURNING returned_at, copy_id")
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var copyID sql.NullInt64
+	err = stmt.QueryRowContext(ctx, l.ID).Scan(&l.ReturnedAt, &copyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// either the loan doesn't exist or it is already returned
+			if _, err := r.Get(ctx, l.ID); err != nil {
+				return err
+			}
+			return ports.ErrLoanReturned
 		}
-		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("%s: %w", ErrCommit, err)
+	l.Is_returned = true
+	// loans returned before copies were tracked have no copy
+	if copyID.Valid {
+		l.CopyID = int(copyID.Int64)
 	}
 
 	return nil
 }
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanLoan scans a loans row into a loan entity
+func scanLoan(s scanner) (*entity.Loan, error) {
+	var l entity.Loan
+	var returnedAt, renewedAt sql.NullTime
+	var copyID sql.NullInt64
+
+	err := s.Scan(&l.ID, &l.UserID, &l.BookID, &l.Is_returned, &l.CreatedAt, &l.DueDate, &returnedAt, &l.Renewals, &renewedAt, &copyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if returnedAt and renewedAt are not NULL
+	if returnedAt.Valid {
+		l.ReturnedAt = returnedAt.Time
+	}
+	if renewedAt.Valid {
+		l.RenewedAt = renewedAt.Time
+	}
+	// loans returned before copies were tracked have no copy
+	if copyID.Valid {
+		l.CopyID = int(copyID.Int64)
+	}
+
+	return &l, nil
+}
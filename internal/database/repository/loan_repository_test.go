@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
-func TestCheckNotReturned(t *testing.T) {
+func TestGetLoan(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -21,45 +23,83 @@ func TestCheckNotReturned(t *testing.T) {
 
 	loan := &entity.Loan{
 		ID:          1,
-		BookID:      1,
 		UserID:      1,
+		BookID:      1,
+		CopyID:      3,
 		Is_returned: false,
+		DueDate:     time.Now().AddDate(0, 0, 14),
+		Renewals:    1,
+		RenewedAt:   time.Now(),
 		CreatedAt:   time.Now(),
 	}
 
 	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "is_returned", "amount", "created_at"}).
-			AddRow(loan.ID, loan.UserID, loan.BookID, loan.Is_returned, loan.CreatedAt)
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"}).
+			AddRow(loan.ID, loan.UserID, loan.BookID, loan.Is_returned, loan.CreatedAt, loan.DueDate, nil, loan.Renewals, loan.RenewedAt, loan.CopyID)
 
-		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND user_id = \\$1 AND book_id = \\$2").
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
 			ExpectQuery().
-			WithArgs(loan.UserID, loan.BookID).
+			WithArgs(loan.ID).
 			WillReturnRows(rows)
 
-		exists, err := repo.CheckNotReturned(context.Background(), loan.UserID, loan.BookID)
+		gotLoan, err := repo.Get(context.Background(), loan.ID)
 		assert.NoError(t, err)
-		assert.True(t, exists)
+		assert.Equal(t, loan, gotLoan)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Prepare Failed", func(t *testing.T) {
-		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND user_id = \\$1 AND book_id = \\$2").
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
 			WillReturnError(sql.ErrConnDone)
 
-		exists, err := repo.CheckNotReturned(context.Background(), loan.UserID, loan.BookID)
+		gotLoan, err := repo.Get(context.Background(), loan.ID)
 		assert.Error(t, err)
-		assert.False(t, exists)
+		assert.Nil(t, gotLoan)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Query Failed", func(t *testing.T) {
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+
+		gotLoan, err := repo.Get(context.Background(), 10)
+		assert.Equal(t, ports.ErrLoanIDNotFound, err)
+		assert.Nil(t, gotLoan)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetNotReturned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoanRepository(db)
+
+	loan := &entity.Loan{
+		ID:          1,
+		BookID:      1,
+		UserID:      1,
+		Is_returned: false,
+		DueDate:     time.Now().AddDate(0, 0, -2),
+		CreatedAt:   time.Now().AddDate(0, 0, -16),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"}).
+			AddRow(loan.ID, loan.UserID, loan.BookID, loan.Is_returned, loan.CreatedAt, loan.DueDate, nil, loan.Renewals, nil, nil)
+
 		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND user_id = \\$1 AND book_id = \\$2").
 			ExpectQuery().
-			WillReturnError(sql.ErrConnDone)
+			WithArgs(loan.UserID, loan.BookID).
+			WillReturnRows(rows)
 
-		exists, err := repo.CheckNotReturned(context.Background(), loan.UserID, loan.BookID)
-		assert.Error(t, err)
-		assert.False(t, exists)
+		gotLoan, err := repo.GetNotReturned(context.Background(), loan.UserID, loan.BookID)
+		assert.NoError(t, err)
+		assert.Equal(t, loan, gotLoan)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -69,9 +109,51 @@ func TestCheckNotReturned(t *testing.T) {
 			WithArgs(10, 10).
 			WillReturnError(sql.ErrNoRows)
 
-		exists, err := repo.CheckNotReturned(context.Background(), 10, 10)
+		gotLoan, err := repo.GetNotReturned(context.Background(), 10, 10)
 		assert.NoError(t, err)
-		assert.False(t, exists)
+		assert.Nil(t, gotLoan)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCountNotReturned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoanRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM loans WHERE is_returned = false AND user_id = \\$1").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		count, err := repo.CountNotReturned(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT").
+			WillReturnError(sql.ErrConnDone)
+
+		count, err := repo.CountNotReturned(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Zero(t, count)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT COUNT").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		count, err := repo.CountNotReturned(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Zero(t, count)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -108,9 +190,9 @@ func TestSearch(t *testing.T) {
 		},
 	}
 	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "is_returned", "amount", "created_at"})
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"})
 		for _, loan := range loans {
-			rows = rows.AddRow(loan.ID, loan.UserID, loan.BookID, loan.Is_returned, loan.CreatedAt)
+			rows = rows.AddRow(loan.ID, loan.UserID, loan.BookID, loan.Is_returned, loan.CreatedAt, loan.DueDate, nil, loan.Renewals, nil, nil)
 		}
 
 		mock.ExpectPrepare("SELECT \\* FROM loans WHERE user_id =").
@@ -163,116 +245,296 @@ func TestSearch(t *testing.T) {
 	})
 }
 
-func TestBorrowTransaction(t *testing.T) {
+func TestListOverdue(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewLoanRepository(db)
 
-	user := &entity.User{
-		ID: 1,
+	loans := []*entity.Loan{
+		{
+			ID:          1,
+			UserID:      1,
+			BookID:      1,
+			Is_returned: false,
+			DueDate:     time.Now().AddDate(0, 0, -2),
+			CreatedAt:   time.Now().AddDate(0, 0, -16),
+		},
+		{
+			ID:          2,
+			UserID:      2,
+			BookID:      1,
+			Is_returned: false,
+			DueDate:     time.Now().AddDate(0, 0, -1),
+			CreatedAt:   time.Now().AddDate(0, 0, -15),
+		},
 	}
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"})
+		for _, loan := range loans {
+			rows = rows.AddRow(loan.ID, loan.UserID, loan.BookID, loan.Is_returned, loan.CreatedAt, loan.DueDate, nil, loan.Renewals, nil, nil)
+		}
 
-	book := &entity.Book{
-		ID:     1,
-		Amount: 5,
-	}
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND due_date < NOW\\(\\) ORDER BY due_date").
+			ExpectQuery().
+			WillReturnRows(rows)
 
-	t.Run("OK", func(t *testing.T) {
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE books SET amount = \\$1 WHERE id = \\$2").
-			WithArgs(book.Amount, book.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectExec("INSERT INTO loans \\(user_id, book_id\\) VALUES \\(\\$1, \\$2\\)").
-			WithArgs(user.ID, book.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectCommit()
-
-		err := repo.BorrowTransaction(context.Background(), user, book)
+		gotLoans, err := repo.ListOverdue(context.Background())
 		assert.NoError(t, err)
+		assert.Equal(t, loans, gotLoans)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Exec Update Books Failed", func(t *testing.T) {
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE books SET amount = \\$1 WHERE id = \\$2").
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND due_date < NOW\\(\\)").
 			WillReturnError(sql.ErrConnDone)
-		mock.ExpectRollback()
 
-		err := repo.BorrowTransaction(context.Background(), user, book)
+		gotLoans, err := repo.ListOverdue(context.Background())
 		assert.Error(t, err)
+		assert.Empty(t, gotLoans)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Exec Insert Loans Failed", func(t *testing.T) {
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE books SET amount = \\$1 WHERE id = \\$2").
-			WithArgs(book.Amount, book.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectExec("INSERT INTO loans \\(user_id, book_id\\) VALUES \\(\\$1, \\$2\\)").
-			WillReturnError(sql.ErrNoRows)
-		mock.ExpectRollback()
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND due_date < NOW\\(\\)").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
 
-		err := repo.BorrowTransaction(context.Background(), user, book)
+		gotLoans, err := repo.ListOverdue(context.Background())
 		assert.Error(t, err)
+		assert.Empty(t, gotLoans)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE is_returned = false AND due_date < NOW\\(\\)").
+			ExpectQuery().
+			WillReturnRows(&sqlmock.Rows{})
+
+		gotLoans, err := repo.ListOverdue(context.Background())
+		assert.Equal(t, ports.ErrNoOverdueLoans, err)
+		assert.Empty(t, gotLoans)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
-func TestReturnTransaction(t *testing.T) {
+func TestRenew(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewLoanRepository(db)
 
-	user := &entity.User{
-		ID: 1,
+	loan := &entity.Loan{
+		ID:        1,
+		DueDate:   time.Now().AddDate(0, 0, 28),
+		Renewals:  1,
+		RenewedAt: time.Now(),
 	}
 
-	book := &entity.Book{
-		ID:     1,
-		Amount: 5,
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE loans SET due_date = \\$1, renewals = \\$2, renewed_at = \\$3 WHERE id = \\$4 AND is_returned = false AND renewals = \\$5").
+			ExpectExec().
+			WithArgs(loan.DueDate, loan.Renewals, loan.RenewedAt, loan.ID, loan.Renewals-1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Renew(context.Background(), loan)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE loans SET due_date").
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.Renew(context.Background(), loan)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE loans SET due_date").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(loan.ID).
+			WillReturnError(sql.ErrNoRows)
+
+		err := repo.Renew(context.Background(), loan)
+		assert.Equal(t, ports.ErrLoanIDNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Returned", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"}).
+			AddRow(loan.ID, 1, 1, true, time.Now(), time.Now(), time.Now(), 0, nil, nil)
+
+		mock.ExpectPrepare("UPDATE loans SET due_date").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(loan.ID).
+			WillReturnRows(rows)
+
+		err := repo.Renew(context.Background(), loan)
+		assert.Equal(t, ports.ErrLoanReturned, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Renewed", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"}).
+			AddRow(loan.ID, 1, 1, false, time.Now(), time.Now(), nil, loan.Renewals, time.Now(), nil)
+
+		mock.ExpectPrepare("UPDATE loans SET due_date").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(loan.ID).
+			WillReturnRows(rows)
+
+		err := repo.Renew(context.Background(), loan)
+		assert.Equal(t, ports.ErrLoanRenewed, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateLoan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoanRepository(db)
+
+	loan := &entity.Loan{
+		UserID:  1,
+		BookID:  1,
+		CopyID:  7,
+		DueDate: time.Now().AddDate(0, 0, 14),
 	}
 
 	t.Run("OK", func(t *testing.T) {
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE books SET amount = \\$1 WHERE id = \\$2").
-			WithArgs(book.Amount, book.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectExec("UPDATE loans SET is_returned = \\$1 WHERE user_id = \\$2 AND book_id = \\$3").
-			WithArgs(true, user.ID, book.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectCommit()
-
-		err := repo.ReturnTransaction(context.Background(), user, book)
+		mock.ExpectPrepare("INSERT INTO loans \\(user_id, book_id, copy_id, due_date\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) RETURNING id").
+			ExpectQuery().
+			WithArgs(loan.UserID, loan.BookID, loan.CopyID, loan.DueDate).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+		id, err := repo.Create(context.Background(), loan)
 		assert.NoError(t, err)
+		assert.Equal(t, 4, id)
+		assert.Equal(t, 4, loan.ID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Returned", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO loans").
+			ExpectQuery().
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		id, err := repo.Create(context.Background(), loan)
+		assert.Equal(t, ports.ErrLoanNotReturned, err)
+		assert.Empty(t, id)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Exec Update Books Failed", func(t *testing.T) {
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE books SET amount = \\$1 WHERE id = \\$2").
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO loans").
+			ExpectQuery().
 			WillReturnError(sql.ErrConnDone)
-		mock.ExpectRollback()
 
-		err := repo.ReturnTransaction(context.Background(), user, book)
+		id, err := repo.Create(context.Background(), loan)
 		assert.Error(t, err)
+		assert.Empty(t, id)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Exec Update Loans Failed", func(t *testing.T) {
-		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE books SET amount = \\$1 WHERE id = \\$2").
-			WithArgs(book.Amount, book.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectExec("UPDATE loans SET is_returned = \\$1 WHERE user_id = \\$2 AND book_id = \\$3").
+}
+
+func TestReturnLoan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoanRepository(db)
+
+	closeLoan := "UPDATE loans SET is_returned = true, returned_at = NOW\\(\\) WHERE id = \\$1 AND is_returned = false RETURNING returned_at, copy_id"
+
+	t.Run("OK", func(t *testing.T) {
+		loan := &entity.Loan{ID: 4}
+		returnedAt := time.Now()
+
+		mock.ExpectPrepare(closeLoan).
+			ExpectQuery().
+			WithArgs(loan.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"returned_at", "copy_id"}).AddRow(returnedAt, 7))
+
+		err := repo.Return(context.Background(), loan)
+		assert.NoError(t, err)
+		assert.True(t, loan.Is_returned)
+		assert.Equal(t, returnedAt, loan.ReturnedAt)
+		assert.Equal(t, 7, loan.CopyID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Without Copy", func(t *testing.T) {
+		loan := &entity.Loan{ID: 2}
+
+		mock.ExpectPrepare(closeLoan).
+			ExpectQuery().
+			WithArgs(loan.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"returned_at", "copy_id"}).AddRow(time.Now(), nil))
+
+		err := repo.Return(context.Background(), loan)
+		assert.NoError(t, err)
+		assert.Zero(t, loan.CopyID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Already Returned", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "book_id", "is_returned", "created_at", "due_date", "returned_at", "renewals", "renewed_at", "copy_id"}).
+			AddRow(4, 1, 1, true, time.Now(), time.Now(), time.Now(), 0, nil, 7)
+
+		mock.ExpectPrepare(closeLoan).
+			ExpectQuery().
+			WithArgs(4).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(4).
+			WillReturnRows(rows)
+
+		err := repo.Return(context.Background(), &entity.Loan{ID: 4})
+		assert.Equal(t, ports.ErrLoanReturned, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare(closeLoan).
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+
+		err := repo.Return(context.Background(), &entity.Loan{ID: 10})
+		assert.Equal(t, ports.ErrLoanIDNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare(closeLoan).
+			ExpectQuery().
 			WillReturnError(sql.ErrConnDone)
-		mock.ExpectRollback()
 
-		err := repo.ReturnTransaction(context.Background(), user, book)
+		err := repo.Return(context.Background(), &entity.Loan{ID: 4})
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type resetTokenRepository struct {
+	db *sql.DB
+}
+
+// NewResetTokenRepository creates a new instance of resetTokenRepository
+func NewResetTokenRepository(db *sql.DB) ports.ResetTokenRepository {
+	return &resetTokenRepository{
+		db: db,
+	}
+}
+
+// GetByHash gets a reset token by the hash of the token mailed to the user
+func (r *resetTokenRepository) GetByHash(ctx context.Context, hash string) (*entity.ResetToken, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM reset_tokens WHERE token_hash = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	var t entity.ResetToken
+	var usedAt sql.NullTime
+
+	err = stmt.QueryRowContext(ctx, hash).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &usedAt, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrResetTokenNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	// check if usedAt is not NULL
+	if usedAt.Valid {
+		t.UsedAt = usedAt.Time
+	}
+
+	return &t, nil
+}
+
+// Create creates a new reset token
+func (r *resetTokenRepository) Create(ctx context.Context, t *entity.ResetToken) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, t.UserID, t.TokenHash, t.ExpiresAt).Scan(&t.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return t.ID, nil
+}
+
+// MarkUsed marks a reset token as used, a token can only be used once
+func (r *resetTokenRepository) MarkUsed(ctx context.Context, id int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE reset_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	// someone else used the token in the meantime
+	if rowsAffected == 0 {
+		return ports.ErrResetTokenNotFound
+	}
+
+	return nil
+}
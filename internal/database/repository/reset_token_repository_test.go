@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetResetTokenByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewResetTokenRepository(db)
+
+	token := &entity.ResetToken{
+		ID:        1,
+		UserID:    1,
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at"}).
+			AddRow(token.ID, token.UserID, token.TokenHash, token.ExpiresAt, nil, token.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM reset_tokens WHERE token_hash = \\$1").
+			ExpectQuery().
+			WithArgs(token.TokenHash).
+			WillReturnRows(rows)
+
+		gotToken, err := repo.GetByHash(context.Background(), token.TokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, token, gotToken)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM reset_tokens WHERE token_hash = \\$1").
+			WillReturnError(sql.ErrConnDone)
+
+		gotToken, err := repo.GetByHash(context.Background(), token.TokenHash)
+		assert.Error(t, err)
+		assert.Nil(t, gotToken)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM reset_tokens WHERE token_hash = \\$1").
+			ExpectQuery().
+			WithArgs("unknown").
+			WillReturnError(sql.ErrNoRows)
+
+		gotToken, err := repo.GetByHash(context.Background(), "unknown")
+		assert.Equal(t, ports.ErrResetTokenNotFound, err)
+		assert.Nil(t, gotToken)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateResetToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewResetTokenRepository(db)
+
+	token := &entity.ResetToken{
+		UserID:    1,
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO reset_tokens").
+			ExpectQuery().
+			WithArgs(token.UserID, token.TokenHash, token.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		id, err := repo.Create(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO reset_tokens").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.Create(context.Background(), token)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMarkResetTokenUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewResetTokenRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE reset_tokens SET used_at = NOW\\(\\) WHERE id = \\$1 AND used_at IS NULL").
+			ExpectExec().
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.MarkUsed(context.Background(), 1)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Already Used", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE reset_tokens SET used_at").
+			ExpectExec().
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.MarkUsed(context.Background(), 1)
+		assert.Equal(t, ports.ErrResetTokenNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE reset_tokens SET used_at").
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.MarkUsed(context.Background(), 1)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
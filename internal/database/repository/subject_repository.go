@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type subjectRepository struct {
+	db *sql.DB
+}
+
+// NewSubjectRepository creates a new instance of subjectRepository
+func NewSubjectRepository(db *sql.DB) ports.SubjectRepository {
+	return &subjectRepository{
+		db: db,
+	}
+}
+
+// Get gets subject data by id
+func (r *subjectRepository) Get(ctx context.Context, id int) (*entity.Subject, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM subjects WHERE id = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, id)
+
+	s, err := scanSubject(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrSubjectNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+	}
+
+	return s, nil
+}
+
+// List lists every subject in name order, the taxonomy is rebuilt from the parents
+func (r *subjectRepository) List(ctx context.Context) ([]*entity.Subject, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM subjects ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	subjects := []*entity.Subject{}
+	for rows.Next() {
+		s, err := scanSubject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		subjects = append(subjects, s)
+	}
+
+	return subjects, nil
+}
+
+// Create creates a new subject, a zero parent id is stored as NULL
+func (r *subjectRepository) Create(ctx context.Context, s *entity.Subject) (int, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO subjects (name, parent_id) VALUES ($1, NULLIF($2, 0)) RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, s.Name, s.ParentID).Scan(&s.ID)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return 0, ports.ErrSubjectAlreadyExists
+		}
+		return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return s.ID, nil
+}
+
+// Update updates the name and parent of a subject
+func (r *subjectRepository) Update(ctx context.Context, s *entity.Subject) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE subjects SET name = $1, parent_id = NULLIF($2, 0), updated_at = NOW() WHERE id = $3")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, s.Name, s.ParentID, s.ID)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrSubjectAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrSubjectNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a subject by id and untags its books, subjects with
+// subjects below them can't be deleted
+func (r *subjectRepository) Delete(ctx context.Context, id int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "DELETE FROM subjects WHERE id = $1")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == foreignKeyViolationCode {
+			return ports.ErrSubjectHasChildren
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return ports.ErrSubjectNotFound
+	}
+
+	return nil
+}
+
+// scanSubject scans a subjects row into a subject entity
+func scanSubject(s scanner) (*entity.Subject, error) {
+	var subject entity.Subject
+	var parentID sql.NullInt64
+	var updatedAt sql.NullTime
+
+	err := s.Scan(&subject.ID, &subject.Name, &parentID, &updatedAt, &subject.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// top subjects have a NULL parent
+	if parentID.Valid {
+		subject.ParentID = int(parentID.Int64)
+	}
+
+	// check if updatedAt is not NULL
+	if updatedAt.Valid {
+		subject.UpdatedAt = updatedAt.Time
+	}
+
+	return &subject, nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetSubject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSubjectRepository(db)
+
+	subject := &entity.Subject{
+		ID:        2,
+		Name:      "Programming",
+		ParentID:  1,
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "updated_at", "created_at"}).
+			AddRow(subject.ID, subject.Name, subject.ParentID, nil, subject.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM subjects WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(subject.ID).
+			WillReturnRows(rows)
+
+		gotSubject, err := repo.Get(context.Background(), subject.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, subject, gotSubject)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Top Subject", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "updated_at", "created_at"}).
+			AddRow(1, "Computing", nil, nil, subject.CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM subjects WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		gotSubject, err := repo.Get(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, gotSubject.ParentID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM subjects WHERE id = \\$1").
+			ExpectQuery().
+			WithArgs(10).
+			WillReturnError(sql.ErrNoRows)
+
+		gotSubject, err := repo.Get(context.Background(), 10)
+		assert.Equal(t, ports.ErrSubjectNotFound, err)
+		assert.Nil(t, gotSubject)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListSubjects(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSubjectRepository(db)
+
+	subjects := []*entity.Subject{
+		{ID: 1, Name: "Computing", CreatedAt: time.Now()},
+		{ID: 2, Name: "Programming", ParentID: 1, CreatedAt: time.Now()},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id", "updated_at", "created_at"}).
+			AddRow(subjects[0].ID, subjects[0].Name, nil, nil, subjects[0].CreatedAt).
+			AddRow(subjects[1].ID, subjects[1].Name, subjects[1].ParentID, nil, subjects[1].CreatedAt)
+
+		mock.ExpectPrepare("SELECT \\* FROM subjects ORDER BY name, id").
+			ExpectQuery().
+			WillReturnRows(rows)
+
+		gotSubjects, err := repo.List(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, subjects, gotSubjects)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM subjects").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		gotSubjects, err := repo.List(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, gotSubjects)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateSubject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSubjectRepository(db)
+
+	subject := &entity.Subject{Name: "Programming", ParentID: 1}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO subjects \\(name, parent_id\\) VALUES \\(\\$1, NULLIF\\(\\$2, 0\\)\\)").
+			ExpectQuery().
+			WithArgs(subject.Name, subject.ParentID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		id, err := repo.Create(context.Background(), subject)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Already Exists", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO subjects").
+			ExpectQuery().
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		id, err := repo.Create(context.Background(), subject)
+		assert.Equal(t, ports.ErrSubjectAlreadyExists, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Query Failed", func(t *testing.T) {
+		mock.ExpectPrepare("INSERT INTO subjects").
+			ExpectQuery().
+			WillReturnError(sql.ErrConnDone)
+
+		id, err := repo.Create(context.Background(), subject)
+		assert.Error(t, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateSubject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSubjectRepository(db)
+
+	subject := &entity.Subject{ID: 2, Name: "Programming Languages", ParentID: 1}
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE subjects SET name = \\$1, parent_id = NULLIF\\(\\$2, 0\\), updated_at = NOW\\(\\) WHERE id = \\$3").
+			ExpectExec().
+			WithArgs(subject.Name, subject.ParentID, subject.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), subject)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Already Exists", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE subjects").
+			ExpectExec().
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		err := repo.Update(context.Background(), subject)
+		assert.Equal(t, ports.ErrSubjectAlreadyExists, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE subjects").
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Update(context.Background(), subject)
+		assert.Equal(t, ports.ErrSubjectNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteSubject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSubjectRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM subjects WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Delete(context.Background(), 2)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Has Children", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM subjects WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(1).
+			WillReturnError(&pq.Error{Code: foreignKeyViolationCode})
+
+		err := repo.Delete(context.Background(), 1)
+		assert.Equal(t, ports.ErrSubjectHasChildren, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM subjects WHERE id = \\$1").
+			ExpectExec().
+			WithArgs(10).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Delete(context.Background(), 10)
+		assert.Equal(t, ports.ErrSubjectNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+// txKey is the context key of the transaction of a WithinTx call
+type txKey struct{}
+
+type txManager struct {
+	db *sql.DB
+}
+
+// NewTxManager creates a new instance of txManager
+func NewTxManager(db *sql.DB) ports.TxManager {
+	return &txManager{
+		db: db,
+	}
+}
+
+// WithinTx runs fn in a transaction, committed when fn succeeds and rolled back when it fails.
+// A call within the context of another call joins the outer transaction
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrBeginTransaction, err)
+	}
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%s: %w", ErrRollback, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", ErrCommit, err)
+	}
+
+	return nil
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction of the context, or the database outside of transactions
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// repoTx is a transaction of a repository method, within the context of a
+// WithinTx call it joins that transaction and leaves the commit and the
+// rollback to it
+type repoTx struct {
+	*sql.Tx
+	joined bool
+}
+
+// beginTx starts a transaction or joins the transaction of the context
+func beginTx(ctx context.Context, db *sql.DB) (*repoTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &repoTx{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repoTx{Tx: tx}, nil
+}
+
+// Commit commits the transaction unless it was joined
+func (tx *repoTx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls the transaction back unless it was joined, the error
+// returned to the caller rolls back the joined transaction instead
+func (tx *repoTx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestWithinTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	txManager := NewTxManager(db)
+	loanRepo := NewLoanRepository(db)
+	copyRepo := NewCopyRepository(db)
+
+	errFailed := errors.New("failed")
+
+	t.Run("Commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectPrepare("UPDATE copies SET status = \\$1").
+			ExpectQuery().
+			WithArgs(entity.CopyOnLoan, 1, entity.CopyAvailable).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectPrepare("INSERT INTO loans").
+			ExpectQuery().
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectCommit()
+
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			copyID, err := copyRepo.Lend(ctx, 1)
+			if err != nil {
+				return err
+			}
+
+			_, err = loanRepo.Create(ctx, &entity.Loan{UserID: 1, BookID: 1, CopyID: copyID})
+			return err
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Rollback", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectPrepare("UPDATE copies SET status = \\$1").
+			ExpectQuery().
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectRollback()
+
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			if _, err := copyRepo.Lend(ctx, 1); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		assert.Equal(t, errFailed, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Nested", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectPrepare("SELECT \\* FROM loans WHERE id = \\$1").
+			ExpectQuery().
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectCommit()
+
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			return txManager.WithinTx(ctx, func(ctx context.Context) error {
+				_, err := loanRepo.Get(ctx, 10)
+				if err != ports.ErrLoanIDNotFound {
+					return err
+				}
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Repository Transaction Joins", func(t *testing.T) {
+		m := &entity.InventoryMovement{BookID: 1, UserID: 2, Kind: entity.MovementLost, Quantity: 1, Reason: "lost"}
+
+		// the adjustment doesn't commit on its own and is rolled back with the rest
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE copies SET status = \\$3").
+			WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("B1-2"))
+		mock.ExpectQuery("INSERT INTO inventory_movements").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, time.Now()))
+		mock.ExpectRollback()
+
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := copyRepo.Adjust(ctx, m); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		assert.Equal(t, errFailed, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Begin Failed", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
+
+		called := false
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.Error(t, err)
+		assert.False(t, called)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Commit Failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(sql.ErrConnDone)
+
+		err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
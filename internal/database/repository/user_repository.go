@@ -4,11 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/lib/pq"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
-	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 const duplicatedKeyValueCode = "23505"
@@ -18,7 +19,7 @@ type userRepository struct {
 }
 
 // NewUserRepository creates a new instance of UserRepository
-func NewUserRepository(db *sql.DB) r.UserRepository {
+func NewUserRepository(db *sql.DB) ports.UserRepository {
 	return &userRepository{
 		db: db,
 	}
@@ -26,29 +27,88 @@ func NewUserRepository(db *sql.DB) r.UserRepository {
 
 // Get gets user info by id
 func (r *userRepository) Get(ctx context.Context, id int) (*entity.User, error) {
-	stmt, err := r.db.PrepareContext(ctx, "SELECT * FROM users WHERE id = $1")
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM users WHERE id = $1")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	u := &entity.User{}
+	row := stmt.QueryRowContext(ctx, id)
+
+	u, err := scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrUserNotFound
+		} else {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+	}
+
+	return u, nil
+}
+
+// GetForUpdate gets user info by id and locks the row until the transaction
+// in the context ends
+func (r *userRepository) GetForUpdate(ctx context.Context, id int) (*entity.User, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM users WHERE id = $1 FOR UPDATE")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
 
 	row := stmt.QueryRowContext(ctx, id)
 
-	var updatedAt sql.NullTime
-	err = row.Scan(&u.ID, &u.Username, &u.Password, &u.Email, &updatedAt, &u.CreatedAt)
+	u, err := scanUser(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, ErrUserNotFound
+			return nil, ports.ErrUserNotFound
 		} else {
 			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
 		}
 	}
 
-	// check if updatedAt is not NULL
-	if updatedAt.Valid {
-		u.UpdatedAt = updatedAt.Time
+	return u, nil
+}
+
+// GetByUsername gets user info by username
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM users WHERE username = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, username)
+
+	u, err := scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrUserNotFound
+		} else {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+	}
+
+	return u, nil
+}
+
+// GetByEmail gets user info by email
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "SELECT * FROM users WHERE email = $1")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, email)
+
+	u, err := scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ports.ErrUserNotFound
+		} else {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
 	}
 
 	return u, nil
@@ -56,17 +116,17 @@ func (r *userRepository) Get(ctx context.Context, id int) (*entity.User, error)
 
 // Create creates a new user
 func (r *userRepository) Create(ctx context.Context, u *entity.User) (int, error) {
-	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO users (username, password, email) VALUES ($1, $2, $3) RETURNING id")
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "INSERT INTO users (username, password, email, category, role) VALUES ($1, $2, $3, $4, $5) RETURNING id")
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRowContext(ctx, u.Username, u.Password, u.Email).Scan(&u.ID)
+	err = stmt.QueryRowContext(ctx, u.Username, u.Password, u.Email, u.Category, u.Role).Scan(&u.ID)
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok && pqErr.Code == duplicatedKeyValueCode {
-			return 0, ErrAlreadyExists
+			return 0, ports.ErrAlreadyExists
 		} else {
 			return 0, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
 		}
@@ -75,22 +135,86 @@ func (r *userRepository) Create(ctx context.Context, u *entity.User) (int, error
 	return u.ID, nil
 }
 
-// Update updates an user
+// Update updates an user at the version it was read and moves it to the next version
 func (r *userRepository) Update(ctx context.Context, u *entity.User) error {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE users SET username = $1, password = $2, email = $3, updated_at = NOW() WHERE id = $4")
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE users SET username = $1, email = $2, category = $3, version = version + 1, updated_at = NOW() "+
+		"WHERE id = $4 AND version = $5 RETURNING version")
 	if err != nil {
 		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, u.Username, u.Password, u.Email, u.ID)
+	err = stmt.QueryRowContext(ctx, u.Username, u.Email, u.Category, u.ID, u.Version).Scan(&u.Version)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return versionMismatch(ctx, conn(ctx, r.db), "users", u.ID, ports.ErrUserNotFound)
+		}
 		pqErr, ok := err.(*pq.Error)
 		if ok && pqErr.Code == duplicatedKeyValueCode {
-			return ErrAlreadyExists
-		} else {
-			return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+			return ports.ErrAlreadyExists
 		}
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// Patch writes the columns of the fields of the patch, taking their values
+// from the patched user, at the version it was read
+func (r *userRepository) Patch(ctx context.Context, u *entity.User, p *entity.UserPatch) error {
+	var sets []string
+	var args []any
+
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if p.Username != nil {
+		set("username", u.Username)
+	}
+	if p.Email != nil {
+		set("email", u.Email)
+	}
+	if p.Category != nil {
+		set("category", u.Category)
+	}
+	sets = append(sets, "version = version + 1", "updated_at = NOW()")
+
+	args = append(args, u.ID, u.Version)
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, fmt.Sprintf("UPDATE users SET %s WHERE id = $%d AND version = $%d RETURNING version",
+		strings.Join(sets, ", "), len(args)-1, len(args)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, args...).Scan(&u.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return versionMismatch(ctx, conn(ctx, r.db), "users", u.ID, ports.ErrUserNotFound)
+		}
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code == duplicatedKeyValueCode {
+			return ports.ErrAlreadyExists
+		}
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// UpdateRole updates the role of an user
+func (r *userRepository) UpdateRole(ctx context.Context, id int, role entity.Role) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE users SET role = $1, version = version + 1, updated_at = NOW() WHERE id = $2")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, role, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -99,21 +223,21 @@ func (r *userRepository) Update(ctx context.Context, u *entity.User) error {
 	}
 
 	if rowsAffected == 0 {
-		return ErrUserNotFound
+		return ports.ErrUserNotFound
 	}
 
 	return nil
 }
 
-// Delete deletes an user by id
-func (r *userRepository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM users WHERE id = $1")
+// UpdatePassword replaces the password hash of an user
+func (r *userRepository) UpdatePassword(ctx context.Context, id int, hash string) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "UPDATE users SET password = $1, version = version + 1, updated_at = NOW() WHERE id = $2")
 	if err != nil {
 		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, id)
+	result, err := stmt.ExecContext(ctx, hash, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
 	}
@@ -124,8 +248,51 @@ func (r *userRepository) Delete(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return ErrUserNotFound
+		return ports.ErrUserNotFound
 	}
 
 	return nil
 }
+
+// Delete deletes an user by id at the version it was read
+func (r *userRepository) Delete(ctx context.Context, id, version int) error {
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, "DELETE FROM users WHERE id = $1 AND version = $2")
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrPrepareStatement, err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, id, version)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	if rowsAffected == 0 {
+		return versionMismatch(ctx, conn(ctx, r.db), "users", id, ports.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// scanUser scans a users row in the table column order
+func scanUser(s scanner) (*entity.User, error) {
+	u := &entity.User{}
+
+	var updatedAt sql.NullTime
+	err := s.Scan(&u.ID, &u.Username, &u.Password, &u.Email, &updatedAt, &u.CreatedAt, &u.Category, &u.Role, &u.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if updatedAt is not NULL
+	if updatedAt.Valid {
+		u.UpdatedAt = updatedAt.Time
+	}
+
+	return u, nil
+}
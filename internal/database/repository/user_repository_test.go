@@ -3,13 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 func TestGetUser(t *testing.T) {
@@ -24,13 +27,15 @@ func TestGetUser(t *testing.T) {
 		Username:  "user135",
 		Password:  "secret",
 		Email:     "user135@email.com",
+		Category:  entity.CategoryStudent,
+		Role:      entity.RoleLibrarian,
 		UpdatedAt: time.Time{},
 		CreatedAt: time.Now(),
 	}
 
 	t.Run("OK", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "amount", "updated_at", "created_at"}).
-			AddRow(user.ID, user.Username, user.Password, user.Email, user.UpdatedAt, user.CreatedAt)
+		rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "updated_at", "created_at", "category", "role", "version"}).
+			AddRow(user.ID, user.Username, user.Password, user.Email, user.UpdatedAt, user.CreatedAt, user.Category, user.Role, user.Version)
 
 		mock.ExpectPrepare("SELECT \\* FROM users WHERE id = ").
 			ExpectQuery().
@@ -79,6 +84,155 @@ func TestGetUser(t *testing.T) {
 	})
 }
 
+func TestGetUserForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	user := &entity.User{
+		ID:        1,
+		Username:  "user135",
+		Password:  "secret",
+		Email:     "user135@email.com",
+		Category:  entity.CategoryStudent,
+		Role:      entity.RolePatron,
+		CreatedAt: time.Now(),
+		Version:   1,
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "updated_at", "created_at", "category", "role", "version"}).
+			AddRow(user.ID, user.Username, user.Password, user.Email, user.UpdatedAt, user.CreatedAt, user.Category, user.Role, user.Version)
+
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE id = \\$1 FOR UPDATE").
+			ExpectQuery().
+			WithArgs(user.ID).
+			WillReturnRows(rows)
+
+		gotUser, err := repo.GetForUpdate(context.Background(), user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, user, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE id = \\$1 FOR UPDATE").
+			ExpectQuery().
+			WithArgs(user.ID).
+			WillReturnError(sql.ErrNoRows)
+
+		gotUser, err := repo.GetForUpdate(context.Background(), user.ID)
+		assert.ErrorIs(t, err, ports.ErrUserNotFound)
+		assert.Nil(t, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUserByUsername(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	user := &entity.User{
+		ID:        1,
+		Username:  "user135",
+		Password:  "secret",
+		Email:     "user135@email.com",
+		Category:  entity.CategoryGuest,
+		Role:      entity.RolePatron,
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "updated_at", "created_at", "category", "role", "version"}).
+			AddRow(user.ID, user.Username, user.Password, user.Email, nil, user.CreatedAt, user.Category, user.Role, user.Version)
+
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE username = \\$1").
+			ExpectQuery().
+			WithArgs(user.Username).
+			WillReturnRows(rows)
+
+		gotUser, err := repo.GetByUsername(context.Background(), user.Username)
+		assert.NoError(t, err)
+		assert.Equal(t, user, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Prepare Failed", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE username = \\$1").
+			WillReturnError(sql.ErrConnDone)
+
+		gotUser, err := repo.GetByUsername(context.Background(), user.Username)
+		assert.Error(t, err)
+		assert.Nil(t, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE username = \\$1").
+			ExpectQuery().
+			WithArgs("nobody").
+			WillReturnError(sql.ErrNoRows)
+
+		gotUser, err := repo.GetByUsername(context.Background(), "nobody")
+		assert.Equal(t, ports.ErrUserNotFound, err)
+		assert.Nil(t, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	user := &entity.User{
+		ID:        1,
+		Username:  "user135",
+		Password:  "secret",
+		Email:     "user135@email.com",
+		Category:  entity.CategoryGuest,
+		Role:      entity.RolePatron,
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "updated_at", "created_at", "category", "role", "version"}).
+			AddRow(user.ID, user.Username, user.Password, user.Email, nil, user.CreatedAt, user.Category, user.Role, user.Version)
+
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE email = \\$1").
+			ExpectQuery().
+			WithArgs(user.Email).
+			WillReturnRows(rows)
+
+		gotUser, err := repo.GetByEmail(context.Background(), user.Email)
+		assert.NoError(t, err)
+		assert.Equal(t, user, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("SELECT \\* FROM users WHERE email = \\$1").
+			ExpectQuery().
+			WithArgs("nobody@email.com").
+			WillReturnError(sql.ErrNoRows)
+
+		gotUser, err := repo.GetByEmail(context.Background(), "nobody@email.com")
+		assert.Equal(t, ports.ErrUserNotFound, err)
+		assert.Nil(t, gotUser)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestCreateUser(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -90,6 +244,8 @@ func TestCreateUser(t *testing.T) {
 		Username: "user135",
 		Password: "secret",
 		Email:    "user135@email.com",
+		Category: entity.CategoryGuest,
+		Role:     entity.RolePatron,
 	}
 
 	t.Run("OK", func(t *testing.T) {
@@ -98,7 +254,7 @@ func TestCreateUser(t *testing.T) {
 
 		mock.ExpectPrepare("INSERT INTO users").
 			ExpectQuery().
-			WithArgs(user.Username, user.Password, user.Email).
+			WithArgs(user.Username, user.Password, user.Email, user.Category, user.Role).
 			WillReturnRows(rows)
 
 		id, err := repo.Create(context.Background(), user)
@@ -142,16 +298,23 @@ func TestUpdateUser(t *testing.T) {
 		Username: "user135",
 		Password: "secret",
 		Email:    "user135@email.com",
+		Category: entity.CategoryGuest,
+		Role:     entity.RolePatron,
+		Version:  2,
 	}
 
+	userExists := "SELECT EXISTS \\(SELECT 1 FROM users WHERE id = \\$1\\)"
+
 	t.Run("OK", func(t *testing.T) {
 		mock.ExpectPrepare("UPDATE users").
-			ExpectExec().
-			WithArgs(user.Username, user.Password, user.Email, user.ID).
-			WillReturnResult(sqlmock.NewResult(int64(user.ID), 1))
+			ExpectQuery().
+			WithArgs(user.Username, user.Email, user.Category, user.ID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
 
-		err := repo.Update(context.Background(), user)
+		updated := *user
+		err := repo.Update(context.Background(), &updated)
 		assert.NoError(t, err)
+		assert.Equal(t, 3, updated.Version)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -165,10 +328,10 @@ func TestUpdateUser(t *testing.T) {
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
-	t.Run("Exec Failed", func(t *testing.T) {
+	t.Run("Query Failed", func(t *testing.T) {
 		mock.ExpectPrepare("UPDATE users").
-			ExpectExec().
-			WithArgs(user.Username, user.Password, user.Email, user.ID).
+			ExpectQuery().
+			WithArgs(user.Username, user.Email, user.Category, user.ID, 2).
 			WillReturnError(sql.ErrConnDone)
 
 		err := repo.Update(context.Background(), user)
@@ -179,13 +342,169 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("Not Found", func(t *testing.T) {
 		mock.ExpectPrepare("UPDATE users").
-			ExpectExec().
-			WithArgs(user.Username, user.Password, user.Email, user.ID).
-			WillReturnResult(sqlmock.NewResult(int64(user.ID), 0))
+			ExpectQuery().
+			WithArgs(user.Username, user.Email, user.Category, user.ID, 2).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(userExists).
+			WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
 		err := repo.Update(context.Background(), user)
+		assert.Equal(t, ports.ErrUserNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users").
+			ExpectQuery().
+			WithArgs(user.Username, user.Email, user.Category, user.ID, 2).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(userExists).
+			WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := repo.Update(context.Background(), user)
+		assert.Equal(t, ports.ErrVersionConflict, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	user := &entity.User{
+		ID:       1,
+		Username: "user135",
+		Password: "secret",
+		Email:    "user135@email.com",
+		Category: entity.CategoryStaff,
+		Version:  2,
+	}
+
+	email := user.Email
+	category := user.Category
+
+	t.Run("Patched Columns", func(t *testing.T) {
+		mock.ExpectPrepare(regexp.QuoteMeta("UPDATE users SET email = $1, category = $2, version = version + 1, updated_at = NOW() WHERE id = $3 AND version = $4 RETURNING version")).
+			ExpectQuery().
+			WithArgs(user.Email, user.Category, user.ID, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+		patched := *user
+		err := repo.Patch(context.Background(), &patched, &entity.UserPatch{Email: &email, Category: &category})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, patched.Version)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Duplicated", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET email = \\$1").
+			ExpectQuery().
+			WillReturnError(&pq.Error{Code: duplicatedKeyValueCode})
+
+		err := repo.Patch(context.Background(), user, &entity.UserPatch{Email: &email})
+		assert.Equal(t, ports.ErrAlreadyExists, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET email = \\$1").
+			ExpectQuery().
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM users WHERE id = \\$1\\)").
+			WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := repo.Patch(context.Background(), user, &entity.UserPatch{Email: &email})
+		assert.Equal(t, ports.ErrVersionConflict, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET role = \\$1, version = version \\+ 1, updated_at = NOW\\(\\) WHERE id = \\$2").
+			ExpectExec().
+			WithArgs(entity.RoleLibrarian, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.UpdateRole(context.Background(), 1, entity.RoleLibrarian)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET role").
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.UpdateRole(context.Background(), 1, entity.RoleLibrarian)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET role").
+			ExpectExec().
+			WithArgs(entity.RoleLibrarian, 10).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.UpdateRole(context.Background(), 10, entity.RoleLibrarian)
+		assert.Equal(t, ports.ErrUserNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET password = \\$1, version = version \\+ 1, updated_at = NOW\\(\\) WHERE id = \\$2").
+			ExpectExec().
+			WithArgs("hash", 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.UpdatePassword(context.Background(), 1, "hash")
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Exec Failed", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET password").
+			ExpectExec().
+			WillReturnError(sql.ErrConnDone)
+
+		err := repo.UpdatePassword(context.Background(), 1, "hash")
 		assert.Error(t, err)
 
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectPrepare("UPDATE users SET password").
+			ExpectExec().
+			WithArgs("hash", 10).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.UpdatePassword(context.Background(), 10, "hash")
+		assert.Equal(t, ports.ErrUserNotFound, err)
+
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -197,13 +516,15 @@ func TestDeleteUser(t *testing.T) {
 
 	repo := NewUserRepository(db)
 
+	userExists := "SELECT EXISTS \\(SELECT 1 FROM users WHERE id = \\$1\\)"
+
 	t.Run("OK", func(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM users WHERE id =").
 			ExpectExec().
-			WithArgs(1).
+			WithArgs(1, 2).
 			WillReturnResult(sqlmock.NewResult(int64(1), 1))
 
-		err := repo.Delete(context.Background(), 1)
+		err := repo.Delete(context.Background(), 1, 2)
 		assert.NoError(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -212,7 +533,7 @@ func TestDeleteUser(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM users WHERE id =").
 			WillReturnError(sql.ErrConnDone)
 
-		err := repo.Delete(context.Background(), 1)
+		err := repo.Delete(context.Background(), 1, 2)
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -220,10 +541,10 @@ func TestDeleteUser(t *testing.T) {
 	t.Run("Exec Failed", func(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM users WHERE id =").
 			ExpectExec().
-			WithArgs(1).
+			WithArgs(1, 2).
 			WillReturnError(sql.ErrConnDone)
 
-		err := repo.Delete(context.Background(), 1)
+		err := repo.Delete(context.Background(), 1, 2)
 		assert.Error(t, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -231,11 +552,28 @@ func TestDeleteUser(t *testing.T) {
 	t.Run("Not Found", func(t *testing.T) {
 		mock.ExpectPrepare("DELETE FROM users WHERE id =").
 			ExpectExec().
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(int64(1), 0))
+		mock.ExpectQuery(userExists).
 			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.Delete(context.Background(), 1, 2)
+		assert.Equal(t, ports.ErrUserNotFound, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		mock.ExpectPrepare("DELETE FROM users WHERE id =").
+			ExpectExec().
+			WithArgs(1, 2).
 			WillReturnResult(sqlmock.NewResult(int64(1), 0))
+		mock.ExpectQuery(userExists).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
-		err := repo.Delete(context.Background(), 1)
-		assert.Error(t, err)
+		err := repo.Delete(context.Background(), 1, 2)
+		assert.Equal(t, ports.ErrVersionConflict, err)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+// versionMismatch tells why a write of a row at a version matched no row,
+// either the row was changed since it was read or it doesn't exist
+func versionMismatch(ctx context.Context, q querier, table string, id int, notFound error) error {
+	var exists bool
+
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	if exists {
+		return ports.ErrVersionConflict
+	}
+
+	return notFound
+}
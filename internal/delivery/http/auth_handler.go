@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type authHandler struct {
+	AuthUsecase uc.AuthUsecase
+}
+
+// NewAuthHandler creates a new instance of authHandler
+func NewAuthHandler(r *chi.Mux, useCase uc.AuthUsecase) {
+	handler := &authHandler{
+		AuthUsecase: useCase,
+	}
+
+	r.Route("/v1/auth", func(r chi.Router) {
+		r.Post("/login", handler.Login)
+		r.Post("/forgot", handler.ForgotPassword)
+		r.Post("/reset", handler.ResetPassword)
+	})
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (h *authHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	token, err := h.AuthUsecase.Login(ctx, req.Username, req.Password)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == ucErr.ErrInvalidCredentials {
+				http.Error(w, invalidCredentials, http.StatusUnauthorized)
+			} else {
+				http.Error(w, login, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(token); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, login, http.StatusInternalServerError)
+		return
+	}
+}
+
+type ForgotRequest struct {
+	Email string `json:"email"`
+}
+
+func (h *authHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	// the same answer is given for unknown emails and failed mails so callers
+	// can't learn which emails are registered, failures are only logged
+	err = h.AuthUsecase.ForgotPassword(r.Context(), req.Email)
+	if err != nil {
+		log.Error().Msg(err.Error())
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type ResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (h *authHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.AuthUsecase.ResetPassword(ctx, req.Token, req.Password)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case ucErr.ErrInvalidResetToken:
+				http.Error(w, invalidResetToken, http.StatusBadRequest)
+			case entity.ErrEmptyUserField, entity.ErrFieldWithSpaces, entity.ErrShortPassword, entity.ErrLongPassword:
+				http.Error(w, invalidPassword, http.StatusBadRequest)
+			default:
+				http.Error(w, resetPassword, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
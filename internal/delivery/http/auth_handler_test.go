@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+func TestLogin(t *testing.T) {
+	token := &entity.Token{Value: "header.payload.signature", ExpiresAt: time.Now().Add(time.Hour)}
+
+	testCases := map[string]struct {
+		body          any
+		buildStubs    func(uc *mock.MockAuthUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			body: LoginRequest{Username: "user123", Password: "password123"},
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Login(gomock.Any(), gomock.Eq("user123"), gomock.Eq("password123")).
+					Times(1).
+					Return(token, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got entity.Token
+				err := json.NewDecoder(recorder.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, token.Value, got.Value)
+			},
+		},
+		"Invalid Body": {
+			body: "invalid body",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Login(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Credentials": {
+			body: LoginRequest{Username: "user123", Password: "wrong"},
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Login(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, ucErr.ErrInvalidCredentials)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			body: LoginRequest{Username: "user123", Password: "password123"},
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Login(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/auth/login", bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			router := chi.NewRouter()
+			NewAuthHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	testCases := map[string]struct {
+		body          any
+		buildStubs    func(uc *mock.MockAuthUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			body: ForgotRequest{Email: "user123@example.com"},
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ForgotPassword(gomock.Any(), gomock.Eq("user123@example.com")).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			body: "invalid body",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ForgotPassword(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			body: ForgotRequest{Email: "user123@example.com"},
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ForgotPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusAccepted, recorder.Code, "failures look like unknown emails")
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/auth/forgot", bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			router := chi.NewRouter()
+			NewAuthHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	req := ResetRequest{Token: "token", Password: "password456"}
+
+	testCases := map[string]struct {
+		body          any
+		buildStubs    func(uc *mock.MockAuthUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			body: req,
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ResetPassword(gomock.Any(), gomock.Eq(req.Token), gomock.Eq(req.Password)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			body: "invalid body",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Token": {
+			body: req,
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrInvalidResetToken)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Short Password": {
+			body: ResetRequest{Token: "token", Password: "short"},
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrShortPassword)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			body: req,
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/auth/reset", bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			router := chi.NewRouter()
+			NewAuthHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
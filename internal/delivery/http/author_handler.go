@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type authorHandler struct {
+	AuthorUsecase uc.AuthorUsecase
+}
+
+// NewAuthorHandler creates a new instance of authorHandler
+func NewAuthorHandler(r *chi.Mux, useCase uc.AuthorUsecase) {
+	handler := &authorHandler{
+		AuthorUsecase: useCase,
+	}
+
+	r.Route("/v1/authors", func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Get("/", handler.ListAuthors)
+		r.Get("/{id}", handler.GetAuthor)
+		// only librarians manage the catalog
+		r.Group(func(r chi.Router) {
+			r.Use(requireLibrarian)
+
+			r.Post("/", handler.CreateAuthor)
+			r.Put("/{id}", handler.UpdateAuthor)
+			r.Delete("/{id}", handler.DeleteAuthor)
+		})
+	})
+}
+
+func (h *authorHandler) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidAuthorID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	a, err := h.AuthorUsecase.GetAuthor(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrAuthorNotFound {
+				http.Error(w, authorNotFound, http.StatusNotFound)
+			} else {
+				http.Error(w, getAuthor, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newAuthorResponse(a)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, getAuthor, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *authorHandler) ListAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	authors, err := h.AuthorUsecase.ListAuthors(ctx)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			http.Error(w, listAuthors, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newAuthorResponses(authors)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listAuthors, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *authorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	var req AuthorRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	id, err := h.AuthorUsecase.CreateAuthor(ctx, req.toEntity())
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == entity.ErrInvalidAuthor {
+				http.Error(w, invalidAuthor, http.StatusBadRequest)
+			} else {
+				http.Error(w, createAuthor, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(map[string]int{"id": id}); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, createAuthor, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *authorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	var req AuthorRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	a := req.toEntity()
+	a.ID, err = strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidAuthorID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.AuthorUsecase.UpdateAuthor(ctx, a)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrAuthorNotFound:
+				http.Error(w, authorNotFound, http.StatusNotFound)
+			case entity.ErrInvalidAuthor:
+				http.Error(w, invalidAuthor, http.StatusBadRequest)
+			default:
+				http.Error(w, updateAuthor, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *authorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidAuthorID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.AuthorUsecase.DeleteAuthor(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrAuthorNotFound:
+				http.Error(w, authorNotFound, http.StatusNotFound)
+			case repoErr.ErrAuthorHasBooks:
+				http.Error(w, authorHasBooks, http.StatusConflict)
+			default:
+				http.Error(w, deleteAuthor, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetAuthor(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockAuthorUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					GetAuthor(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Author{ID: 1, Name: "Alex Edwards"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got AuthorResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, "Alex Edwards", got.Name)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					GetAuthor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					GetAuthor(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(nil, repoErr.ErrAuthorNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					GetAuthor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthorUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/authors/", tc.ID)
+			request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, patron)
+
+			router := chi.NewRouter()
+			NewAuthorHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := mock.NewMockAuthorUsecase(ctrl)
+	uc.EXPECT().
+		ListAuthors(gomock.Any()).
+		Times(1).
+		Return([]*entity.Author{{ID: 1, Name: "Alex Edwards"}, {ID: 2, Name: "Teiva Harsanyi"}}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/authors/", http.NoBody)
+	assert.NoError(t, err)
+	request = authenticated(request, patron)
+
+	router := chi.NewRouter()
+	NewAuthorHandler(router, uc)
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []AuthorResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got, 2)
+}
+
+func TestCreateAuthor(t *testing.T) {
+	author := AuthorRequest{Name: "Alex Edwards", Bio: "Go developer and writer"}
+
+	testCases := map[string]struct {
+		body          any
+		buildStubs    func(uc *mock.MockAuthorUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			body: author,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Eq(author.toEntity())).
+					Times(1).
+					Return(1, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			body: map[string]any{"name": 1},
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Author": {
+			body: AuthorRequest{},
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidAuthor)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthorUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/authors/", bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewAuthorHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	author := AuthorRequest{Name: "Alex Edwards", Bio: "Author of Let's Go"}
+
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockAuthorUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					UpdateAuthor(gomock.Any(), gomock.Eq(&entity.Author{ID: 1, Name: author.Name, Bio: author.Bio})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					UpdateAuthor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					UpdateAuthor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrAuthorNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthorUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(author)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/authors/", tc.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewAuthorHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockAuthorUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 2,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					DeleteAuthor(gomock.Any(), gomock.Eq(2)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Has Books": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					DeleteAuthor(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(repoErr.ErrAuthorHasBooks)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockAuthorUsecase) {
+				uc.EXPECT().
+					DeleteAuthor(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(repoErr.ErrAuthorNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthorUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/authors/", tc.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewAuthorHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+type usecases struct {
+	book    *mock.MockBookUsecase
+	copy    *mock.MockCopyUsecase
+	author  *mock.MockAuthorUsecase
+	subject *mock.MockSubjectUsecase
+	user    *mock.MockUserUsecase
+	loan    *mock.MockLoanUsecase
+	hold    *mock.MockHoldUsecase
+	fine    *mock.MockFineUsecase
+}
+
+func TestPatronAuthorization(t *testing.T) {
+	testCases := map[string]struct {
+		method     string
+		url        string
+		body       any
+		buildStubs func(uc usecases)
+		want       int
+	}{
+		"Create Book": {
+			method: http.MethodPost,
+			url:    "/v1/books/",
+			body:   BookRequest{Title: "Title", Author: "Author", Amount: 1},
+			want:   http.StatusForbidden,
+		},
+		"Update Book": {
+			method: http.MethodPut,
+			url:    "/v1/books/1",
+			body:   BookRequest{Title: "Title", Author: "Author", Amount: 1},
+			want:   http.StatusForbidden,
+		},
+		"Import Books": {
+			method: http.MethodPost,
+			url:    "/v1/books/import?format=csv",
+			want:   http.StatusForbidden,
+		},
+		"Export Books": {
+			method: http.MethodGet,
+			url:    "/v1/books/export?format=csv",
+			want:   http.StatusForbidden,
+		},
+		"Adjust Stock": {
+			method: http.MethodPost,
+			url:    "/v1/books/1/stock",
+			want:   http.StatusForbidden,
+		},
+		"List Stock Movements": {
+			method: http.MethodGet,
+			url:    "/v1/books/1/stock",
+			want:   http.StatusForbidden,
+		},
+		"Patch Book": {
+			method: http.MethodPatch,
+			url:    "/v1/books/1",
+			body:   map[string]string{"title": "Title"},
+			want:   http.StatusForbidden,
+		},
+		"Delete Book": {
+			method: http.MethodDelete,
+			url:    "/v1/books/1",
+			want:   http.StatusForbidden,
+		},
+		"Add Copy": {
+			method: http.MethodPost,
+			url:    "/v1/books/1/copies",
+			body:   CopyRequest{Barcode: "B1-9"},
+			want:   http.StatusForbidden,
+		},
+		"Update Copy": {
+			method: http.MethodPut,
+			url:    "/v1/copies/1",
+			body:   CopyRequest{Barcode: "B1-1", Status: entity.CopyLost},
+			want:   http.StatusForbidden,
+		},
+		"Delete Copy": {
+			method: http.MethodDelete,
+			url:    "/v1/copies/1",
+			want:   http.StatusForbidden,
+		},
+		"List Book Copies": {
+			method: http.MethodGet,
+			url:    "/v1/books/1/copies",
+			buildStubs: func(uc usecases) {
+				uc.copy.EXPECT().
+					ListBookCopies(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return([]*entity.Copy{{ID: 1, BookID: 1}}, nil)
+			},
+			want: http.StatusOK,
+		},
+		"Create Author": {
+			method: http.MethodPost,
+			url:    "/v1/authors/",
+			body:   AuthorRequest{Name: "Author"},
+			want:   http.StatusForbidden,
+		},
+		"Delete Author": {
+			method: http.MethodDelete,
+			url:    "/v1/authors/1",
+			want:   http.StatusForbidden,
+		},
+		"Create Subject": {
+			method: http.MethodPost,
+			url:    "/v1/subjects/",
+			body:   SubjectRequest{Name: "Subject"},
+			want:   http.StatusForbidden,
+		},
+		"Delete Subject": {
+			method: http.MethodDelete,
+			url:    "/v1/subjects/1",
+			want:   http.StatusForbidden,
+		},
+		"List Subject Books": {
+			method: http.MethodGet,
+			url:    "/v1/subjects/1/books",
+			buildStubs: func(uc usecases) {
+				uc.subject.EXPECT().
+					ListSubjectBooks(gomock.Any(), gomock.Eq(1), gomock.Any()).
+					Times(1).
+					Return(&entity.BookPage{Books: []*entity.Book{}}, nil)
+			},
+			want: http.StatusOK,
+		},
+		"Get Own User": {
+			method: http.MethodGet,
+			url:    "/v1/users/2",
+			buildStubs: func(uc usecases) {
+				uc.user.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(2)).
+					Times(1).
+					Return(&entity.User{ID: 2}, nil)
+			},
+			want: http.StatusOK,
+		},
+		"Get Other User": {
+			method: http.MethodGet,
+			url:    "/v1/users/1",
+			want:   http.StatusForbidden,
+		},
+		"Update Other User": {
+			method: http.MethodPut,
+			url:    "/v1/users/1",
+			body:   UserRequest{Username: "user123", Password: "password123", Email: "user123@example.com"},
+			want:   http.StatusForbidden,
+		},
+		"Update Own Category": {
+			method: http.MethodPut,
+			url:    "/v1/users/2",
+			body:   UserRequest{Username: "user456", Password: "password456", Email: "user456@example.com", Category: entity.CategoryStaff},
+			buildStubs: func(uc usecases) {
+				uc.user.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, u *entity.User) error {
+						assert.Empty(t, u.Category)
+						return nil
+					})
+			},
+			want: http.StatusNoContent,
+		},
+		"Patch Other User": {
+			method: http.MethodPatch,
+			url:    "/v1/users/1",
+			body:   map[string]string{"email": "user123@example.com"},
+			want:   http.StatusForbidden,
+		},
+		"Patch Own Category": {
+			method: http.MethodPatch,
+			url:    "/v1/users/2",
+			body:   map[string]string{"email": "user456@example.com", "category": string(entity.CategoryStaff)},
+			buildStubs: func(uc usecases) {
+				uc.user.EXPECT().
+					PatchUser(gomock.Any(), gomock.Eq(2), gomock.Eq(1), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, _, _ int, p *entity.UserPatch) (*entity.User, error) {
+						assert.Nil(t, p.Category)
+						return &entity.User{ID: 2}, nil
+					})
+			},
+			want: http.StatusOK,
+		},
+		"Delete Other User": {
+			method: http.MethodDelete,
+			url:    "/v1/users/1",
+			want:   http.StatusForbidden,
+		},
+		"Change Role": {
+			method: http.MethodPut,
+			url:    "/v1/users/2/role",
+			body:   RoleRequest{Role: entity.RoleLibrarian},
+			want:   http.StatusForbidden,
+		},
+		"List Overdue Loans": {
+			method: http.MethodGet,
+			url:    "/v1/loans/overdue",
+			want:   http.StatusForbidden,
+		},
+		"Search Other User Loans": {
+			method: http.MethodGet,
+			url:    "/v1/loans/1",
+			want:   http.StatusForbidden,
+		},
+		"Borrow For Other User": {
+			method: http.MethodPost,
+			url:    "/v1/loans/borrow",
+			body:   LoanRequest{UserID: 1, BookID: 1},
+			want:   http.StatusForbidden,
+		},
+		"Borrow For Self": {
+			method: http.MethodPost,
+			url:    "/v1/loans/borrow",
+			body:   LoanRequest{UserID: 2, BookID: 1},
+			buildStubs: func(uc usecases) {
+				uc.loan.EXPECT().
+					BorrowBook(gomock.Any(), gomock.Eq(2), gomock.Eq(1)).
+					Times(1).
+					Return(nil)
+			},
+			want: http.StatusNoContent,
+		},
+		"Return For Other User": {
+			method: http.MethodPost,
+			url:    "/v1/loans/return",
+			body:   LoanRequest{UserID: 1, BookID: 1},
+			want:   http.StatusForbidden,
+		},
+		"Return Other User Loan": {
+			method: http.MethodPost,
+			url:    "/v1/loans/1/return",
+			buildStubs: func(uc usecases) {
+				uc.loan.EXPECT().
+					GetLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, UserID: 1}, nil)
+			},
+			want: http.StatusForbidden,
+		},
+		"Return Own Loan": {
+			method: http.MethodPost,
+			url:    "/v1/loans/1/return",
+			buildStubs: func(uc usecases) {
+				uc.loan.EXPECT().
+					GetLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, UserID: 2}, nil)
+				uc.loan.EXPECT().
+					ReturnLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, UserID: 2, Is_returned: true}, nil)
+			},
+			want: http.StatusOK,
+		},
+		"Renew Other User Loan": {
+			method: http.MethodPost,
+			url:    "/v1/loans/1/renew",
+			buildStubs: func(uc usecases) {
+				uc.loan.EXPECT().
+					GetLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, UserID: 1}, nil)
+			},
+			want: http.StatusForbidden,
+		},
+		"Renew Own Loan": {
+			method: http.MethodPost,
+			url:    "/v1/loans/1/renew",
+			buildStubs: func(uc usecases) {
+				uc.loan.EXPECT().
+					GetLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, UserID: 2}, nil)
+				uc.loan.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, UserID: 2, Renewals: 1}, nil)
+			},
+			want: http.StatusOK,
+		},
+		"Place Hold For Other User": {
+			method: http.MethodPost,
+			url:    "/v1/books/1/holds",
+			body:   HoldRequest{UserID: 1},
+			want:   http.StatusForbidden,
+		},
+		"List Book Holds": {
+			method: http.MethodGet,
+			url:    "/v1/books/1/holds",
+			want:   http.StatusForbidden,
+		},
+		"Cancel Other User Hold": {
+			method: http.MethodDelete,
+			url:    "/v1/holds/1",
+			buildStubs: func(uc usecases) {
+				uc.hold.EXPECT().
+					GetHold(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Hold{ID: 1, UserID: 1}, nil)
+			},
+			want: http.StatusForbidden,
+		},
+		"Get Other User Balance": {
+			method: http.MethodGet,
+			url:    "/v1/users/1/balance",
+			want:   http.StatusForbidden,
+		},
+		"Register Payment": {
+			method: http.MethodPost,
+			url:    "/v1/users/2/payments",
+			body:   PaymentRequest{Amount: 100},
+			want:   http.StatusForbidden,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// any call not stubbed fails the test
+			uc := usecases{
+				book:    mock.NewMockBookUsecase(ctrl),
+				copy:    mock.NewMockCopyUsecase(ctrl),
+				author:  mock.NewMockAuthorUsecase(ctrl),
+				subject: mock.NewMockSubjectUsecase(ctrl),
+				user:    mock.NewMockUserUsecase(ctrl),
+				loan:    mock.NewMockLoanUsecase(ctrl),
+				hold:    mock.NewMockHoldUsecase(ctrl),
+				fine:    mock.NewMockFineUsecase(ctrl),
+			}
+			if tc.buildStubs != nil {
+				tc.buildStubs(uc)
+			}
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("If-Match", `"1"`)
+			request = authenticated(request, patron)
+
+			router := chi.NewRouter()
+			NewBookHandler(router, uc.book)
+			NewCopyHandler(router, uc.copy)
+			NewAuthorHandler(router, uc.author)
+			NewSubjectHandler(router, uc.subject)
+			NewUserHandler(router, uc.user)
+			NewLoanHandler(router, uc.loan)
+			NewHoldHandler(router, uc.hold)
+			NewFineHandler(router, uc.fine)
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.want, recorder.Code)
+		})
+	}
+}
+
+func TestSignUpCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := mock.NewMockUserUsecase(ctrl)
+	uc.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, u *entity.User) (int, error) {
+			// users signing up can't choose their category
+			assert.Empty(t, u.Category)
+			return 3, nil
+		})
+
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(UserRequest{Username: "user789", Password: "password789", Email: "user789@example.com", Category: entity.CategoryStaff})
+	assert.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/v1/users/", bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	router := chi.NewRouter()
+	NewUserHandler(router, uc)
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
@@ -2,15 +2,19 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog/log"
 
-	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
 )
 
@@ -25,11 +29,22 @@ func NewBookHandler(r *chi.Mux, useCase uc.BookUsecase) {
 	}
 
 	r.Route("/v1/books", func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Get("/search", handler.RankBooks)
 		r.Get("/{id}", handler.GetBook)
 		r.Get("/", handler.SearchBooks)
-		r.Post("/", handler.CreateBook)
-		r.Put("/{id}", handler.UpdateBook)
-		r.Delete("/{id}", handler.DeleteBook)
+		// only librarians manage the catalog
+		r.Group(func(r chi.Router) {
+			r.Use(requireLibrarian)
+
+			r.Post("/", handler.CreateBook)
+			r.Post("/import", handler.ImportBooks)
+			r.Get("/export", handler.ExportBooks)
+			r.Put("/{id}", handler.UpdateBook)
+			r.Patch("/{id}", handler.PatchBook)
+			r.Delete("/{id}", handler.DeleteBook)
+		})
 	})
 }
 
@@ -60,9 +75,10 @@ func (h *bookHandler) GetBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(b.Version))
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(b); err != nil {
+	if err := json.NewEncoder(w).Encode(newBookResponse(b)); err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, getBook, http.StatusInternalServerError)
 		return
@@ -73,7 +89,15 @@ type SearchBookRequest struct {
 	Title string `json:"title"`
 }
 
+// SearchBooks lists the books matching the query string. Searching with a JSON
+// body on GET is deprecated, proxies and caches drop it, it is only read when
+// there is no query string
 func (h *bookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	if r.URL.RawQuery != "" {
+		h.ListBooks(w, r)
+		return
+	}
+
 	var req SearchBookRequest
 
 	err := json.NewDecoder(r.Body).Decode(&req)
@@ -83,14 +107,16 @@ func (h *bookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-	var b []*entity.Book
 	if req.Title == "" {
-		b, err = h.BookUsecase.ListBooks(ctx)
-	} else {
-		b, err = h.BookUsecase.SearchBooks(ctx, req.Title)
+		h.ListBooks(w, r)
+		return
 	}
 
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", `</v1/books?title=`+url.QueryEscape(req.Title)+`>; rel="successor-version"`)
+
+	ctx := r.Context()
+	matches, err := h.BookUsecase.SearchBooks(ctx, entity.BookSearch{Query: req.Title})
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -98,29 +124,171 @@ func (h *bookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
 		case <-ctx.Done():
 			http.Error(w, timeout, http.StatusGatewayTimeout)
 		default:
-			if err == repoErr.ErrBookNotFound {
+			switch err {
+			case repoErr.ErrBookNotFound:
 				http.Error(w, bookNotFound, http.StatusNotFound)
-			} else {
+			case entity.ErrInvalidBookSearch:
+				http.Error(w, invalidBookSearch, http.StatusBadRequest)
+			default:
 				http.Error(w, searchBook, http.StatusInternalServerError)
 			}
 		}
 		return
 	}
 
+	books := make([]*entity.Book, 0, len(matches))
+	for _, m := range matches {
+		books = append(books, m.Book)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(b); err != nil {
+	if err := json.NewEncoder(w).Encode(newBookResponses(books)); err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, searchBook, http.StatusInternalServerError)
 		return
 	}
 }
 
+// RankBooks runs a full-text search of the q words in the title, author and
+// description, the most relevant books come first
+func (h *bookHandler) RankBooks(w http.ResponseWriter, r *http.Request) {
+	search := entity.BookSearch{Query: r.URL.Query().Get("q")}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			log.Error().Msg(err.Error())
+			http.Error(w, invalidBookSearch, http.StatusBadRequest)
+			return
+		}
+		search.Limit = n
+	}
+
+	ctx := r.Context()
+	matches, err := h.BookUsecase.SearchBooks(ctx, search)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrBookNotFound:
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			case entity.ErrInvalidBookSearch:
+				http.Error(w, invalidBookSearch, http.StatusBadRequest)
+			default:
+				http.Error(w, searchBook, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newBookMatchResponses(matches)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, searchBook, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *bookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
+	q, err := parseBookQuery(r)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookQuery, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	page, err := h.BookUsecase.ListBooks(ctx, q)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrBookNotFound:
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			case entity.ErrInvalidBookQuery:
+				http.Error(w, invalidBookQuery, http.StatusBadRequest)
+			case repoErr.ErrInvalidCursor:
+				http.Error(w, invalidCursor, http.StatusBadRequest)
+			default:
+				http.Error(w, listBooks, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newBookPageResponse(page)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listBooks, http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseBookQuery reads the pagination, sort and filters from the query string
+func parseBookQuery(r *http.Request) (entity.BookQuery, error) {
+	params := r.URL.Query()
+
+	q := entity.BookQuery{
+		Query:  params.Get("q"),
+		Title:  params.Get("title"),
+		Author: params.Get("author"),
+		Sort:   entity.BookSort(params.Get("sort")),
+		Cursor: params.Get("cursor"),
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return q, err
+		}
+		q.Limit = n
+	}
+
+	if authorID := params.Get("author_id"); authorID != "" {
+		n, err := strconv.Atoi(authorID)
+		if err != nil {
+			return q, err
+		}
+		q.AuthorID = n
+	}
+
+	if subjectID := params.Get("subject_id"); subjectID != "" {
+		n, err := strconv.Atoi(subjectID)
+		if err != nil {
+			return q, err
+		}
+		q.SubjectID = n
+	}
+
+	if available := params.Get("available"); available != "" {
+		b, err := strconv.ParseBool(available)
+		if err != nil {
+			return q, err
+		}
+		q.Available = b
+	}
+
+	return q, nil
+}
+
 func (h *bookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
-	var b entity.Book
+	var req BookRequest
 
-	err := json.NewDecoder(r.Body).Decode(&b)
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, invalidRequestBody, http.StatusBadRequest)
@@ -128,7 +296,7 @@ func (h *bookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	id, err := h.BookUsecase.CreateBook(ctx, &b)
+	id, err := h.BookUsecase.CreateBook(ctx, req.toEntity())
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -136,7 +304,11 @@ func (h *bookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		case <-ctx.Done():
 			http.Error(w, timeout, http.StatusGatewayTimeout)
 		default:
-			http.Error(w, createBook, http.StatusInternalServerError)
+			if msg, ok := invalidBookMessage(err); ok {
+				http.Error(w, msg, http.StatusBadRequest)
+			} else {
+				http.Error(w, createBook, http.StatusInternalServerError)
+			}
 		}
 		return
 	}
@@ -152,15 +324,16 @@ func (h *bookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *bookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
-	var b entity.Book
+	var req BookRequest
 
-	err := json.NewDecoder(r.Body).Decode(&b)
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, invalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
+	b := req.toEntity()
 	b.ID, err = strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		log.Error().Msg(err.Error())
@@ -168,8 +341,13 @@ func (h *bookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var ok bool
+	if b.Version, ok = ifMatch(w, r); !ok {
+		return
+	}
+
 	ctx := r.Context()
-	err = h.BookUsecase.UpdateBook(ctx, &b)
+	err = h.BookUsecase.UpdateBook(ctx, b)
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -179,6 +357,12 @@ func (h *bookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		default:
 			if err == repoErr.ErrBookNotFound {
 				http.Error(w, bookNotFound, http.StatusNotFound)
+			} else if err == repoErr.ErrVersionConflict {
+				http.Error(w, versionConflict, http.StatusPreconditionFailed)
+			} else if err == ucErr.ErrAmountChange {
+				http.Error(w, amountChange, http.StatusConflict)
+			} else if msg, ok := invalidBookMessage(err); ok {
+				http.Error(w, msg, http.StatusBadRequest)
 			} else {
 				http.Error(w, updateBook, http.StatusInternalServerError)
 			}
@@ -186,9 +370,251 @@ func (h *bookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", etag(b.Version))
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PatchBook applies a JSON Merge Patch on a book, the members left out keep
+// their stored value and a null member clears the field
+func (h *bookHandler) PatchBook(w http.ResponseWriter, r *http.Request) {
+	var req BookRequest
+
+	doc, ok := decodeMergePatch(w, r, &req)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	b, err := h.BookUsecase.PatchBook(ctx, id, version, req.toPatch(doc))
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrBookNotFound {
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			} else if err == repoErr.ErrVersionConflict {
+				http.Error(w, versionConflict, http.StatusPreconditionFailed)
+			} else if err == ucErr.ErrAmountChange {
+				http.Error(w, amountChange, http.StatusConflict)
+			} else if msg, ok := invalidBookMessage(err); ok {
+				http.Error(w, msg, http.StatusBadRequest)
+			} else {
+				http.Error(w, updateBook, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(b.Version))
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newBookResponse(b)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, updateBook, http.StatusInternalServerError)
+		return
+	}
+}
+
+// invalidBookMessage returns the response message of the errors caused by the
+// book sent by the client
+func invalidBookMessage(err error) (string, bool) {
+	switch err {
+	case entity.ErrInvalidBook:
+		return invalidBook, true
+	case entity.ErrInvalidISBN:
+		return invalidISBN, true
+	case entity.ErrInvalidPublishedYear:
+		return invalidPublishedYear, true
+	case entity.ErrInvalidLanguage:
+		return invalidLanguage, true
+	case entity.ErrInvalidPages:
+		return invalidPages, true
+	case entity.ErrInvalidCoverURL:
+		return invalidCoverURL, true
+	case repoErr.ErrISBNAlreadyExists:
+		return isbnAlreadyExists, true
+	case entity.ErrInvalidBookAuthors:
+		return invalidBookAuthors, true
+	case repoErr.ErrAuthorNotFound:
+		return bookAuthorNotFound, true
+	case entity.ErrInvalidBookSubjects:
+		return invalidBookSubjects, true
+	case repoErr.ErrSubjectNotFound:
+		return bookSubjectNotFound, true
+	}
+
+	return "", false
+}
+
+// maxImportSize is the largest import body in bytes
+const maxImportSize = 10 << 20
+
+// ImportBooks imports a CSV or JSON Lines file of books, the format is read
+// from the format query parameter or else from the Content-Type. The report
+// tells what happened to every row, the valid rows are imported even when
+// other rows are rejected
+func (h *bookHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	format := entity.BookImportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = importFormat(r.Header.Get("Content-Type"))
+	}
+	if !format.IsValid() {
+		http.Error(w, invalidImportFormat, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	ctx := r.Context()
+	report, err := h.BookUsecase.ImportBooks(ctx, format, body)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		var tooLarge *http.MaxBytesError
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch {
+			case errors.As(err, &tooLarge), err == entity.ErrBookImportTooLarge:
+				http.Error(w, importTooLarge, http.StatusRequestEntityTooLarge)
+			case errors.Is(err, entity.ErrInvalidBookImport):
+				http.Error(w, invalidImport+": "+err.Error(), http.StatusBadRequest)
+			default:
+				http.Error(w, importBooks, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newBookImportResponse(report)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, importBooks, http.StatusInternalServerError)
+		return
+	}
+}
+
+// importFormat maps the media type of an import to its format
+func importFormat(contentType string) entity.BookImportFormat {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return entity.BookImportCSV
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return entity.BookImportJSONL
+	}
+
+	return ""
+}
+
+// the X-Export-Status trailer tells a complete export from one cut short after
+// the first batch, when the status can't change anymore
+const (
+	exportStatusTrailer = "X-Export-Status"
+	exportComplete      = "complete"
+	exportTruncated     = "truncated"
+)
+
+// ExportBooks streams the whole catalog as CSV, JSON Lines or MARC 21 XML,
+// the books are sent a batch at a time as they are read. A failure after the
+// first batch ends the export early, the X-Export-Status trailer is then
+// truncated and JSON Lines exports end with an error record
+func (h *bookHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format := entity.BookExportFormat(r.URL.Query().Get("format"))
+	if !format.IsValid() {
+		http.Error(w, invalidExportFormat, http.StatusBadRequest)
+		return
+	}
+
+	contentType, filename := exportFile(format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Trailer", exportStatusTrailer)
+
+	stream := &exportStream{w: w}
+
+	ctx := r.Context()
+	n, err := h.BookUsecase.ExportBooks(ctx, format, stream)
+	if err != nil {
+		log.Error().Str("format", string(format)).Int("books", n).Msg(err.Error())
+
+		if stream.sent {
+			w.Header().Set(exportStatusTrailer, exportTruncated)
+			if format == entity.BookExportJSONL {
+				if err := json.NewEncoder(w).Encode(map[string]string{"error": exportCutShort}); err != nil {
+					log.Error().Msg(err.Error())
+				}
+			}
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		w.Header().Del("Trailer")
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			http.Error(w, exportBooks, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set(exportStatusTrailer, exportComplete)
+	log.Info().Str("format", string(format)).Int("books", n).Msg("books exported")
+}
+
+// exportStream sends every write of an export to the client right away
+type exportStream struct {
+	w    http.ResponseWriter
+	sent bool
+}
+
+func (s *exportStream) Write(p []byte) (int, error) {
+	s.sent = true
+
+	n, err := s.w.Write(p)
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return n, err
+}
+
+// exportFile maps the format of an export to its media type and file name
+func exportFile(format entity.BookExportFormat) (string, string) {
+	switch format {
+	case entity.BookExportJSONL:
+		return "application/jsonl", "books.jsonl"
+	case entity.BookExportMARCXML:
+		return "application/marcxml+xml", "books.xml"
+	default:
+		return "text/csv; charset=utf-8", "books.csv"
+	}
+}
+
 func (h *bookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -197,8 +623,13 @@ func (h *bookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
 	ctx := r.Context()
-	err = h.BookUsecase.DeleteBook(ctx, id)
+	err = h.BookUsecase.DeleteBook(ctx, id, version)
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -208,6 +639,8 @@ func (h *bookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		default:
 			if err == repoErr.ErrBookNotFound {
 				http.Error(w, bookNotFound, http.StatusNotFound)
+			} else if err == repoErr.ErrVersionConflict {
+				http.Error(w, versionConflict, http.StatusPreconditionFailed)
 			} else {
 				http.Error(w, deleteBook, http.StatusInternalServerError)
 			}
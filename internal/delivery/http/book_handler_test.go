@@ -2,20 +2,24 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
-	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
 	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 func TestGetBook(t *testing.T) {
@@ -30,10 +34,15 @@ func TestGetBook(t *testing.T) {
 				uc.EXPECT().
 					GetBook(gomock.Any(), gomock.Eq(1)).
 					Times(1).
-					Return(&entity.Book{}, nil)
+					Return(&entity.Book{Authors: []entity.BookAuthor{{AuthorID: 1, Name: "author123", Role: entity.AuthorRoleAuthor}}, Version: 3}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+
+				var got BookResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, "author123", got.Authors[0].Name)
 			},
 		},
 		"Invalid URL Param": {
@@ -85,6 +94,7 @@ func TestGetBook(t *testing.T) {
 			url := fmt.Sprint("/v1/books/", tc.ID)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewBookHandler(router, uc)
@@ -110,12 +120,13 @@ func TestSearchBooks(t *testing.T) {
 			},
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					SearchBooks(gomock.Any(), gomock.Eq("book title")).
+					SearchBooks(gomock.Any(), gomock.Eq(entity.BookSearch{Query: "book title"})).
 					Times(1).
-					Return([]*entity.Book{}, nil)
+					Return([]*entity.BookMatch{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "true", recorder.Header().Get("Deprecation"))
 			},
 		},
 		"OK List": {
@@ -124,9 +135,9 @@ func TestSearchBooks(t *testing.T) {
 			},
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					ListBooks(gomock.Any()).
+					ListBooks(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return([]*entity.Book{}, nil)
+					Return(&entity.BookPage{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
@@ -149,7 +160,7 @@ func TestSearchBooks(t *testing.T) {
 			title: testSearchBookRequest{},
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					ListBooks(gomock.Any()).
+					ListBooks(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(nil, repoErr.ErrBookNotFound)
 			},
@@ -161,7 +172,7 @@ func TestSearchBooks(t *testing.T) {
 			title: testSearchBookRequest{},
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					ListBooks(gomock.Any()).
+					ListBooks(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(nil, sql.ErrConnDone)
 			},
@@ -185,6 +196,7 @@ func TestSearchBooks(t *testing.T) {
 
 			request, err := http.NewRequest(http.MethodGet, "/v1/books/", bytes.NewReader(data))
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewBookHandler(router, uc)
@@ -194,56 +206,141 @@ func TestSearchBooks(t *testing.T) {
 	}
 }
 
-func TestCreateBook(t *testing.T) {
-	book := &entity.Book{
-		ID:     1,
-		Title:  "Book123",
-		Author: "author123",
-		Amount: 5,
+func TestListBooks(t *testing.T) {
+	page := &entity.BookPage{
+		Books:      []*entity.Book{{ID: 1, Title: "Book123", Author: "author123", Amount: 5}},
+		Total:      3,
+		NextCursor: "cursor",
 	}
 
 	testCases := map[string]struct {
-		book          any
+		query         string
 		buildStubs    func(uc *mock.MockBookUsecase)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		"OK": {
-			book: book,
+			query: "?limit=1&sort=-author&author=author&available=true&cursor=abc",
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					CreateBook(gomock.Any(), gomock.Eq(book)).
+					ListBooks(gomock.Any(), gomock.Eq(entity.BookQuery{
+						Author:    "author",
+						Available: true,
+						Sort:      "-author",
+						Limit:     1,
+						Cursor:    "abc",
+					})).
 					Times(1).
-					Return(book.ID, nil)
+					Return(page, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusCreated, recorder.Code)
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got BookPageResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, 3, got.Total)
+				assert.Equal(t, "cursor", got.NextCursor)
+				assert.Len(t, got.Books, 1)
 			},
 		},
-		"Invalid Body": {
-			book: "invalid body",
+		"Search": {
+			query: "?q=go&title=let&author=edwards",
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					CreateBook(gomock.Any(), gomock.Any()).
+					ListBooks(gomock.Any(), gomock.Eq(entity.BookQuery{
+						Query:  "go",
+						Title:  "let",
+						Author: "edwards",
+					})).
+					Times(1).
+					Return(page, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Empty(t, recorder.Header().Get("Deprecation"))
+			},
+		},
+		"Author ID": {
+			query: "?author_id=7",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ListBooks(gomock.Any(), gomock.Eq(entity.BookQuery{AuthorID: 7})).
+					Times(1).
+					Return(page, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Subject ID": {
+			query: "?subject_id=3",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ListBooks(gomock.Any(), gomock.Eq(entity.BookQuery{SubjectID: 3})).
+					Times(1).
+					Return(page, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Invalid Author ID": {
+			query: "?author_id=seven",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-		"Unexpected Error": {
-			book: book,
+		"Invalid Limit": {
+			query: "?limit=ten",
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					CreateBook(gomock.Any(), gomock.Any()).
+					ListBooks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Available": {
+			query: "?available=maybe",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Sort": {
+			query: "?sort=amount",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(0, sql.ErrConnDone)
+					Return(nil, entity.ErrInvalidBookQuery)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Cursor": {
+			query: "?cursor=abc",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ListBooks(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrInvalidCursor)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 	}
-
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -254,11 +351,113 @@ func TestCreateBook(t *testing.T) {
 
 			recorder := httptest.NewRecorder()
 
-			data, err := json.Marshal(tc.book)
+			request, err := http.NewRequest(http.MethodGet, "/v1/books/"+tc.query, http.NoBody)
 			assert.NoError(t, err)
+			request = authenticated(request, patron)
 
-			request, err := http.NewRequest(http.MethodPost, "/v1/books/", bytes.NewReader(data))
+			router := chi.NewRouter()
+			NewBookHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRankBooks(t *testing.T) {
+	matches := []*entity.BookMatch{
+		{
+			Book:    &entity.Book{ID: 1, Title: "Book123", Author: "author123", Amount: 5},
+			Rank:    0.5,
+			Snippet: "<mark>Book123</mark> - author123",
+		},
+	}
+
+	testCases := map[string]struct {
+		query         string
+		buildStubs    func(uc *mock.MockBookUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			query: "?q=book+auth&limit=5",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					SearchBooks(gomock.Any(), gomock.Eq(entity.BookSearch{Query: "book auth", Limit: 5})).
+					Times(1).
+					Return(matches, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []BookMatchResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Len(t, got, 1)
+				assert.Equal(t, 1, got[0].ID)
+				assert.Equal(t, "Book123", got[0].Title)
+				assert.Equal(t, 0.5, got[0].Rank)
+				assert.Equal(t, "<mark>Book123</mark> - author123", got[0].Snippet)
+			},
+		},
+		"Invalid Limit": {
+			query: "?q=book&limit=ten",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					SearchBooks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Search": {
+			query: "",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					SearchBooks(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, entity.ErrInvalidBookSearch)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			query: "?q=nothing",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					SearchBooks(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			query: "?q=book",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					SearchBooks(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockBookUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/v1/books/search"+tc.query, http.NoBody)
 			assert.NoError(t, err)
+			request = authenticated(request, patron)
 
 			router := chi.NewRouter()
 			NewBookHandler(router, uc)
@@ -268,78 +467,153 @@ func TestCreateBook(t *testing.T) {
 	}
 }
 
-func TestUpdateBook(t *testing.T) {
-	book := &entity.Book{
-		ID:     1,
+func TestCreateBook(t *testing.T) {
+	book := BookRequest{
 		Title:  "Book123",
 		Author: "author123",
 		Amount: 5,
 	}
 
 	testCases := map[string]struct {
-		ID            any
 		book          any
 		buildStubs    func(uc *mock.MockBookUsecase)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		"OK": {
-			ID:   1,
 			book: book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Eq(book)).
+					CreateBook(gomock.Any(), gomock.Eq(book.toEntity())).
 					Times(1).
-					Return(nil)
+					Return(1, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusNoContent, recorder.Code)
+				assert.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
 		"Invalid Body": {
-			ID:   1,
-			book: "",
+			book: "invalid body",
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Any()).
+					CreateBook(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-		"Invalid URL Param": {
-			ID:   "ID",
+		"Invalid ISBN": {
+			book: BookRequest{Title: "Book123", Author: "author123", Amount: 5, ISBN: "123"},
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					CreateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidISBN)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"ISBN Already Exists": {
 			book: book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Eq(book)).
-					Times(0)
+					CreateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrISBNAlreadyExists)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-		"Not Found": {
-			ID:   1,
+		"Authors": {
+			book: BookRequest{
+				Title:   "Book123",
+				Authors: []BookAuthorRequest{{AuthorID: 1, Role: entity.AuthorRoleAuthor}},
+				Amount:  5,
+			},
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					CreateBook(gomock.Any(), gomock.Eq(&entity.Book{
+						Title:   "Book123",
+						Authors: []entity.BookAuthor{{AuthorID: 1, Role: entity.AuthorRoleAuthor}},
+						Amount:  5,
+					})).
+					Times(1).
+					Return(1, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Subjects": {
+			book: BookRequest{
+				Title:      "Book123",
+				Author:     "author123",
+				SubjectIDs: []int{1, 2},
+				Amount:     5,
+			},
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					CreateBook(gomock.Any(), gomock.Eq(&entity.Book{
+						Title:    "Book123",
+						Author:   "author123",
+						Subjects: []entity.Subject{{ID: 1}, {ID: 2}},
+						Amount:   5,
+					})).
+					Times(1).
+					Return(1, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Subject Not Found": {
 			book: book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Any()).
+					CreateBook(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(repoErr.ErrBookNotFound)
+					Return(0, repoErr.ErrSubjectNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), bookSubjectNotFound)
+			},
+		},
+		"Invalid Authors": {
+			book: book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					CreateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidBookAuthors)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidBookAuthors)
+			},
+		},
+		"Author Not Found": {
+			book: book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					CreateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrAuthorNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), bookAuthorNotFound)
 			},
 		},
 		"Unexpected Error": {
-			ID:   1,
 			book: book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					UpdateBook(gomock.Any(), gomock.Any()).
+					CreateBook(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(sql.ErrConnDone)
+					Return(0, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -360,10 +634,9 @@ func TestUpdateBook(t *testing.T) {
 			data, err := json.Marshal(tc.book)
 			assert.NoError(t, err)
 
-			url := fmt.Sprint("/v1/books/", tc.ID)
-
-			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			request, err := http.NewRequest(http.MethodPost, "/v1/books/", bytes.NewReader(data))
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewBookHandler(router, uc)
@@ -373,29 +646,60 @@ func TestUpdateBook(t *testing.T) {
 	}
 }
 
-func TestDeleteBook(t *testing.T) {
+func TestUpdateBook(t *testing.T) {
+	book := &entity.Book{
+		ID:      1,
+		Title:   "Book123",
+		Author:  "author123",
+		Amount:  5,
+		Version: 1,
+	}
+
 	testCases := map[string]struct {
 		ID            any
+		ifMatch       string
+		book          any
 		buildStubs    func(uc *mock.MockBookUsecase)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		"OK": {
-			ID: 1,
+			ID:      1,
+			ifMatch: `"1"`,
+			book:    book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					DeleteBook(gomock.Any(), gomock.Eq(1)).
+					UpdateBook(gomock.Any(), gomock.Eq(book)).
 					Times(1).
-					Return(nil)
+					DoAndReturn(func(_ context.Context, b *entity.Book) error {
+						b.Version = 2
+						return nil
+					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusNoContent, recorder.Code)
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+			},
+		},
+		"Invalid Body": {
+			ID:      1,
+			ifMatch: `"1"`,
+			book:    "",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		"Invalid URL Param": {
-			ID: "ID",
+			ID:      "ID",
+			ifMatch: `"1"`,
+			book:    book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					DeleteBook(gomock.Any(), gomock.Any()).
+					UpdateBook(gomock.Any(), gomock.Eq(book)).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -403,10 +707,12 @@ func TestDeleteBook(t *testing.T) {
 			},
 		},
 		"Not Found": {
-			ID: 1,
+			ID:      1,
+			ifMatch: `"1"`,
+			book:    book,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					DeleteBook(gomock.Any(), gomock.Any()).
+					UpdateBook(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(repoErr.ErrBookNotFound)
 			},
@@ -414,11 +720,649 @@ func TestDeleteBook(t *testing.T) {
 				assert.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
-		"Unexpected Error": {
-			ID: 1,
+		"Amount Change": {
+			ID:      1,
+			ifMatch: `"1"`,
+			book:    book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrAmountChange)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), amountChange)
+			},
+		},
+		"Missing If-Match": {
+			ID:   1,
+			book: book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		"Invalid If-Match": {
+			ID:      1,
+			ifMatch: `W/"1"`,
+			book:    book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Version Conflict": {
+			ID:      1,
+			ifMatch: `"1"`,
+			book:    book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrVersionConflict)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:      1,
+			ifMatch: `"1"`,
+			book:    book,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockBookUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.book)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/books/", tc.ID)
+
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+			request.Header.Set("If-Match", tc.ifMatch)
+
+			router := chi.NewRouter()
+			NewBookHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPatchBook(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		body          string
+		contentType   string
+		ifMatch       string
+		buildStubs    func(uc *mock.MockBookUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:          1,
+			body:        `{"title": "Book123", "author": null, "subject_ids": null}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Eq(1), gomock.Eq(1), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _, _ int, p *entity.BookPatch) (*entity.Book, error) {
+						assert.Equal(t, "Book123", *p.Title)
+						assert.Equal(t, "", *p.Author)
+						assert.Empty(t, *p.Subjects)
+						assert.Nil(t, p.Authors)
+						assert.Nil(t, p.ISBN)
+						return &entity.Book{ID: 1, Title: "Book123", Author: "author123", Version: 2}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+
+				var got BookResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, "Book123", got.Title)
+				assert.Equal(t, "author123", got.Author)
+			},
+		},
+		"JSON Content Type": {
+			ID:          1,
+			body:        `{"pages": 300}`,
+			contentType: "application/json; charset=utf-8",
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Eq(1), gomock.Eq(1), gomock.Any()).
+					Times(1).
+					Return(&entity.Book{ID: 1, Version: 2}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Unsupported Media Type": {
+			ID:          1,
+			body:        `{"title": "Book123"}`,
+			contentType: "text/plain",
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			ID:          1,
+			body:        `["title"]`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Member": {
+			ID:          1,
+			body:        `{"pages": "many"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:          "ID",
+			body:        `{"title": "Book123"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Missing If-Match": {
+			ID:          1,
+			body:        `{"title": "Book123"}`,
+			contentType: mergePatchType,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID:          1,
+			body:        `{"title": "Book123"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Version Conflict": {
+			ID:          1,
+			body:        `{"title": "Book123"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrVersionConflict)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Amount Change": {
+			ID:          1,
+			body:        `{"amount": 7}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, ucErr.ErrAmountChange)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Invalid Book": {
+			ID:          1,
+			body:        `{"title": null}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, entity.ErrInvalidBook)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidBook)
+			},
+		},
+		"Unexpected Error": {
+			ID:          1,
+			body:        `{"title": "Book123"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					PatchBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockBookUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/books/", tc.ID)
+			request, err := http.NewRequest(http.MethodPatch, url, strings.NewReader(tc.body))
+			assert.NoError(t, err)
+			request.Header.Set("Content-Type", tc.contentType)
+			request.Header.Set("If-Match", tc.ifMatch)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewBookHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestImportBooks(t *testing.T) {
+	file := "title,author,isbn\n100 Go Mistakes,Teiva Harsanyi,9781617299537\n,Alex Edwards,\n"
+
+	testCases := map[string]struct {
+		url           string
+		contentType   string
+		buildStubs    func(uc *mock.MockBookUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			url:         "/v1/books/import",
+			contentType: "text/csv; charset=utf-8",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ImportBooks(gomock.Any(), gomock.Eq(entity.BookImportCSV), gomock.Any()).
+					Times(1).
+					Return(&entity.BookImportReport{
+						Updated:  1,
+						Rejected: 1,
+						Rows: []entity.BookImportRow{
+							{Line: 2, Status: entity.BookImportUpdated, Book: &entity.Book{ID: 7, BookMetadata: entity.BookMetadata{ISBN: "9781617299537"}}},
+							{Line: 3, Status: entity.BookImportRejected, Err: entity.ErrInvalidBook},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got BookImportResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, 1, got.Updated)
+				assert.Equal(t, 1, got.Rejected)
+				assert.Equal(t, 7, got.Rows[0].BookID)
+				assert.Equal(t, invalidBook, got.Rows[1].Reason)
+			},
+		},
+		"Format Query Parameter": {
+			url:         "/v1/books/import?format=jsonl",
+			contentType: "application/octet-stream",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ImportBooks(gomock.Any(), gomock.Eq(entity.BookImportJSONL), gomock.Any()).
+					Times(1).
+					Return(&entity.BookImportReport{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Unknown Format": {
+			url:         "/v1/books/import",
+			contentType: "application/xml",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ImportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		"Invalid Import": {
+			url:         "/v1/books/import?format=csv",
+			contentType: "text/csv",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ImportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("%w: unknown column \"price\"", entity.ErrInvalidBookImport))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), "price")
+			},
+		},
+		"Too Large": {
+			url:         "/v1/books/import",
+			contentType: "text/csv",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ImportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, entity.ErrBookImportTooLarge)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			url:         "/v1/books/import",
+			contentType: "text/csv",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ImportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockBookUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, tc.url, bytes.NewBufferString(file))
+			assert.NoError(t, err)
+			request.Header.Set("Content-Type", tc.contentType)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewBookHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestExportBooks(t *testing.T) {
+	testCases := map[string]struct {
+		url           string
+		buildStubs    func(uc *mock.MockBookUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			url: "/v1/books/export?format=marcxml",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Eq(entity.BookExportMARCXML), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ entity.BookExportFormat, w io.Writer) (int, error) {
+						_, err := io.WriteString(w, "<collection></collection>")
+						return 0, err
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "application/marcxml+xml", recorder.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="books.xml"`, recorder.Header().Get("Content-Disposition"))
+				assert.Equal(t, "<collection></collection>", recorder.Body.String())
+				assert.True(t, recorder.Flushed)
+				assert.Equal(t, "complete", recorder.Result().Trailer.Get("X-Export-Status"))
+			},
+		},
+		"Invalid Format": {
+			url: "/v1/books/export?format=xlsx",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Failed Before Sending": {
+			url: "/v1/books/export?format=csv",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Eq(entity.BookExportCSV), gomock.Any()).
+					Times(1).
+					Return(0, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assert.Empty(t, recorder.Header().Get("Content-Disposition"))
+			},
+		},
+		"Failed While Sending": {
+			url: "/v1/books/export?format=jsonl",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Eq(entity.BookExportJSONL), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ entity.BookExportFormat, w io.Writer) (int, error) {
+						_, err := io.WriteString(w, "{\"id\":1}\n")
+						assert.NoError(t, err)
+						return 1, sql.ErrConnDone
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "{\"id\":1}\n{\"error\":\""+exportCutShort+"\"}\n", recorder.Body.String())
+				assert.Equal(t, "truncated", recorder.Result().Trailer.Get("X-Export-Status"))
+			},
+		},
+		"CSV Failed While Sending": {
+			url: "/v1/books/export?format=csv",
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Eq(entity.BookExportCSV), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ entity.BookExportFormat, w io.Writer) (int, error) {
+						_, err := io.WriteString(w, "id,title\n1,Book One\n")
+						assert.NoError(t, err)
+						return 1, sql.ErrConnDone
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "id,title\n1,Book One\n", recorder.Body.String())
+				assert.Equal(t, "truncated", recorder.Result().Trailer.Get("X-Export-Status"))
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockBookUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewBookHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteBook(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		ifMatch       string
+		buildStubs    func(uc *mock.MockBookUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:      1,
+			ifMatch: `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					DeleteBook(gomock.Any(), gomock.Eq(1), gomock.Eq(1)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:      "ID",
+			ifMatch: `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID:      1,
+			ifMatch: `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Missing If-Match": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		"Invalid If-Match": {
+			ID:      1,
+			ifMatch: `W/"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Version Conflict": {
+			ID:      1,
+			ifMatch: `"1"`,
+			buildStubs: func(uc *mock.MockBookUsecase) {
+				uc.EXPECT().
+					DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrVersionConflict)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:      1,
+			ifMatch: `"1"`,
 			buildStubs: func(uc *mock.MockBookUsecase) {
 				uc.EXPECT().
-					DeleteBook(gomock.Any(), gomock.Any()).
+					DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(sql.ErrConnDone)
 			},
@@ -440,6 +1384,8 @@ func TestDeleteBook(t *testing.T) {
 			url := fmt.Sprint("/v1/books/", tc.ID)
 			request, err := http.NewRequest(http.MethodDelete, url, nil)
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+			request.Header.Set("If-Match", tc.ifMatch)
 
 			router := chi.NewRouter()
 			NewBookHandler(router, uc)
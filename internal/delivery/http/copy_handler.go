@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type copyHandler struct {
+	CopyUsecase uc.CopyUsecase
+}
+
+// NewCopyHandler creates a new instance of copyHandler
+func NewCopyHandler(r *chi.Mux, useCase uc.CopyUsecase) {
+	handler := &copyHandler{
+		CopyUsecase: useCase,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Get("/v1/books/{id}/copies", handler.ListBookCopies)
+		r.Get("/v1/copies/{id}", handler.GetCopy)
+		// only librarians manage the copies on the shelves
+		r.Group(func(r chi.Router) {
+			r.Use(requireLibrarian)
+
+			r.Post("/v1/books/{id}/copies", handler.AddCopy)
+			r.Put("/v1/copies/{id}", handler.UpdateCopy)
+			r.Delete("/v1/copies/{id}", handler.DeleteCopy)
+			r.Get("/v1/books/{id}/stock", handler.ListStockMovements)
+			r.Post("/v1/books/{id}/stock", handler.AdjustStock)
+		})
+	})
+}
+
+func (h *copyHandler) GetCopy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidCopyID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	c, err := h.CopyUsecase.GetCopy(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrCopyNotFound {
+				http.Error(w, copyNotFound, http.StatusNotFound)
+			} else {
+				http.Error(w, getCopy, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newCopyResponse(c)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, getCopy, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *copyHandler) ListBookCopies(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	copies, err := h.CopyUsecase.ListBookCopies(ctx, bookID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrBookNotFound {
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			} else {
+				http.Error(w, listCopies, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newCopyResponses(copies)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listCopies, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *copyHandler) AddCopy(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	var req CopyRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	c := req.toEntity()
+	c.BookID = bookID
+
+	ctx := r.Context()
+	claims, _ := claimsFromContext(ctx)
+
+	id, err := h.CopyUsecase.AddCopy(ctx, c, claims.UserID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrBookNotFound {
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			} else if msg, ok := invalidCopyMessage(err); ok {
+				http.Error(w, msg, http.StatusBadRequest)
+			} else {
+				http.Error(w, addCopy, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(map[string]int{"id": id}); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, addCopy, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *copyHandler) UpdateCopy(w http.ResponseWriter, r *http.Request) {
+	var req CopyRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	c := req.toEntity()
+	c.ID, err = strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidCopyID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	claims, _ := claimsFromContext(ctx)
+
+	err = h.CopyUsecase.UpdateCopy(ctx, c, claims.UserID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrCopyNotFound {
+				http.Error(w, copyNotFound, http.StatusNotFound)
+			} else if err == ucErr.ErrCopyOnLoan {
+				http.Error(w, copyOnLoan, http.StatusConflict)
+			} else if err == repoErr.ErrNotEnoughCopies {
+				http.Error(w, copyReserved, http.StatusConflict)
+			} else if msg, ok := invalidCopyMessage(err); ok {
+				http.Error(w, msg, http.StatusBadRequest)
+			} else {
+				http.Error(w, updateCopy, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invalidCopyMessage returns the response message of the errors caused by the
+// copy sent by the client
+func invalidCopyMessage(err error) (string, bool) {
+	switch err {
+	case entity.ErrInvalidCopy:
+		return invalidCopy, true
+	case entity.ErrInvalidCopyStatus:
+		return invalidCopyStatus, true
+	case repoErr.ErrBarcodeAlreadyExists:
+		return barcodeAlreadyExists, true
+	}
+
+	return "", false
+}
+
+func (h *copyHandler) DeleteCopy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidCopyID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	claims, _ := claimsFromContext(ctx)
+
+	err = h.CopyUsecase.DeleteCopy(ctx, id, claims.UserID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrCopyNotFound:
+				http.Error(w, copyNotFound, http.StatusNotFound)
+			case ucErr.ErrCopyOnLoan:
+				http.Error(w, copyOnLoan, http.StatusConflict)
+			case repoErr.ErrNotEnoughCopies:
+				http.Error(w, copyReserved, http.StatusConflict)
+			default:
+				http.Error(w, deleteCopy, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdjustStock adds, moves or withdraws copies of a book, the adjustment is
+// recorded with the librarian making it
+func (h *copyHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	var req StockRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	claims, _ := claimsFromContext(ctx)
+
+	m := req.toEntity()
+	m.BookID = bookID
+	m.UserID = claims.UserID
+
+	err = h.CopyUsecase.AdjustStock(ctx, m)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrBookNotFound:
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			case entity.ErrInvalidMovement:
+				http.Error(w, invalidMovement, http.StatusBadRequest)
+			case entity.ErrInvalidMovementKind:
+				http.Error(w, invalidMovementKind, http.StatusBadRequest)
+			case repoErr.ErrNotEnoughCopies:
+				http.Error(w, notEnoughCopies, http.StatusConflict)
+			case repoErr.ErrBarcodeAlreadyExists:
+				http.Error(w, barcodeAlreadyExists, http.StatusConflict)
+			default:
+				http.Error(w, adjustStock, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(newMovementResponse(m)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, adjustStock, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *copyHandler) ListStockMovements(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	movements, err := h.CopyUsecase.ListStockMovements(ctx, bookID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrBookNotFound {
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			} else {
+				http.Error(w, listMovements, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newMovementResponses(movements)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listMovements, http.StatusInternalServerError)
+		return
+	}
+}
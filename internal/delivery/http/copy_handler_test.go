@@ -0,0 +1,698 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetCopy(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					GetCopy(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Copy{ID: 1, BookID: 1, Barcode: "B1-1", Status: entity.CopyAvailable}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var res CopyResponse
+				assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+				assert.Equal(t, "B1-1", res.Barcode)
+				assert.Equal(t, entity.CopyAvailable, res.Status)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					GetCopy(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					GetCopy(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(nil, repoErr.ErrCopyNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					GetCopy(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/copies/", tc.ID)
+			request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, patron)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListBookCopies(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					ListBookCopies(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return([]*entity.Copy{{ID: 1, BookID: 1}, {ID: 2, BookID: 1}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var res []CopyResponse
+				assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+				assert.Len(t, res, 2)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					ListBookCopies(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Book Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					ListBookCopies(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(nil, repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/books/", tc.ID, "/copies")
+			request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, patron)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddCopy(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		body          any
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   1,
+			body: CopyRequest{Barcode: "B1-3", Location: "A3"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AddCopy(gomock.Any(), gomock.Eq(&entity.Copy{BookID: 1, Barcode: "B1-3", Location: "A3"}), gomock.Eq(librarian.UserID)).
+					Times(1).
+					Return(3, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:   "ID",
+			body: CopyRequest{Barcode: "B1-3"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AddCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			ID:   1,
+			body: map[string]any{"barcode": 3},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AddCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Copy": {
+			ID:   1,
+			body: CopyRequest{Barcode: ""},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AddCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidCopy)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidCopy)
+			},
+		},
+		"Barcode Already Exists": {
+			ID:   1,
+			body: CopyRequest{Barcode: "B1-1"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AddCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrBarcodeAlreadyExists)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), barcodeAlreadyExists)
+			},
+		},
+		"Book Not Found": {
+			ID:   10,
+			body: CopyRequest{Barcode: "B10-1"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AddCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/books/", tc.ID, "/copies")
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCopy(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		body          CopyRequest
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   1,
+			body: CopyRequest{Barcode: "B1-1", Status: entity.CopyLost},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					UpdateCopy(gomock.Any(), gomock.Eq(&entity.Copy{ID: 1, Barcode: "B1-1", Status: entity.CopyLost}), gomock.Eq(librarian.UserID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:   "ID",
+			body: CopyRequest{Barcode: "B1-1", Status: entity.CopyLost},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					UpdateCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Status": {
+			ID:   1,
+			body: CopyRequest{Barcode: "B1-1", Status: "stolen"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					UpdateCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrInvalidCopyStatus)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidCopyStatus)
+			},
+		},
+		"Copy On Loan": {
+			ID:   3,
+			body: CopyRequest{Barcode: "B2-1", Status: entity.CopyAvailable},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					UpdateCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrCopyOnLoan)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Copy Reserved": {
+			ID:   2,
+			body: CopyRequest{Barcode: "B1-2", Status: entity.CopyLost},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					UpdateCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrNotEnoughCopies)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID:   10,
+			body: CopyRequest{Barcode: "B1-9", Status: entity.CopyLost},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					UpdateCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrCopyNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/copies/", tc.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteCopy(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					DeleteCopy(gomock.Any(), gomock.Eq(1), gomock.Eq(librarian.UserID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					DeleteCopy(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Copy On Loan": {
+			ID: 3,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					DeleteCopy(gomock.Any(), gomock.Eq(3), gomock.Eq(librarian.UserID)).
+					Times(1).
+					Return(ucErr.ErrCopyOnLoan)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Copy Reserved": {
+			ID: 2,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					DeleteCopy(gomock.Any(), gomock.Eq(2), gomock.Eq(librarian.UserID)).
+					Times(1).
+					Return(repoErr.ErrNotEnoughCopies)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					DeleteCopy(gomock.Any(), gomock.Eq(10), gomock.Eq(librarian.UserID)).
+					Times(1).
+					Return(repoErr.ErrCopyNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/copies/", tc.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAdjustStock(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		body          any
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   1,
+			body: StockRequest{Kind: entity.MovementAcquired, Quantity: 2, Reason: "donation"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Eq(&entity.InventoryMovement{BookID: 1, UserID: librarian.UserID, Kind: entity.MovementAcquired, Quantity: 2, Reason: "donation"})).
+					Times(1).
+					DoAndReturn(func(_ context.Context, m *entity.InventoryMovement) error {
+						m.ID = 3
+						m.Barcodes = []string{"B1-3", "B1-4"}
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+
+				var got MovementResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, 3, got.ID)
+				assert.Equal(t, librarian.UserID, *got.UserID)
+				assert.Equal(t, []string{"B1-3", "B1-4"}, got.Barcodes)
+			},
+		},
+		"Invalid URL Param": {
+			ID:   "ID",
+			body: StockRequest{Kind: entity.MovementLost, Quantity: 1, Reason: "missing"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Kind": {
+			ID:   1,
+			body: StockRequest{Kind: "stolen", Quantity: 1, Reason: "missing"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrInvalidMovementKind)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidMovementKind)
+			},
+		},
+		"Invalid Movement": {
+			ID:   1,
+			body: StockRequest{Kind: entity.MovementLost, Quantity: 1},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrInvalidMovement)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidMovement)
+			},
+		},
+		"Not Enough Copies": {
+			ID:   2,
+			body: StockRequest{Kind: entity.MovementWithdrawn, Quantity: 1, Reason: "weeding"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrNotEnoughCopies)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Book Not Found": {
+			ID:   10,
+			body: StockRequest{Kind: entity.MovementAcquired, Quantity: 1, Reason: "donation"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:   1,
+			body: StockRequest{Kind: entity.MovementAcquired, Quantity: 1, Reason: "donation"},
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					AdjustStock(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/books/", tc.ID, "/stock")
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListStockMovements(t *testing.T) {
+	movements := []*entity.InventoryMovement{
+		{ID: 2, BookID: 1, Kind: entity.MovementLost, Quantity: 1, Reason: "missing", Barcodes: []string{"B1-2"}},
+		{ID: 1, BookID: 1, UserID: 1, Kind: entity.MovementAcquired, Quantity: 2, Reason: "donation", Barcodes: []string{"B1-1", "B1-2"}},
+	}
+
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockCopyUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					ListStockMovements(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(movements, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []MovementResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Len(t, got, 2)
+				assert.Nil(t, got[0].UserID, "the librarian was deleted")
+				assert.Equal(t, 1, *got[1].UserID)
+			},
+		},
+		"Book Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					ListStockMovements(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(nil, repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockCopyUsecase) {
+				uc.EXPECT().
+					ListStockMovements(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockCopyUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/books/", tc.ID, "/stock")
+			request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewCopyHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
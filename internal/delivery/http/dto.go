@@ -0,0 +1,623 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+// UserRequest is the body accepted when creating or updating a user,
+// the password is write-only and never sent back
+type UserRequest struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Email    string          `json:"email"`
+	Category entity.Category `json:"category"`
+}
+
+func (req UserRequest) toEntity() *entity.User {
+	return &entity.User{
+		Username: req.Username,
+		Password: req.Password,
+		Email:    req.Email,
+		Category: req.Category,
+	}
+}
+
+// toPatch returns the partial update of the members of the merge patch, the
+// password and unknown members are ignored like in updates
+func (req UserRequest) toPatch(doc mergePatch) *entity.UserPatch {
+	u := req.toEntity()
+	p := &entity.UserPatch{}
+
+	if doc.has("username") {
+		p.Username = &u.Username
+	}
+	if doc.has("email") {
+		p.Email = &u.Email
+	}
+	if doc.has("category") {
+		p.Category = &u.Category
+	}
+
+	return p
+}
+
+// UserResponse is the public representation of a user, without secrets
+type UserResponse struct {
+	ID        int             `json:"id"`
+	Username  string          `json:"username"`
+	Email     string          `json:"email"`
+	Category  entity.Category `json:"category"`
+	Role      entity.Role     `json:"role"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt *time.Time      `json:"updated_at"`
+}
+
+func newUserResponse(u *entity.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		Email:     u.Email,
+		Category:  u.Category,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: optionalTime(u.UpdatedAt),
+	}
+}
+
+// BookRequest is the body accepted when creating or updating a book,
+// the bibliographic fields are optional. The author defaults to the names of
+// the authors, and updates without authors or subjects keep those of the book
+type BookRequest struct {
+	Title         string              `json:"title"`
+	Author        string              `json:"author"`
+	Authors       []BookAuthorRequest `json:"authors"`
+	SubjectIDs    []int               `json:"subject_ids"`
+	Amount        int                 `json:"amount"`
+	ISBN          string              `json:"isbn"`
+	Publisher     string              `json:"publisher"`
+	PublishedYear int                 `json:"published_year"`
+	Language      string              `json:"language"`
+	Pages         int                 `json:"pages"`
+	Description   string              `json:"description"`
+	CoverURL      string              `json:"cover_url"`
+}
+
+// BookAuthorRequest credits an author on a book, in the order of the list
+type BookAuthorRequest struct {
+	AuthorID int               `json:"author_id"`
+	Role     entity.AuthorRole `json:"role"`
+}
+
+func (req BookRequest) toEntity() *entity.Book {
+	b := &entity.Book{
+		Title:  req.Title,
+		Author: req.Author,
+		Amount: req.Amount,
+		BookMetadata: entity.BookMetadata{
+			ISBN:          req.ISBN,
+			Publisher:     req.Publisher,
+			PublishedYear: req.PublishedYear,
+			Language:      req.Language,
+			Pages:         req.Pages,
+			Description:   req.Description,
+			CoverURL:      req.CoverURL,
+		},
+	}
+
+	// nil authors keep the authors of the book, an empty list removes them
+	if req.Authors != nil {
+		b.Authors = make([]entity.BookAuthor, 0, len(req.Authors))
+		for _, a := range req.Authors {
+			b.Authors = append(b.Authors, entity.BookAuthor{AuthorID: a.AuthorID, Role: a.Role})
+		}
+	}
+
+	// the same goes for the subjects
+	if req.SubjectIDs != nil {
+		b.Subjects = make([]entity.Subject, 0, len(req.SubjectIDs))
+		for _, id := range req.SubjectIDs {
+			b.Subjects = append(b.Subjects, entity.Subject{ID: id})
+		}
+	}
+
+	return b
+}
+
+// toPatch returns the partial update of the members of the merge patch, null
+// authors or subject_ids remove them like an empty list
+func (req BookRequest) toPatch(doc mergePatch) *entity.BookPatch {
+	b := req.toEntity()
+	p := &entity.BookPatch{}
+
+	if doc.has("title") {
+		p.Title = &b.Title
+	}
+	if doc.has("author") {
+		p.Author = &b.Author
+	}
+	if doc.has("amount") {
+		p.Amount = &b.Amount
+	}
+	if doc.has("authors") {
+		p.Authors = &b.Authors
+	}
+	if doc.has("subject_ids") {
+		p.Subjects = &b.Subjects
+	}
+	if doc.has("isbn") {
+		p.ISBN = &b.ISBN
+	}
+	if doc.has("publisher") {
+		p.Publisher = &b.Publisher
+	}
+	if doc.has("published_year") {
+		p.PublishedYear = &b.PublishedYear
+	}
+	if doc.has("language") {
+		p.Language = &b.Language
+	}
+	if doc.has("pages") {
+		p.Pages = &b.Pages
+	}
+	if doc.has("description") {
+		p.Description = &b.Description
+	}
+	if doc.has("cover_url") {
+		p.CoverURL = &b.CoverURL
+	}
+
+	return p
+}
+
+// BookResponse is the public representation of a book
+type BookResponse struct {
+	ID            int                   `json:"id"`
+	Title         string                `json:"title"`
+	Author        string                `json:"author"`
+	Amount        int                   `json:"amount"`
+	Authors       []BookAuthorResponse  `json:"authors,omitempty"`
+	Subjects      []BookSubjectResponse `json:"subjects,omitempty"`
+	ISBN          string                `json:"isbn"`
+	Publisher     string                `json:"publisher"`
+	PublishedYear int                   `json:"published_year"`
+	Language      string                `json:"language"`
+	Pages         int                   `json:"pages"`
+	Description   string                `json:"description"`
+	CoverURL      string                `json:"cover_url"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     *time.Time            `json:"updated_at"`
+}
+
+func newBookResponse(b *entity.Book) BookResponse {
+	res := BookResponse{
+		ID:            b.ID,
+		Title:         b.Title,
+		Author:        b.Author,
+		Amount:        b.Amount,
+		ISBN:          b.ISBN,
+		Publisher:     b.Publisher,
+		PublishedYear: b.PublishedYear,
+		Language:      b.Language,
+		Pages:         b.Pages,
+		Description:   b.Description,
+		CoverURL:      b.CoverURL,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     optionalTime(b.UpdatedAt),
+	}
+	for _, a := range b.Authors {
+		res.Authors = append(res.Authors, BookAuthorResponse{
+			AuthorID: a.AuthorID,
+			Name:     a.Name,
+			Role:     a.Role,
+		})
+	}
+	for _, subject := range b.Subjects {
+		res.Subjects = append(res.Subjects, BookSubjectResponse{
+			ID:   subject.ID,
+			Name: subject.Name,
+		})
+	}
+	return res
+}
+
+// BookAuthorResponse is an author credited on a book, only the book details
+// list the authors
+type BookAuthorResponse struct {
+	AuthorID int               `json:"author_id"`
+	Name     string            `json:"name"`
+	Role     entity.AuthorRole `json:"role"`
+}
+
+// BookSubjectResponse is a subject a book is tagged with, only the book details
+// list the subjects
+type BookSubjectResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuthorRequest is the body accepted when creating or updating an author
+type AuthorRequest struct {
+	Name string `json:"name"`
+	Bio  string `json:"bio"`
+}
+
+func (req AuthorRequest) toEntity() *entity.Author {
+	return &entity.Author{
+		Name: req.Name,
+		Bio:  req.Bio,
+	}
+}
+
+// AuthorResponse is the public representation of an author
+type AuthorResponse struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Bio       string     `json:"bio"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func newAuthorResponse(a *entity.Author) AuthorResponse {
+	return AuthorResponse{
+		ID:        a.ID,
+		Name:      a.Name,
+		Bio:       a.Bio,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: optionalTime(a.UpdatedAt),
+	}
+}
+
+func newAuthorResponses(authors []*entity.Author) []AuthorResponse {
+	res := make([]AuthorResponse, 0, len(authors))
+	for _, a := range authors {
+		res = append(res, newAuthorResponse(a))
+	}
+	return res
+}
+
+// SubjectRequest is the body accepted when creating or updating a subject,
+// subjects without a parent_id are at the top of the taxonomy
+type SubjectRequest struct {
+	Name     string `json:"name"`
+	ParentID int    `json:"parent_id"`
+}
+
+func (req SubjectRequest) toEntity() *entity.Subject {
+	return &entity.Subject{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+}
+
+// SubjectResponse is the public representation of a subject, the parent_id
+// of the subjects at the top of the taxonomy is null
+type SubjectResponse struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *int       `json:"parent_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func newSubjectResponse(s *entity.Subject) SubjectResponse {
+	res := SubjectResponse{
+		ID:        s.ID,
+		Name:      s.Name,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: optionalTime(s.UpdatedAt),
+	}
+	if s.ParentID != 0 {
+		parentID := s.ParentID
+		res.ParentID = &parentID
+	}
+	return res
+}
+
+func newSubjectResponses(subjects []*entity.Subject) []SubjectResponse {
+	res := make([]SubjectResponse, 0, len(subjects))
+	for _, s := range subjects {
+		res = append(res, newSubjectResponse(s))
+	}
+	return res
+}
+
+func newBookResponses(books []*entity.Book) []BookResponse {
+	res := make([]BookResponse, 0, len(books))
+	for _, b := range books {
+		res = append(res, newBookResponse(b))
+	}
+	return res
+}
+
+// BookPageResponse is a page of books, NextCursor is omitted on the last page
+type BookPageResponse struct {
+	Books      []BookResponse `json:"books"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func newBookPageResponse(p *entity.BookPage) BookPageResponse {
+	return BookPageResponse{
+		Books:      newBookResponses(p.Books),
+		Total:      p.Total,
+		NextCursor: p.NextCursor,
+	}
+}
+
+// BookImportResponse is the report of an import, with the outcome of every row
+type BookImportResponse struct {
+	Created  int                     `json:"created"`
+	Updated  int                     `json:"updated"`
+	Rejected int                     `json:"rejected"`
+	Rows     []BookImportRowResponse `json:"rows"`
+}
+
+// BookImportRowResponse is the outcome of an imported row, the book_id of
+// rejected rows is omitted and only they have a reason
+type BookImportRowResponse struct {
+	Line   int                     `json:"line"`
+	Status entity.BookImportStatus `json:"status"`
+	BookID int                     `json:"book_id,omitempty"`
+	ISBN   string                  `json:"isbn,omitempty"`
+	Reason string                  `json:"reason,omitempty"`
+}
+
+func newBookImportResponse(report *entity.BookImportReport) BookImportResponse {
+	res := BookImportResponse{
+		Created:  report.Created,
+		Updated:  report.Updated,
+		Rejected: report.Rejected,
+		Rows:     make([]BookImportRowResponse, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		r := BookImportRowResponse{
+			Line:   row.Line,
+			Status: row.Status,
+		}
+		if row.Book != nil {
+			r.BookID = row.Book.ID
+			r.ISBN = row.Book.ISBN
+		}
+		if row.Err != nil {
+			// rows are rejected with the messages of the book endpoints
+			r.Reason = row.Err.Error()
+			if msg, ok := invalidBookMessage(row.Err); ok {
+				r.Reason = msg
+			}
+		}
+		res.Rows = append(res.Rows, r)
+	}
+	return res
+}
+
+// BookMatchResponse is a book found by a full-text search, the snippet wraps
+// the matched words in <mark> tags
+type BookMatchResponse struct {
+	BookResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func newBookMatchResponses(matches []*entity.BookMatch) []BookMatchResponse {
+	res := make([]BookMatchResponse, 0, len(matches))
+	for _, m := range matches {
+		res = append(res, BookMatchResponse{
+			BookResponse: newBookResponse(m.Book),
+			Rank:         m.Rank,
+			Snippet:      m.Snippet,
+		})
+	}
+	return res
+}
+
+// CopyRequest is the body accepted when adding or updating a copy,
+// new copies are always available so the status is only read on updates
+type CopyRequest struct {
+	Barcode  string            `json:"barcode"`
+	Status   entity.CopyStatus `json:"status"`
+	Location string            `json:"location"`
+}
+
+func (req CopyRequest) toEntity() *entity.Copy {
+	return &entity.Copy{
+		Barcode:  req.Barcode,
+		Status:   req.Status,
+		Location: req.Location,
+	}
+}
+
+// CopyResponse is the public representation of a copy
+type CopyResponse struct {
+	ID        int               `json:"id"`
+	BookID    int               `json:"book_id"`
+	Barcode   string            `json:"barcode"`
+	Status    entity.CopyStatus `json:"status"`
+	Location  string            `json:"location"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+}
+
+func newCopyResponse(c *entity.Copy) CopyResponse {
+	return CopyResponse{
+		ID:        c.ID,
+		BookID:    c.BookID,
+		Barcode:   c.Barcode,
+		Status:    c.Status,
+		Location:  c.Location,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: optionalTime(c.UpdatedAt),
+	}
+}
+
+func newCopyResponses(copies []*entity.Copy) []CopyResponse {
+	res := make([]CopyResponse, 0, len(copies))
+	for _, c := range copies {
+		res = append(res, newCopyResponse(c))
+	}
+	return res
+}
+
+// StockRequest is the body accepted when adjusting the stock of a book
+type StockRequest struct {
+	Kind     entity.MovementKind `json:"kind"`
+	Quantity int                 `json:"quantity"`
+	Reason   string              `json:"reason"`
+}
+
+func (req StockRequest) toEntity() *entity.InventoryMovement {
+	return &entity.InventoryMovement{
+		Kind:     req.Kind,
+		Quantity: req.Quantity,
+		Reason:   req.Reason,
+	}
+}
+
+// MovementResponse is the public representation of a stock adjustment
+type MovementResponse struct {
+	ID        int                 `json:"id"`
+	BookID    int                 `json:"book_id"`
+	UserID    *int                `json:"user_id"`
+	Kind      entity.MovementKind `json:"kind"`
+	Quantity  int                 `json:"quantity"`
+	Reason    string              `json:"reason"`
+	Barcodes  []string            `json:"barcodes"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func newMovementResponse(m *entity.InventoryMovement) MovementResponse {
+	res := MovementResponse{
+		ID:        m.ID,
+		BookID:    m.BookID,
+		Kind:      m.Kind,
+		Quantity:  m.Quantity,
+		Reason:    m.Reason,
+		Barcodes:  m.Barcodes,
+		CreatedAt: m.CreatedAt,
+	}
+	// the librarian may have been deleted since
+	if m.UserID != 0 {
+		res.UserID = &m.UserID
+	}
+	if res.Barcodes == nil {
+		res.Barcodes = []string{}
+	}
+	return res
+}
+
+func newMovementResponses(movements []*entity.InventoryMovement) []MovementResponse {
+	res := make([]MovementResponse, 0, len(movements))
+	for _, m := range movements {
+		res = append(res, newMovementResponse(m))
+	}
+	return res
+}
+
+// LoanResponse is the public representation of a loan
+type LoanResponse struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	BookID     int        `json:"book_id"`
+	CopyID     int        `json:"copy_id"`
+	IsReturned bool       `json:"is_returned"`
+	DueDate    time.Time  `json:"due_date"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	Renewals   int        `json:"renewals"`
+	RenewedAt  *time.Time `json:"renewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newLoanResponse(l *entity.Loan) LoanResponse {
+	return LoanResponse{
+		ID:         l.ID,
+		UserID:     l.UserID,
+		BookID:     l.BookID,
+		CopyID:     l.CopyID,
+		IsReturned: l.Is_returned,
+		DueDate:    l.DueDate,
+		ReturnedAt: optionalTime(l.ReturnedAt),
+		Renewals:   l.Renewals,
+		RenewedAt:  optionalTime(l.RenewedAt),
+		CreatedAt:  l.CreatedAt,
+	}
+}
+
+func newLoanResponses(loans []*entity.Loan) []LoanResponse {
+	res := make([]LoanResponse, 0, len(loans))
+	for _, l := range loans {
+		res = append(res, newLoanResponse(l))
+	}
+	return res
+}
+
+// HoldResponse is the public representation of a hold
+type HoldResponse struct {
+	ID        int               `json:"id"`
+	UserID    int               `json:"user_id"`
+	BookID    int               `json:"book_id"`
+	Status    entity.HoldStatus `json:"status"`
+	ExpiresAt *time.Time        `json:"expires_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+}
+
+func newHoldResponses(holds []*entity.Hold) []HoldResponse {
+	res := make([]HoldResponse, 0, len(holds))
+	for _, h := range holds {
+		res = append(res, HoldResponse{
+			ID:        h.ID,
+			UserID:    h.UserID,
+			BookID:    h.BookID,
+			Status:    h.Status,
+			ExpiresAt: optionalTime(h.ExpiresAt),
+			CreatedAt: h.CreatedAt,
+			UpdatedAt: optionalTime(h.UpdatedAt),
+		})
+	}
+	return res
+}
+
+// LedgerEntryResponse is a charge or payment in a balance, amounts are in cents
+type LedgerEntryResponse struct {
+	ID        int              `json:"id"`
+	LoanID    int              `json:"loan_id,omitempty"`
+	Kind      entity.EntryKind `json:"kind"`
+	Amount    int              `json:"amount"`
+	Note      string           `json:"note"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// BalanceResponse is how much a patron owes the library, in cents
+type BalanceResponse struct {
+	UserID  int                   `json:"user_id"`
+	Balance int                   `json:"balance"`
+	Entries []LedgerEntryResponse `json:"entries"`
+}
+
+func newBalanceResponse(b *entity.Balance) BalanceResponse {
+	res := BalanceResponse{
+		UserID:  b.UserID,
+		Balance: b.Amount,
+		Entries: make([]LedgerEntryResponse, 0, len(b.Entries)),
+	}
+	for _, e := range b.Entries {
+		res.Entries = append(res.Entries, LedgerEntryResponse{
+			ID:        e.ID,
+			LoanID:    e.LoanID,
+			Kind:      e.Kind,
+			Amount:    e.Amount,
+			Note:      e.Note,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return res
+}
+
+// optionalTime returns nil for zero timestamps so they are encoded as null
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -6,27 +6,115 @@ const (
 	invalidRequestBody = "the request body is invalid or malformed"
 )
 
+// Precondition error response message
+const (
+	preconditionRequired = "the If-Match header with the ETag of the resource is required"
+	versionConflict      = "the resource was changed since its ETag was read, get it again and retry"
+	unsupportedPatch     = "the patch should be a JSON Merge Patch document sent as application/merge-patch+json"
+)
+
 // Book error response message
 const (
-	getBook        = "failed to retrieve the book"
-	bookNotFound   = "the requested book was not found"
-	createBook     = "failed to create the book"
-	updateBook     = "failed to update the book"
-	deleteBook     = "failed to delete the book"
-	searchBook     = "failed to search for books"
-	wrongBodyTitle = "invalid title format, it should be a string"
-	invalidBookID  = "invalid book ID provided, it should be a positive integer"
+	getBook              = "failed to retrieve the book"
+	bookNotFound         = "the requested book was not found"
+	createBook           = "failed to create the book"
+	updateBook           = "failed to update the book"
+	amountChange         = "the amount is the number of available copies, change it with a stock adjustment"
+	deleteBook           = "failed to delete the book"
+	searchBook           = "failed to search for books"
+	listBooks            = "failed to list the books"
+	invalidCursor        = "invalid cursor, use the next_cursor of the previous page with the same sort"
+	invalidBookQuery     = "limit must be between 1 and 100, sort must be title, author or created_at with an optional - prefix, author_id and subject_id must be positive integers and available must be true or false"
+	invalidBookSearch    = "the search needs at least one word in q and limit must be between 1 and 100"
+	wrongBodyTitle       = "invalid title format, it should be a string"
+	invalidBook          = "title and author or authors can't be empty and amount must be positive"
+	invalidISBN          = "invalid ISBN, it should be an ISBN-10 or ISBN-13 with a valid check digit"
+	invalidPublishedYear = "invalid published year, it can't be negative or more than a year ahead"
+	invalidLanguage      = "invalid language, it should be a lowercase ISO 639 code such as en or por"
+	invalidPages         = "invalid pages, it can't be negative"
+	invalidCoverURL      = "invalid cover URL, it should be an absolute http or https URL"
+	isbnAlreadyExists    = "another book has the same ISBN"
+	invalidBookAuthors   = "each author needs an author_id and a role of author, editor or translator, and is credited once per role"
+	bookAuthorNotFound   = "one of the authors of the book was not found"
+	invalidBookSubjects  = "subject_ids must hold positive subject IDs, each listed once"
+	bookSubjectNotFound  = "one of the subjects of the book was not found"
+	invalidBookID        = "invalid book ID provided, it should be a positive integer"
+	importBooks          = "failed to import the books"
+	exportBooks          = "failed to export the books"
+	exportCutShort       = "the export failed before the last book, the catalog is incomplete"
+	invalidExportFormat  = "the export format should be csv, jsonl or marcxml"
+	invalidImportFormat  = "the import format should be csv or jsonl, set the format query parameter or a text/csv or application/jsonl Content-Type"
+	invalidImport        = "the import can't be read"
+	importTooLarge       = "the import is too large, split it in files of at most 10 MB and 5000 rows"
+)
+
+// Copy error response message
+const (
+	getCopy              = "failed to retrieve the copy"
+	listCopies           = "failed to list the book copies"
+	addCopy              = "failed to add the copy"
+	updateCopy           = "failed to update the copy"
+	deleteCopy           = "failed to delete the copy"
+	copyNotFound         = "the requested copy was not found"
+	invalidCopy          = "the barcode can't be empty or have spaces"
+	invalidCopyStatus    = "invalid status, it should be available, on_loan, lost or in_repair"
+	barcodeAlreadyExists = "another copy has the same barcode"
+	copyOnLoan           = "the copy is on loan, it changes status only when borrowed or returned"
+	copyReserved         = "the copies of the book on the shelf are set aside for ready holds"
+	invalidCopyID        = "invalid copy ID provided, it should be a positive integer"
+	adjustStock          = "failed to adjust the stock of the book"
+	listMovements        = "failed to list the stock adjustments of the book"
+	invalidMovement      = "invalid adjustment, the quantity should be between 1 and 100 and the reason can't be empty"
+	invalidMovementKind  = "invalid kind, it should be acquired, lost, damaged, withdrawn or restored"
+	notEnoughCopies      = "the book has fewer copies on the shelf than the adjustment removes, copies on loan can't be adjusted"
+)
+
+// Author error response message
+const (
+	getAuthor       = "failed to retrieve the author"
+	listAuthors     = "failed to list the authors"
+	createAuthor    = "failed to create the author"
+	updateAuthor    = "failed to update the author"
+	deleteAuthor    = "failed to delete the author"
+	authorNotFound  = "the requested author was not found"
+	invalidAuthor   = "the author name can't be empty"
+	authorHasBooks  = "the author is credited on books, remove them from the books first"
+	invalidAuthorID = "invalid author ID provided, it should be a positive integer"
+)
+
+// Subject error response message
+const (
+	getSubject            = "failed to retrieve the subject"
+	listSubjects          = "failed to list the subjects"
+	createSubject         = "failed to create the subject"
+	updateSubject         = "failed to update the subject"
+	deleteSubject         = "failed to delete the subject"
+	subjectNotFound       = "the requested subject was not found"
+	invalidSubject        = "the subject name can't be empty and a subject can't be its own parent"
+	parentSubjectNotFound = "the parent subject was not found"
+	subjectCycle          = "a subject can't be moved below itself or a subject below it"
+	subjectAlreadyExists  = "the parent subject already has a subject with the same name"
+	subjectHasChildren    = "the subject has subjects below it, move or delete them first"
+	invalidSubjectID      = "invalid subject ID provided, it should be a positive integer"
 )
 
 // User error response message
 const (
-	getUser       = "failed to retrieve the user"
-	createUser    = "failed to create the user"
-	updateUser    = "failed to update the user"
-	deleteUser    = "failed to delete the user"
-	userNotFound  = "the requested user was not found"
-	invalidUserID = "invalid user ID provided, it should be a positive integer"
-	alreadyExists = "the username or email already exists"
+	getUser         = "failed to retrieve the user"
+	createUser      = "failed to create the user"
+	updateUser      = "failed to update the user"
+	deleteUser      = "failed to delete the user"
+	userNotFound    = "the requested user was not found"
+	invalidUserID   = "invalid user ID provided, it should be a positive integer"
+	alreadyExists   = "the username or email already exists"
+	invalidCategory = "invalid category, it should be guest, student or staff"
+	changeRole      = "failed to change the user role"
+	invalidRole     = "invalid role, it should be patron or librarian"
+	changePassword  = "failed to change the user password"
+	wrongPassword   = "the current password is incorrect"
+	invalidPassword = "invalid password, it should have between 6 and 72 characters and no spaces"
+	invalidProfile  = "the username and email can't be empty, the username can't have spaces and the email should be a valid address"
+	invalidNewUser  = "the username, email and password can't be empty or have spaces, the email should be a valid address and the password should have between 6 and 72 characters"
 )
 
 // Loan error response message
@@ -38,4 +126,47 @@ const (
 	searchUserLoans     = "failed to search for user loans"
 	returnBookFirst     = "return the book before borrowing it again"
 	bookUnavailable     = "the book is currently unavailable"
+	noOverdueLoans      = "no overdue loans found"
+	listOverdueLoans    = "failed to list overdue loans"
+	renewLoan           = "failed to renew the loan"
+	loanIDNotFound      = "the requested loan was not found"
+	loanOverdue         = "overdue loans can't be renewed, return the book first"
+	renewalLimitReached = "the loan has reached the maximum number of renewals"
+	loanRenewed         = "the loan was renewed at the same time, get it again and retry"
+	invalidLoanID       = "invalid loan ID provided, it should be a positive integer"
+	bookReserved        = "the available copies are reserved for patrons on the hold queue"
+	loanReturned        = "the loan has already been returned"
+	outstandingBalance  = "the user must pay the outstanding fines before borrowing"
+	loanLimitReached    = "the user has reached the maximum number of loans for their category"
+)
+
+// Hold error response message
+const (
+	placeHold         = "failed to place the hold"
+	cancelHold        = "failed to cancel the hold"
+	listHolds         = "failed to list the book holds"
+	holdNotFound      = "the requested hold was not found"
+	holdAlreadyPlaced = "the user already has an active hold for this book"
+	holdNotActive     = "the hold was already fulfilled, cancelled or expired"
+	bookAvailable     = "the book is available, borrow it instead"
+	invalidHoldID     = "invalid hold ID provided, it should be a positive integer"
+)
+
+// Fine error response message
+const (
+	getBalance         = "failed to retrieve the user balance"
+	payFine            = "failed to register the payment"
+	invalidAmount      = "invalid amount provided, it should be a positive number of cents"
+	paymentExceedsDebt = "the payment is larger than the user balance"
+)
+
+// Auth error response message
+const (
+	login              = "failed to log in"
+	invalidCredentials = "invalid username or password"
+	invalidToken       = "the session token is invalid or has expired"
+	unauthenticated    = "log in to access this resource"
+	forbidden          = "you are not allowed to access this resource"
+	resetPassword      = "failed to reset the password"
+	invalidResetToken  = "the reset token is invalid, has expired or was already used"
 )
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// etag is the entity tag of a version of a book or user
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatch reads the version a write expects from the If-Match header, writing
+// a precondition required response when the header is missing and a
+// precondition failed one when it names no version of the resource
+func ifMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	if tag == "" || tag == "*" {
+		http.Error(w, preconditionRequired, http.StatusPreconditionRequired)
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.Trim(tag, `"`))
+	if err != nil || tag != etag(version) {
+		http.Error(w, versionConflict, http.StatusPreconditionFailed)
+		return 0, false
+	}
+
+	return version, true
+}
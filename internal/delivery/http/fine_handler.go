@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type fineHandler struct {
+	FineUsecase uc.FineUsecase
+}
+
+// NewFineHandler creates a new instance of fineHandler
+func NewFineHandler(r *chi.Mux, useCase uc.FineUsecase) {
+	handler := &fineHandler{
+		FineUsecase: useCase,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Get("/v1/users/{id}/balance", handler.GetBalance)
+		// payments are registered at the desk
+		r.With(requireLibrarian).Post("/v1/users/{id}/payments", handler.PayFine)
+	})
+}
+
+type PaymentRequest struct {
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+func (h *fineHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	if !authorizeUser(w, r, userID) {
+		return
+	}
+
+	ctx := r.Context()
+	balance, err := h.FineUsecase.GetBalance(ctx, userID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrUserNotFound:
+				http.Error(w, userNotFound, http.StatusNotFound)
+			default:
+				http.Error(w, getBalance, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newBalanceResponse(balance)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, getBalance, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *fineHandler) PayFine(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	var req PaymentRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	id, err := h.FineUsecase.PayFine(ctx, userID, req.Amount, req.Note)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrUserNotFound:
+				http.Error(w, userNotFound, http.StatusNotFound)
+			case entity.ErrInvalidAmount:
+				http.Error(w, invalidAmount, http.StatusBadRequest)
+			case ucErr.ErrPaymentExceedsDebt:
+				http.Error(w, paymentExceedsDebt, http.StatusUnprocessableEntity)
+			default:
+				http.Error(w, payFine, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(map[string]int{"id": id}); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, payFine, http.StatusInternalServerError)
+		return
+	}
+}
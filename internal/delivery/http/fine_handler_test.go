@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetBalance(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockFineUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					GetBalance(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Balance{UserID: 1, Amount: 75, Entries: []*entity.LedgerEntry{}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var balance entity.Balance
+				err := json.NewDecoder(recorder.Body).Decode(&balance)
+				assert.NoError(t, err)
+				assert.Equal(t, 75, balance.Amount)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"User Not Found": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockFineUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/users/", tc.ID, "/balance")
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewFineHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPayFine(t *testing.T) {
+	type testPaymentRequest struct {
+		Amount any    `json:"amount"`
+		Note   string `json:"note"`
+	}
+
+	testCases := map[string]struct {
+		ID            any
+		body          testPaymentRequest
+		buildStubs    func(uc *mock.MockFineUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   1,
+			body: testPaymentRequest{Amount: 75, Note: "cash"},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Eq(1), gomock.Eq(75), gomock.Eq("cash")).
+					Times(1).
+					Return(1, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:   "ID",
+			body: testPaymentRequest{Amount: 75},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			ID:   1,
+			body: testPaymentRequest{Amount: "75"},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Amount": {
+			ID:   1,
+			body: testPaymentRequest{Amount: -75},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidAmount)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"User Not Found": {
+			ID:   1,
+			body: testPaymentRequest{Amount: 75},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Exceeds Balance": {
+			ID:   1,
+			body: testPaymentRequest{Amount: 75},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, ucErr.ErrPaymentExceedsDebt)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:   1,
+			body: testPaymentRequest{Amount: 75},
+			buildStubs: func(uc *mock.MockFineUsecase) {
+				uc.EXPECT().
+					PayFine(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockFineUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/users/", tc.ID, "/payments")
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewFineHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type holdHandler struct {
+	HoldUsecase uc.HoldUsecase
+}
+
+// NewHoldHandler creates a new instance of holdHandler
+func NewHoldHandler(r *chi.Mux, useCase uc.HoldUsecase) {
+	handler := &holdHandler{
+		HoldUsecase: useCase,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Post("/v1/books/{id}/holds", handler.PlaceHold)
+		r.With(requireLibrarian).Get("/v1/books/{id}/holds", handler.ListBookHolds)
+		r.Delete("/v1/holds/{id}", handler.CancelHold)
+	})
+}
+
+type HoldRequest struct {
+	UserID int `json:"user_id"`
+}
+
+func (h *holdHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	var req HoldRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	if !authorizeUser(w, r, req.UserID) {
+		return
+	}
+
+	ctx := r.Context()
+	id, err := h.HoldUsecase.PlaceHold(ctx, req.UserID, bookID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrBookNotFound:
+				http.Error(w, bookNotFound, http.StatusNotFound)
+			case repoErr.ErrUserNotFound:
+				http.Error(w, userNotFound, http.StatusNotFound)
+			case ucErr.ErrHoldAlreadyPlaced:
+				http.Error(w, holdAlreadyPlaced, http.StatusConflict)
+			case ucErr.ErrBookAvailable:
+				http.Error(w, bookAvailable, http.StatusConflict)
+			default:
+				http.Error(w, placeHold, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(map[string]int{"id": id}); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, placeHold, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *holdHandler) ListBookHolds(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	holds, err := h.HoldUsecase.ListBookHolds(ctx, bookID)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			http.Error(w, listHolds, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newHoldResponses(holds)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listHolds, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *holdHandler) CancelHold(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidHoldID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	// patrons can only cancel their own holds
+	if claims, _ := claimsFromContext(ctx); !claims.IsLibrarian() {
+		hold, err := h.HoldUsecase.GetHold(ctx, id)
+		if err != nil {
+			log.Error().Msg(err.Error())
+
+			select {
+			case <-ctx.Done():
+				http.Error(w, timeout, http.StatusGatewayTimeout)
+			default:
+				if err == repoErr.ErrHoldNotFound {
+					http.Error(w, holdNotFound, http.StatusNotFound)
+				} else {
+					http.Error(w, cancelHold, http.StatusInternalServerError)
+				}
+			}
+			return
+		}
+
+		if !authorizeUser(w, r, hold.UserID) {
+			return
+		}
+	}
+
+	err = h.HoldUsecase.CancelHold(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrHoldNotFound:
+				http.Error(w, holdNotFound, http.StatusNotFound)
+			case ucErr.ErrHoldNotActive:
+				http.Error(w, holdNotActive, http.StatusConflict)
+			default:
+				http.Error(w, cancelHold, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
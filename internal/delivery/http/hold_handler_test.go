@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestPlaceHold(t *testing.T) {
+	type testHoldRequest struct {
+		UserID any `json:"user_id"`
+	}
+
+	testCases := map[string]struct {
+		ID            any
+		body          testHoldRequest
+		buildStubs    func(uc *mock.MockHoldUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   1,
+			body: testHoldRequest{UserID: 1},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Eq(1), gomock.Eq(1)).
+					Times(1).
+					Return(1, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:   "ID",
+			body: testHoldRequest{UserID: 1},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			ID:   1,
+			body: testHoldRequest{UserID: "1"},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Book Not Found": {
+			ID:   1,
+			body: testHoldRequest{UserID: 1},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrBookNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Already Placed": {
+			ID:   1,
+			body: testHoldRequest{UserID: 1},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, ucErr.ErrHoldAlreadyPlaced)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Book Available": {
+			ID:   1,
+			body: testHoldRequest{UserID: 1},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, ucErr.ErrBookAvailable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:   1,
+			body: testHoldRequest{UserID: 1},
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					PlaceHold(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockHoldUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/books/", tc.ID, "/holds")
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewHoldHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListBookHolds(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockHoldUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					ListBookHolds(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return([]*entity.Hold{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					ListBookHolds(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					ListBookHolds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockHoldUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/books/", tc.ID, "/holds")
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewHoldHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCancelHold(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockHoldUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					CancelHold(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					CancelHold(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					CancelHold(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrHoldNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Not Active": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					CancelHold(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrHoldNotActive)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockHoldUsecase) {
+				uc.EXPECT().
+					CancelHold(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockHoldUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/holds/", tc.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewHoldHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -10,8 +10,8 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog/log"
 
-	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
 )
 
@@ -26,9 +26,14 @@ func NewLoanHandler(r *chi.Mux, useCase uc.LoanUsecase) {
 	}
 
 	r.Route("/v1/loans", func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.With(requireLibrarian).Get("/overdue", handler.ListOverdueLoans)
 		r.Get("/{id}", handler.SearchUserLoans)
 		r.Post("/borrow", handler.BorrowBook)
 		r.Post("/return", handler.ReturnBook)
+		r.Post("/{id}/return", handler.ReturnLoan)
+		r.Post("/{id}/renew", handler.RenewLoan)
 	})
 }
 
@@ -40,6 +45,10 @@ func (h *loanHandler) SearchUserLoans(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizeUser(w, r, id) {
+		return
+	}
+
 	ctx := r.Context()
 	l, err := h.LoanUsecase.SearchUserLoans(ctx, id)
 	if err != nil {
@@ -61,13 +70,42 @@ func (h *loanHandler) SearchUserLoans(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(l); err != nil {
+	if err := json.NewEncoder(w).Encode(newLoanResponses(l)); err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, searchUserLoans, http.StatusInternalServerError)
 		return
 	}
 }
 
+func (h *loanHandler) ListOverdueLoans(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l, err := h.LoanUsecase.ListOverdueLoans(ctx)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrNoOverdueLoans {
+				http.Error(w, noOverdueLoans, http.StatusNotFound)
+			} else {
+				http.Error(w, listOverdueLoans, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newLoanResponses(l)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listOverdueLoans, http.StatusInternalServerError)
+		return
+	}
+}
+
 type LoanRequest struct {
 	UserID int `json:"user_id"`
 	BookID int `json:"book_id"`
@@ -83,6 +121,10 @@ func (h *loanHandler) BorrowBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizeUser(w, r, req.UserID) {
+		return
+	}
+
 	ctx := r.Context()
 	err = h.LoanUsecase.BorrowBook(ctx, req.UserID, req.BookID)
 	if err != nil {
@@ -101,6 +143,12 @@ func (h *loanHandler) BorrowBook(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, returnBookFirst, http.StatusBadRequest)
 			case ucErr.ErrBookUnavailable:
 				http.Error(w, bookUnavailable, http.StatusNotFound)
+			case ucErr.ErrBookReserved:
+				http.Error(w, bookReserved, http.StatusConflict)
+			case ucErr.ErrOutstandingBalance:
+				http.Error(w, outstandingBalance, http.StatusForbidden)
+			case ucErr.ErrLoanLimitReached:
+				http.Error(w, loanLimitReached, http.StatusForbidden)
 			default:
 				http.Error(w, borrowBook, http.StatusInternalServerError)
 			}
@@ -122,6 +170,10 @@ func (h *loanHandler) ReturnBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizeUser(w, r, req.UserID) {
+		return
+	}
+
 	ctx := r.Context()
 	err = h.LoanUsecase.ReturnBook(ctx, req.UserID, req.BookID)
 	if err != nil {
@@ -142,3 +194,137 @@ func (h *loanHandler) ReturnBook(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *loanHandler) ReturnLoan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidLoanID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	// patrons can only return their own loans
+	if claims, _ := claimsFromContext(ctx); !claims.IsLibrarian() {
+		l, err := h.LoanUsecase.GetLoan(ctx, id)
+		if err != nil {
+			log.Error().Msg(err.Error())
+
+			select {
+			case <-ctx.Done():
+				http.Error(w, timeout, http.StatusGatewayTimeout)
+			default:
+				if err == repoErr.ErrLoanIDNotFound {
+					http.Error(w, loanIDNotFound, http.StatusNotFound)
+				} else {
+					http.Error(w, returnBook, http.StatusInternalServerError)
+				}
+			}
+			return
+		}
+
+		if !authorizeUser(w, r, l.UserID) {
+			return
+		}
+	}
+
+	l, err := h.LoanUsecase.ReturnLoan(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrLoanIDNotFound:
+				http.Error(w, loanIDNotFound, http.StatusNotFound)
+			case ucErr.ErrLoanAlreadyReturned:
+				http.Error(w, loanReturned, http.StatusConflict)
+			default:
+				http.Error(w, returnBook, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newLoanResponse(l)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, returnBook, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *loanHandler) RenewLoan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidLoanID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	// patrons can only renew their own loans
+	if claims, _ := claimsFromContext(ctx); !claims.IsLibrarian() {
+		l, err := h.LoanUsecase.GetLoan(ctx, id)
+		if err != nil {
+			log.Error().Msg(err.Error())
+
+			select {
+			case <-ctx.Done():
+				http.Error(w, timeout, http.StatusGatewayTimeout)
+			default:
+				if err == repoErr.ErrLoanIDNotFound {
+					http.Error(w, loanIDNotFound, http.StatusNotFound)
+				} else {
+					http.Error(w, renewLoan, http.StatusInternalServerError)
+				}
+			}
+			return
+		}
+
+		if !authorizeUser(w, r, l.UserID) {
+			return
+		}
+	}
+
+	l, err := h.LoanUsecase.RenewLoan(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrLoanIDNotFound:
+				http.Error(w, loanIDNotFound, http.StatusNotFound)
+			case ucErr.ErrLoanAlreadyReturned:
+				http.Error(w, loanAlreadyReturned, http.StatusNotFound)
+			case ucErr.ErrLoanOverdue:
+				http.Error(w, loanOverdue, http.StatusConflict)
+			case ucErr.ErrRenewalLimitReached:
+				http.Error(w, renewalLimitReached, http.StatusConflict)
+			case repoErr.ErrLoanRenewed:
+				http.Error(w, loanRenewed, http.StatusConflict)
+			default:
+				http.Error(w, renewLoan, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newLoanResponse(l)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, renewLoan, http.StatusInternalServerError)
+		return
+	}
+}
@@ -8,15 +8,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
-	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
 	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 func TestSearchUserLoan(t *testing.T) {
@@ -86,6 +87,68 @@ func TestSearchUserLoan(t *testing.T) {
 			url := fmt.Sprint("/v1/loans/", tc.ID)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewLoanHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListOverdueLoans(t *testing.T) {
+	testCases := map[string]struct {
+		buildStubs    func(uc *mock.MockLoanUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ListOverdueLoans(gomock.Any()).
+					Times(1).
+					Return([]*entity.Loan{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Not Found": {
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ListOverdueLoans(gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrNoOverdueLoans)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ListOverdueLoans(gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockLoanUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/v1/loans/overdue", nil)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewLoanHandler(router, uc)
@@ -183,6 +246,42 @@ func TestBorrowBook(t *testing.T) {
 				assert.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		"Book Reserved": {
+			body: testLoanRequest{},
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					BorrowBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrBookReserved)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Outstanding Balance": {
+			body: testLoanRequest{},
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					BorrowBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrOutstandingBalance)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		"Loan Limit Reached": {
+			body: testLoanRequest{},
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					BorrowBook(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrLoanLimitReached)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		"Unexpected Error": {
 			body: testLoanRequest{},
 			buildStubs: func(uc *mock.MockLoanUsecase) {
@@ -211,6 +310,7 @@ func TestBorrowBook(t *testing.T) {
 
 			request, err := http.NewRequest(http.MethodPost, "/v1/loans/borrow", bytes.NewReader(data))
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewLoanHandler(router, uc)
@@ -300,6 +400,213 @@ func TestReturnBook(t *testing.T) {
 
 			request, err := http.NewRequest(http.MethodPost, "/v1/loans/return", bytes.NewReader(data))
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewLoanHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReturnLoan(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockLoanUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ReturnLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, Is_returned: true, ReturnedAt: time.Now()}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got LoanResponse
+				assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+				assert.True(t, got.IsReturned)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ReturnLoan(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ReturnLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrLoanIDNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Already Returned": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ReturnLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, ucErr.ErrLoanAlreadyReturned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					ReturnLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockLoanUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/loans/", tc.ID, "/return")
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewLoanHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRenewLoan(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockLoanUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Loan{ID: 1, Renewals: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrLoanIDNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Overdue": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, ucErr.ErrLoanOverdue)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Renewal Limit Reached": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, ucErr.ErrRenewalLimitReached)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Renewed Concurrently": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrLoanRenewed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockLoanUsecase) {
+				uc.EXPECT().
+					RenewLoan(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockLoanUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/loans/", tc.ID, "/renew")
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewLoanHandler(router, uc)
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// mergePatchType is the media type of JSON Merge Patch documents
+const mergePatchType = "application/merge-patch+json"
+
+// mergePatch holds the members of a JSON Merge Patch document, RFC 7396
+type mergePatch map[string]json.RawMessage
+
+// has reports whether the patch holds the member, even set to null
+func (doc mergePatch) has(name string) bool {
+	_, ok := doc[name]
+	return ok
+}
+
+// decodeMergePatch reads the merge patch of the request body into req and
+// returns its members, null members leave the zero value in req to clear the
+// field. It writes an unsupported media type response for other content types
+// and a bad request one for bodies that aren't a JSON object
+func decodeMergePatch(w http.ResponseWriter, r *http.Request, req any) (mergePatch, bool) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != mergePatchType && mediaType != "application/json") {
+		http.Error(w, unsupportedPatch, http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return nil, false
+	}
+
+	var doc mergePatch
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return nil, false
+	}
+
+	if err := json.Unmarshal(body, req); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return nil, false
+	}
+
+	return doc, true
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type contextKey string
+
+const (
+	claimsKey       contextKey = "claims"
+	invalidTokenKey contextKey = "invalid_token"
+)
+
+// exportPath is the route streaming the whole catalog
+const exportPath = "/v1/books/export"
+
+// Timeout ends requests running longer than the timeout with a 504, except
+// the catalog export which streams for as long as the client keeps reading
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		timed := middleware.Timeout(timeout)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet && r.URL.Path == exportPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			timed.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Authenticate reads the bearer token of every request, storing the claims of valid tokens
+// in the request context. Anonymous requests and requests with an invalid or expired token
+// are let through without claims, routes requiring a user reject them with requireAuth
+func Authenticate(useCase uc.AuthUsecase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, invalidTokenKey, true)))
+				return
+			}
+
+			claims, err := useCase.Authenticate(ctx, token)
+			if err != nil {
+				log.Error().Msg(err.Error())
+
+				select {
+				case <-ctx.Done():
+					http.Error(w, timeout, http.StatusGatewayTimeout)
+				default:
+					next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, invalidTokenKey, true)))
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withClaims(ctx, claims)))
+		})
+	}
+}
+
+// requireAuth rejects anonymous requests and requests with an invalid token
+func requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if _, ok := claimsFromContext(ctx); !ok {
+			if invalid, _ := ctx.Value(invalidTokenKey).(bool); invalid {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, invalidToken, http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, unauthenticated, http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireLibrarian rejects requests of users who are not librarians
+func requireLibrarian(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := claimsFromContext(r.Context())
+		if !ok || !claims.IsLibrarian() {
+			http.Error(w, forbidden, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorizeUser checks that the authenticated user can act on behalf of the user,
+// writing a forbidden response when it can't
+func authorizeUser(w http.ResponseWriter, r *http.Request, userID int) bool {
+	claims, ok := claimsFromContext(r.Context())
+	if !ok || !claims.CanActFor(userID) {
+		http.Error(w, forbidden, http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// withClaims stores the authenticated user claims in the context
+func withClaims(ctx context.Context, claims *entity.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// claimsFromContext gets the authenticated user claims from the context
+func claimsFromContext(ctx context.Context) (*entity.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*entity.Claims)
+	return claims, ok
+}
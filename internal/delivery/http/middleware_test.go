@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/auth"
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+var (
+	librarian = &entity.Claims{UserID: 1, Username: "user123", Role: entity.RoleLibrarian}
+	patron    = &entity.Claims{UserID: 2, Username: "user456", Role: entity.RolePatron}
+)
+
+// authenticated returns the request as made by the user of the claims
+func authenticated(r *http.Request, claims *entity.Claims) *http.Request {
+	return r.WithContext(withClaims(r.Context(), claims))
+}
+
+func TestAuthenticate(t *testing.T) {
+	testCases := map[string]struct {
+		path          string
+		header        string
+		buildStubs    func(uc *mock.MockAuthUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			header: "Bearer valid",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Authenticate(gomock.Any(), gomock.Eq("valid")).
+					Times(1).
+					Return(patron, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, patron.Username, recorder.Body.String())
+			},
+		},
+		"Missing Token": {
+			header: "",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Authenticate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+			},
+		},
+		"Wrong Scheme": {
+			header: "Basic dXNlcjpwYXNz",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Authenticate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		"Invalid Token": {
+			header: "Bearer expired",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Authenticate(gomock.Any(), gomock.Eq("expired")).
+					Times(1).
+					Return(nil, auth.ErrExpiredToken)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assert.Equal(t, `Bearer error="invalid_token"`, recorder.Header().Get("WWW-Authenticate"))
+			},
+		},
+		"Invalid Token On Public Route": {
+			path:   "/public",
+			header: "Bearer expired",
+			buildStubs: func(uc *mock.MockAuthUsecase) {
+				uc.EXPECT().
+					Authenticate(gomock.Any(), gomock.Eq("expired")).
+					Times(1).
+					Return(nil, auth.ErrExpiredToken)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "anonymous", recorder.Body.String())
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockAuthUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			path := "/protected"
+			if tc.path != "" {
+				path = tc.path
+			}
+
+			request, err := http.NewRequest(http.MethodGet, path, nil)
+			assert.NoError(t, err)
+			if tc.header != "" {
+				request.Header.Set("Authorization", tc.header)
+			}
+
+			router := chi.NewRouter()
+			router.Use(Authenticate(uc))
+			router.With(requireAuth).Get("/protected", func(w http.ResponseWriter, r *http.Request) {
+				claims, _ := claimsFromContext(r.Context())
+				w.Write([]byte(claims.Username))
+			})
+			router.Get("/public", func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := claimsFromContext(r.Context()); !ok {
+					w.Write([]byte("anonymous"))
+				}
+			})
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		url          string
+		wantDeadline bool
+	}{
+		"Timed":  {url: "/v1/books", wantDeadline: true},
+		"Export": {url: "/v1/books/export?format=csv", wantDeadline: false},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var hasDeadline bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+			})
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, http.NoBody)
+			assert.NoError(t, err)
+
+			Timeout(time.Minute)(next).ServeHTTP(httptest.NewRecorder(), request)
+			assert.Equal(t, tc.wantDeadline, hasDeadline)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type subjectHandler struct {
+	SubjectUsecase uc.SubjectUsecase
+}
+
+// NewSubjectHandler creates a new instance of subjectHandler
+func NewSubjectHandler(r *chi.Mux, useCase uc.SubjectUsecase) {
+	handler := &subjectHandler{
+		SubjectUsecase: useCase,
+	}
+
+	r.Route("/v1/subjects", func(r chi.Router) {
+		r.Use(requireAuth)
+
+		r.Get("/", handler.ListSubjects)
+		r.Get("/{id}", handler.GetSubject)
+		r.Get("/{id}/books", handler.ListSubjectBooks)
+		// only librarians manage the catalog
+		r.Group(func(r chi.Router) {
+			r.Use(requireLibrarian)
+
+			r.Post("/", handler.CreateSubject)
+			r.Put("/{id}", handler.UpdateSubject)
+			r.Delete("/{id}", handler.DeleteSubject)
+		})
+	})
+}
+
+func (h *subjectHandler) GetSubject(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidSubjectID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	s, err := h.SubjectUsecase.GetSubject(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrSubjectNotFound {
+				http.Error(w, subjectNotFound, http.StatusNotFound)
+			} else {
+				http.Error(w, getSubject, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newSubjectResponse(s)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, getSubject, http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListSubjects lists the whole taxonomy, clients rebuild the tree from the parent_id
+func (h *subjectHandler) ListSubjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	subjects, err := h.SubjectUsecase.ListSubjects(ctx)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			http.Error(w, listSubjects, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newSubjectResponses(subjects)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listSubjects, http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListSubjectBooks lists a page of the books tagged with the subject or any
+// subject below it, it takes the same query string as the book listing
+func (h *subjectHandler) ListSubjectBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidSubjectID, http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseBookQuery(r)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidBookQuery, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	page, err := h.SubjectUsecase.ListSubjectBooks(ctx, id, q)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrSubjectNotFound:
+				http.Error(w, subjectNotFound, http.StatusNotFound)
+			case entity.ErrInvalidBookQuery:
+				http.Error(w, invalidBookQuery, http.StatusBadRequest)
+			case repoErr.ErrInvalidCursor:
+				http.Error(w, invalidCursor, http.StatusBadRequest)
+			default:
+				http.Error(w, listBooks, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newBookPageResponse(page)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, listBooks, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *subjectHandler) CreateSubject(w http.ResponseWriter, r *http.Request) {
+	var req SubjectRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	id, err := h.SubjectUsecase.CreateSubject(ctx, req.toEntity())
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if msg, status, ok := invalidSubjectMessage(err); ok {
+				http.Error(w, msg, status)
+			} else {
+				http.Error(w, createSubject, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(map[string]int{"id": id}); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, createSubject, http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *subjectHandler) UpdateSubject(w http.ResponseWriter, r *http.Request) {
+	var req SubjectRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	s := req.toEntity()
+	s.ID, err = strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidSubjectID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.SubjectUsecase.UpdateSubject(ctx, s)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			if err == repoErr.ErrSubjectNotFound {
+				http.Error(w, subjectNotFound, http.StatusNotFound)
+			} else if msg, status, ok := invalidSubjectMessage(err); ok {
+				http.Error(w, msg, status)
+			} else {
+				http.Error(w, updateSubject, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invalidSubjectMessage returns the response message and status of the errors
+// caused by the subject sent by the client
+func invalidSubjectMessage(err error) (string, int, bool) {
+	switch err {
+	case entity.ErrInvalidSubject:
+		return invalidSubject, http.StatusBadRequest, true
+	case usecase.ErrParentSubjectNotFound:
+		return parentSubjectNotFound, http.StatusBadRequest, true
+	case usecase.ErrSubjectCycle:
+		return subjectCycle, http.StatusConflict, true
+	case repoErr.ErrSubjectAlreadyExists:
+		return subjectAlreadyExists, http.StatusConflict, true
+	}
+
+	return "", 0, false
+}
+
+func (h *subjectHandler) DeleteSubject(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidSubjectID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.SubjectUsecase.DeleteSubject(ctx, id)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrSubjectNotFound:
+				http.Error(w, subjectNotFound, http.StatusNotFound)
+			case repoErr.ErrSubjectHasChildren:
+				http.Error(w, subjectHasChildren, http.StatusConflict)
+			default:
+				http.Error(w, deleteSubject, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,452 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestGetSubject(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockSubjectUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 2,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					GetSubject(gomock.Any(), gomock.Eq(2)).
+					Times(1).
+					Return(&entity.Subject{ID: 2, Name: "Programming", ParentID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got SubjectResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, "Programming", got.Name)
+				assert.Equal(t, 1, *got.ParentID)
+			},
+		},
+		"Top Subject": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					GetSubject(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(&entity.Subject{ID: 1, Name: "Computing"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), `"parent_id":null`)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					GetSubject(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					GetSubject(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(nil, repoErr.ErrSubjectNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					GetSubject(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockSubjectUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/subjects/", tc.ID)
+			request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, patron)
+
+			router := chi.NewRouter()
+			NewSubjectHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListSubjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := mock.NewMockSubjectUsecase(ctrl)
+	uc.EXPECT().
+		ListSubjects(gomock.Any()).
+		Times(1).
+		Return([]*entity.Subject{{ID: 1, Name: "Computing"}, {ID: 2, Name: "Programming", ParentID: 1}}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/subjects/", http.NoBody)
+	assert.NoError(t, err)
+	request = authenticated(request, patron)
+
+	router := chi.NewRouter()
+	NewSubjectHandler(router, uc)
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []SubjectResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Len(t, got, 2)
+}
+
+func TestListSubjectBooks(t *testing.T) {
+	testCases := map[string]struct {
+		url           string
+		buildStubs    func(uc *mock.MockSubjectUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			url: "/v1/subjects/1/books?sort=-created_at&limit=1",
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					ListSubjectBooks(gomock.Any(), gomock.Eq(1), gomock.Eq(entity.BookQuery{Sort: "-created_at", Limit: 1})).
+					Times(1).
+					Return(&entity.BookPage{Books: []*entity.Book{{ID: 1, Title: "Let's Go"}}, Total: 2, NextCursor: "next"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var got BookPageResponse
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				assert.Equal(t, 2, got.Total)
+				assert.Equal(t, "Let's Go", got.Books[0].Title)
+			},
+		},
+		"Invalid URL Param": {
+			url: "/v1/subjects/ID/books",
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					ListSubjectBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Query": {
+			url: "/v1/subjects/1/books?sort=amount",
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					ListSubjectBooks(gomock.Any(), gomock.Eq(1), gomock.Any()).
+					Times(1).
+					Return(nil, entity.ErrInvalidBookQuery)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			url: "/v1/subjects/10/books",
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					ListSubjectBooks(gomock.Any(), gomock.Eq(10), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrSubjectNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockSubjectUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, patron)
+
+			router := chi.NewRouter()
+			NewSubjectHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateSubject(t *testing.T) {
+	subject := SubjectRequest{Name: "Programming", ParentID: 1}
+
+	testCases := map[string]struct {
+		body          any
+		buildStubs    func(uc *mock.MockSubjectUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			body: subject,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					CreateSubject(gomock.Any(), gomock.Eq(subject.toEntity())).
+					Times(1).
+					Return(2, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			body: map[string]any{"name": 1},
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					CreateSubject(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Parent Not Found": {
+			body: SubjectRequest{Name: "Programming", ParentID: 10},
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					CreateSubject(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, usecase.ErrParentSubjectNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Already Exists": {
+			body: subject,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					CreateSubject(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, repoErr.ErrSubjectAlreadyExists)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockSubjectUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/subjects/", bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewSubjectHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateSubject(t *testing.T) {
+	subject := SubjectRequest{Name: "Computing", ParentID: 2}
+
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockSubjectUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 3,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					UpdateSubject(gomock.Any(), gomock.Eq(&entity.Subject{ID: 3, Name: subject.Name, ParentID: subject.ParentID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Cycle": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					UpdateSubject(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(usecase.ErrSubjectCycle)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID: "ID",
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					UpdateSubject(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					UpdateSubject(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrSubjectNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockSubjectUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(subject)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/subjects/", tc.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewSubjectHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteSubject(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		buildStubs    func(uc *mock.MockSubjectUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID: 2,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					DeleteSubject(gomock.Any(), gomock.Eq(2)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Has Children": {
+			ID: 1,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					DeleteSubject(gomock.Any(), gomock.Eq(1)).
+					Times(1).
+					Return(repoErr.ErrSubjectHasChildren)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID: 10,
+			buildStubs: func(uc *mock.MockSubjectUsecase) {
+				uc.EXPECT().
+					DeleteSubject(gomock.Any(), gomock.Eq(10)).
+					Times(1).
+					Return(repoErr.ErrSubjectNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockSubjectUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/subjects/", tc.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, http.NoBody)
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewSubjectHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -8,8 +8,9 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog/log"
 
-	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 	uc "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
 )
 
@@ -24,10 +25,19 @@ func NewUserHandler(r *chi.Mux, useCase uc.UserUsecase) {
 	}
 
 	r.Route("/v1/users", func(r chi.Router) {
-		r.Get("/{id}", handler.GetUser)
+		// anyone can sign up
 		r.Post("/", handler.CreateUser)
-		r.Put("/{id}", handler.UpdateUser)
-		r.Delete("/{id}", handler.DeleteUser)
+
+		r.Group(func(r chi.Router) {
+			r.Use(requireAuth)
+
+			r.Get("/{id}", handler.GetUser)
+			r.Put("/{id}", handler.UpdateUser)
+			r.Patch("/{id}", handler.PatchUser)
+			r.Put("/{id}/password", handler.ChangePassword)
+			r.Delete("/{id}", handler.DeleteUser)
+			r.With(requireLibrarian).Put("/{id}/role", handler.ChangeRole)
+		})
 	})
 }
 
@@ -39,6 +49,10 @@ func (h *userHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizeUser(w, r, id) {
+		return
+	}
+
 	ctx := r.Context()
 	u, err := h.UserUsecase.GetUser(ctx, id)
 	if err != nil {
@@ -58,9 +72,10 @@ func (h *userHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(u.Version))
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(u); err != nil {
+	if err := json.NewEncoder(w).Encode(newUserResponse(u)); err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, getUser, http.StatusInternalServerError)
 		return
@@ -68,17 +83,24 @@ func (h *userHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var u entity.User
+	var req UserRequest
 
-	err := json.NewDecoder(r.Body).Decode(&u)
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, invalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
+	u := req.toEntity()
+
+	// only librarians choose the category, users signing up are guests
+	if claims, ok := claimsFromContext(r.Context()); !ok || !claims.IsLibrarian() {
+		u.Category = ""
+	}
+
 	ctx := r.Context()
-	id, err := h.UserUsecase.CreateUser(ctx, &u)
+	id, err := h.UserUsecase.CreateUser(ctx, u)
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -86,9 +108,19 @@ func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		case <-ctx.Done():
 			http.Error(w, timeout, http.StatusGatewayTimeout)
 		default:
-			if err == repoErr.ErrAlreadyExists {
+			switch err {
+			case repoErr.ErrAlreadyExists:
 				http.Error(w, alreadyExists, http.StatusBadRequest)
-			} else {
+			case entity.ErrInvalidCategory:
+				http.Error(w, invalidCategory, http.StatusBadRequest)
+			case entity.ErrInvalidEmail:
+				http.Error(w, invalidProfile, http.StatusBadRequest)
+			case entity.ErrShortPassword, entity.ErrLongPassword:
+				http.Error(w, invalidPassword, http.StatusBadRequest)
+			case entity.ErrEmptyUserField, entity.ErrFieldWithSpaces:
+				// the username, the email or the password
+				http.Error(w, invalidNewUser, http.StatusBadRequest)
+			default:
 				http.Error(w, createUser, http.StatusInternalServerError)
 			}
 		}
@@ -106,15 +138,16 @@ func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	var u entity.User
+	var req UserRequest
 
-	err := json.NewDecoder(r.Body).Decode(&u)
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		log.Error().Msg(err.Error())
 		http.Error(w, invalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
+	u := req.toEntity()
 	u.ID, err = strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		log.Error().Msg(err.Error())
@@ -122,8 +155,22 @@ func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizeUser(w, r, u.ID) {
+		return
+	}
+
+	var ok bool
+	if u.Version, ok = ifMatch(w, r); !ok {
+		return
+	}
+
+	// patrons can't change their own category
+	if claims, _ := claimsFromContext(r.Context()); !claims.IsLibrarian() {
+		u.Category = ""
+	}
+
 	ctx := r.Context()
-	err = h.UserUsecase.UpdateUser(ctx, &u)
+	err = h.UserUsecase.UpdateUser(ctx, u)
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -134,8 +181,14 @@ func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 			switch err {
 			case repoErr.ErrUserNotFound:
 				http.Error(w, userNotFound, http.StatusNotFound)
+			case repoErr.ErrVersionConflict:
+				http.Error(w, versionConflict, http.StatusPreconditionFailed)
 			case repoErr.ErrAlreadyExists:
 				http.Error(w, alreadyExists, http.StatusBadRequest)
+			case entity.ErrInvalidCategory:
+				http.Error(w, invalidCategory, http.StatusBadRequest)
+			case entity.ErrEmptyUserField, entity.ErrFieldWithSpaces, entity.ErrInvalidEmail:
+				http.Error(w, invalidProfile, http.StatusBadRequest)
 			default:
 				http.Error(w, updateUser, http.StatusInternalServerError)
 			}
@@ -143,9 +196,81 @@ func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", etag(u.Version))
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PatchUser applies a JSON Merge Patch on the profile of a user, the members
+// left out keep their stored value and the password is kept
+func (h *userHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	var req UserRequest
+
+	doc, ok := decodeMergePatch(w, r, &req)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	if !authorizeUser(w, r, id) {
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	p := req.toPatch(doc)
+
+	// patrons can't change their own category
+	if claims, _ := claimsFromContext(r.Context()); !claims.IsLibrarian() {
+		p.Category = nil
+	}
+
+	ctx := r.Context()
+	u, err := h.UserUsecase.PatchUser(ctx, id, version, p)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrUserNotFound:
+				http.Error(w, userNotFound, http.StatusNotFound)
+			case repoErr.ErrVersionConflict:
+				http.Error(w, versionConflict, http.StatusPreconditionFailed)
+			case repoErr.ErrAlreadyExists:
+				http.Error(w, alreadyExists, http.StatusBadRequest)
+			case entity.ErrInvalidCategory:
+				http.Error(w, invalidCategory, http.StatusBadRequest)
+			case entity.ErrEmptyUserField, entity.ErrFieldWithSpaces, entity.ErrInvalidEmail:
+				http.Error(w, invalidProfile, http.StatusBadRequest)
+			default:
+				http.Error(w, updateUser, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(u.Version))
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(newUserResponse(u)); err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, updateUser, http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -154,8 +279,17 @@ func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !authorizeUser(w, r, id) {
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
 	ctx := r.Context()
-	err = h.UserUsecase.DeleteUser(ctx, id)
+	err = h.UserUsecase.DeleteUser(ctx, id, version)
 	if err != nil {
 		log.Error().Msg(err.Error())
 
@@ -165,6 +299,8 @@ func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		default:
 			if err == repoErr.ErrUserNotFound {
 				http.Error(w, userNotFound, http.StatusNotFound)
+			} else if err == repoErr.ErrVersionConflict {
+				http.Error(w, versionConflict, http.StatusPreconditionFailed)
 			} else {
 				http.Error(w, deleteUser, http.StatusInternalServerError)
 			}
@@ -174,3 +310,100 @@ func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type RoleRequest struct {
+	Role entity.Role `json:"role"`
+}
+
+func (h *userHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	var req RoleRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.UserUsecase.ChangeRole(ctx, id, req.Role)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrUserNotFound:
+				http.Error(w, userNotFound, http.StatusNotFound)
+			case entity.ErrInvalidRole:
+				http.Error(w, invalidRole, http.StatusBadRequest)
+			default:
+				http.Error(w, changeRole, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type PasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (h *userHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	if !authorizeUser(w, r, id) {
+		return
+	}
+
+	var req PasswordRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Error().Msg(err.Error())
+		http.Error(w, invalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	err = h.UserUsecase.ChangePassword(ctx, id, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		log.Error().Msg(err.Error())
+
+		select {
+		case <-ctx.Done():
+			http.Error(w, timeout, http.StatusGatewayTimeout)
+		default:
+			switch err {
+			case repoErr.ErrUserNotFound:
+				http.Error(w, userNotFound, http.StatusNotFound)
+			case ucErr.ErrWrongPassword:
+				http.Error(w, wrongPassword, http.StatusForbidden)
+			case entity.ErrEmptyUserField, entity.ErrFieldWithSpaces, entity.ErrShortPassword, entity.ErrLongPassword:
+				http.Error(w, invalidPassword, http.StatusBadRequest)
+			default:
+				http.Error(w, changePassword, http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
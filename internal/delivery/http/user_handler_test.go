@@ -2,20 +2,23 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
-	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ucErr "github.com/LuigiAzevedo/public-library-v2/internal/domain/usecase"
 	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 func TestGetUser(t *testing.T) {
@@ -30,10 +33,17 @@ func TestGetUser(t *testing.T) {
 				uc.EXPECT().
 					GetUser(gomock.Any(), gomock.Eq(1)).
 					Times(1).
-					Return(&entity.User{}, nil)
+					Return(&entity.User{ID: 1, Username: "user123", Password: "$2a$10$hash", Version: 3}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+
+				var body map[string]any
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				assert.NotContains(t, body, "password")
+				assert.Contains(t, body, "created_at")
+				assert.Contains(t, body, "updated_at")
 			},
 		},
 		"Invalid URL Param": {
@@ -86,6 +96,7 @@ func TestGetUser(t *testing.T) {
 			url := fmt.Sprint("/v1/users/", tc.ID)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewUserHandler(router, uc)
@@ -96,8 +107,7 @@ func TestGetUser(t *testing.T) {
 }
 
 func TestCreateUser(t *testing.T) {
-	user := &entity.User{
-		ID:       1,
+	user := UserRequest{
 		Username: "user123",
 		Password: "password123",
 		Email:    "user123@example.com",
@@ -112,9 +122,9 @@ func TestCreateUser(t *testing.T) {
 			user: user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
-					CreateUser(gomock.Any(), gomock.Eq(user)).
+					CreateUser(gomock.Any(), gomock.Eq(user.toEntity())).
 					Times(1).
-					Return(user.ID, nil)
+					Return(1, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusCreated, recorder.Code)
@@ -143,6 +153,56 @@ func TestCreateUser(t *testing.T) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		"Invalid Category": {
+			user: user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidCategory)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Email": {
+			user: user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrInvalidEmail)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Equal(t, invalidProfile+"\n", recorder.Body.String())
+			},
+		},
+		"Short Password": {
+			user: user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrShortPassword)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Equal(t, invalidPassword+"\n", recorder.Body.String())
+			},
+		},
+		"Empty Field": {
+			user: user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(0, entity.ErrEmptyUserField)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		"Unexpected Error": {
 			user: user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
@@ -171,6 +231,7 @@ func TestCreateUser(t *testing.T) {
 
 			request, err := http.NewRequest(http.MethodPost, "/v1/users/", bytes.NewReader(data))
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewUserHandler(router, uc)
@@ -181,35 +242,44 @@ func TestCreateUser(t *testing.T) {
 }
 
 func TestUpdateUser(t *testing.T) {
-	user := &entity.User{
-		ID:       1,
+	user := UserRequest{
 		Username: "user123",
 		Password: "password123",
 		Email:    "user123@example.com",
 	}
+	updated := user.toEntity()
+	updated.ID = 1
+	updated.Version = 1
 
 	testCases := map[string]struct {
 		ID            any
+		ifMatch       string
 		user          any
 		buildStubs    func(uc *mock.MockUserUsecase)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		"OK": {
-			ID:   1,
-			user: user,
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(user)).
+					UpdateUser(gomock.Any(), gomock.Eq(updated)).
 					Times(1).
-					Return(nil)
+					DoAndReturn(func(_ context.Context, u *entity.User) error {
+						u.Version = 2
+						return nil
+					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusNoContent, recorder.Code)
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
 			},
 		},
 		"Invalid Body": {
-			ID:   1,
-			user: "invalid body",
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    "invalid body",
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Any()).
@@ -220,8 +290,9 @@ func TestUpdateUser(t *testing.T) {
 			},
 		},
 		"Invalid URL Param": {
-			ID:   "ID",
-			user: user,
+			ID:      "ID",
+			ifMatch: `"1"`,
+			user:    user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Any()).
@@ -232,8 +303,9 @@ func TestUpdateUser(t *testing.T) {
 			},
 		},
 		"Not Found": {
-			ID:   1,
-			user: user,
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Any()).
@@ -245,8 +317,9 @@ func TestUpdateUser(t *testing.T) {
 			},
 		},
 		"Duplicated": {
-			ID:   1,
-			user: user,
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Any()).
@@ -257,9 +330,64 @@ func TestUpdateUser(t *testing.T) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-		"Unexpected Error": {
+		"Invalid Profile": {
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrInvalidEmail)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Equal(t, invalidProfile+"\n", recorder.Body.String())
+			},
+		},
+		"Missing If-Match": {
 			ID:   1,
 			user: user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		"Invalid If-Match": {
+			ID:      1,
+			ifMatch: `W/"1"`,
+			user:    user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Version Conflict": {
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    user,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrVersionConflict)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:      1,
+			ifMatch: `"1"`,
+			user:    user,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Any()).
@@ -287,6 +415,171 @@ func TestUpdateUser(t *testing.T) {
 			url := fmt.Sprint("/v1/users/", tc.ID)
 			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+			request.Header.Set("If-Match", tc.ifMatch)
+
+			router := chi.NewRouter()
+			NewUserHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPatchUser(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		body          string
+		contentType   string
+		ifMatch       string
+		buildStubs    func(uc *mock.MockUserUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:          1,
+			body:        `{"email": "user123@library.com", "category": "staff", "password": "ignored"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Eq(1), gomock.Eq(1), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _, _ int, p *entity.UserPatch) (*entity.User, error) {
+						assert.Equal(t, &entity.UserPatch{Email: p.Email, Category: p.Category}, p)
+						assert.Equal(t, "user123@library.com", *p.Email)
+						assert.Equal(t, entity.CategoryStaff, *p.Category)
+						return &entity.User{ID: 1, Username: "user123", Email: "user123@library.com", Category: entity.CategoryStaff, Version: 2}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+
+				var body map[string]any
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				assert.Equal(t, "user123@library.com", body["email"])
+				assert.NotContains(t, body, "password")
+			},
+		},
+		"Unsupported Media Type": {
+			ID:          1,
+			body:        `{"email": "user123@library.com"}`,
+			contentType: "application/x-www-form-urlencoded",
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			ID:          1,
+			body:        `null`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Missing If-Match": {
+			ID:          1,
+			body:        `{"email": "user123@library.com"}`,
+			contentType: mergePatchType,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID:          1,
+			body:        `{"email": "user123@library.com"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Version Conflict": {
+			ID:          1,
+			body:        `{"email": "user123@library.com"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repoErr.ErrVersionConflict)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Invalid Profile": {
+			ID:          1,
+			body:        `{"email": null}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, entity.ErrEmptyUserField)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), invalidProfile)
+			},
+		},
+		"Unexpected Error": {
+			ID:          1,
+			body:        `{"email": "user123@library.com"}`,
+			contentType: mergePatchType,
+			ifMatch:     `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					PatchUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockUserUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprint("/v1/users/", tc.ID)
+			request, err := http.NewRequest(http.MethodPatch, url, strings.NewReader(tc.body))
+			assert.NoError(t, err)
+			request.Header.Set("Content-Type", tc.contentType)
+			request.Header.Set("If-Match", tc.ifMatch)
+			request = authenticated(request, librarian)
 
 			router := chi.NewRouter()
 			NewUserHandler(router, uc)
@@ -299,14 +592,16 @@ func TestUpdateUser(t *testing.T) {
 func TestDeleteUser(t *testing.T) {
 	testCases := map[string]struct {
 		ID            any
+		ifMatch       string
 		buildStubs    func(uc *mock.MockUserUsecase)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		"OK": {
-			ID: 1,
+			ID:      1,
+			ifMatch: `"1"`,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
-					DeleteUser(gomock.Any(), gomock.Eq(1)).
+					DeleteUser(gomock.Any(), gomock.Eq(1), gomock.Eq(1)).
 					Times(1).
 					Return(nil)
 			},
@@ -315,10 +610,11 @@ func TestDeleteUser(t *testing.T) {
 			},
 		},
 		"Invalid URL Param": {
-			ID: "invalid",
+			ID:      "invalid",
+			ifMatch: `"1"`,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
-					DeleteUser(gomock.Any(), gomock.Any()).
+					DeleteUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -326,10 +622,11 @@ func TestDeleteUser(t *testing.T) {
 			},
 		},
 		"Not Found": {
-			ID: 1,
+			ID:      1,
+			ifMatch: `"1"`,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
-					DeleteUser(gomock.Any(), gomock.Any()).
+					DeleteUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(repoErr.ErrUserNotFound)
 			},
@@ -337,11 +634,48 @@ func TestDeleteUser(t *testing.T) {
 				assert.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
-		"Unexpected Error": {
+		"Missing If-Match": {
 			ID: 1,
 			buildStubs: func(uc *mock.MockUserUsecase) {
 				uc.EXPECT().
-					DeleteUser(gomock.Any(), gomock.Any()).
+					DeleteUser(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		"Invalid If-Match": {
+			ID:      1,
+			ifMatch: `W/"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Version Conflict": {
+			ID:      1,
+			ifMatch: `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrVersionConflict)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:      1,
+			ifMatch: `"1"`,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(sql.ErrConnDone)
 			},
@@ -363,6 +697,232 @@ func TestDeleteUser(t *testing.T) {
 			url := fmt.Sprint("/v1/users/", tc.ID)
 			request, err := http.NewRequest(http.MethodDelete, url, nil)
 			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+			request.Header.Set("If-Match", tc.ifMatch)
+
+			router := chi.NewRouter()
+			NewUserHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestChangeRole(t *testing.T) {
+	testCases := map[string]struct {
+		ID            any
+		body          any
+		buildStubs    func(uc *mock.MockUserUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   2,
+			body: RoleRequest{Role: entity.RoleLibrarian},
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangeRole(gomock.Any(), gomock.Eq(2), gomock.Eq(entity.RoleLibrarian)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid URL Param": {
+			ID:   "ID",
+			body: RoleRequest{Role: entity.RoleLibrarian},
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangeRole(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Invalid Role": {
+			ID:   2,
+			body: RoleRequest{Role: "admin"},
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangeRole(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrInvalidRole)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID:   2,
+			body: RoleRequest{Role: entity.RoleLibrarian},
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangeRole(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:   2,
+			body: RoleRequest{Role: entity.RoleLibrarian},
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangeRole(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockUserUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/users/", tc.ID, "/role")
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			request = authenticated(request, librarian)
+
+			router := chi.NewRouter()
+			NewUserHandler(router, uc)
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	req := PasswordRequest{CurrentPassword: "password123", NewPassword: "password456"}
+
+	testCases := map[string]struct {
+		ID            any
+		body          any
+		buildStubs    func(uc *mock.MockUserUsecase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			ID:   1,
+			body: req,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Eq(1), gomock.Eq(req.CurrentPassword), gomock.Eq(req.NewPassword)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		"Invalid Body": {
+			ID:   1,
+			body: "invalid body",
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Other User": {
+			ID:   3,
+			body: req,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		"Wrong Password": {
+			ID:   1,
+			body: req,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ucErr.ErrWrongPassword)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		"Short Password": {
+			ID:   1,
+			body: PasswordRequest{CurrentPassword: "password123", NewPassword: "short"},
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(entity.ErrShortPassword)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Not Found": {
+			ID:   1,
+			body: req,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repoErr.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Unexpected Error": {
+			ID:   1,
+			body: req,
+			buildStubs: func(uc *mock.MockUserUsecase) {
+				uc.EXPECT().
+					ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockUserUsecase(ctrl)
+			tc.buildStubs(uc)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			url := fmt.Sprint("/v1/users/", tc.ID, "/password")
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+			// even librarians need the current password, resets go through the auth flow
+			request = authenticated(request, &entity.Claims{UserID: 1, Role: entity.RolePatron})
 
 			router := chi.NewRouter()
 			NewUserHandler(router, uc)
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+)
+
+// Claims identifies the user behind an authenticated request
+type Claims struct {
+	UserID    int
+	Username  string
+	Role      Role
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Token is a signed session token handed to a user after login
+type Token struct {
+	Value     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IsLibrarian reports whether the authenticated user is a librarian
+func (c *Claims) IsLibrarian() bool {
+	return c.Role == RoleLibrarian
+}
+
+// CanActFor reports whether the authenticated user can act on behalf of the user,
+// patrons can only act for themselves
+func (c *Claims) CanActFor(userID int) bool {
+	return c.IsLibrarian() || c.UserID == userID
+}
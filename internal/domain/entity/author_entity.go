@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type AuthorRole string
+
+// Roles of the authors of a book
+const (
+	// AuthorRoleAuthor wrote the book
+	AuthorRoleAuthor AuthorRole = "author"
+	// AuthorRoleEditor edited the book or the collection it belongs to
+	AuthorRoleEditor AuthorRole = "editor"
+	// AuthorRoleTranslator translated the book
+	AuthorRoleTranslator AuthorRole = "translator"
+)
+
+// Author is a person credited on books, shared by every book they worked on
+type Author struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Bio       string `json:"bio"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// BookAuthor credits an author on a book, the authors of a book keep the order they are credited in
+type BookAuthor struct {
+	AuthorID int        `json:"author_id"`
+	Name     string     `json:"name"`
+	Role     AuthorRole `json:"role"`
+}
+
+// NewAuthor creates a new author entity
+func NewAuthor(name, bio string) (*Author, error) {
+	a := &Author{
+		Name:      strings.TrimSpace(name),
+		Bio:       bio,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Time{},
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Validate validates the author entity.
+func (a *Author) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return ErrInvalidAuthor
+	}
+
+	return nil
+}
+
+// ValidateBookAuthors validates the authors of a book, an author can be
+// credited more than once only with different roles
+func ValidateBookAuthors(authors []BookAuthor) error {
+	credited := make(map[BookAuthor]bool, len(authors))
+	for _, a := range authors {
+		if a.AuthorID <= 0 || !a.Role.IsValid() {
+			return ErrInvalidBookAuthors
+		}
+
+		credit := BookAuthor{AuthorID: a.AuthorID, Role: a.Role}
+		if credited[credit] {
+			return ErrInvalidBookAuthors
+		}
+		credited[credit] = true
+	}
+
+	return nil
+}
+
+// Byline joins the names of the writers of the book in the order they are
+// credited, books without writers such as anthologies credit every author
+func Byline(authors []BookAuthor) string {
+	var names, all []string
+	for _, a := range authors {
+		if a.Role == AuthorRoleAuthor {
+			names = append(names, a.Name)
+		}
+		all = append(all, a.Name)
+	}
+
+	if len(names) == 0 {
+		names = all
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// IsValid reports whether the role is a known author role
+func (r AuthorRole) IsValid() bool {
+	switch r {
+	case AuthorRoleAuthor, AuthorRoleEditor, AuthorRoleTranslator:
+		return true
+	}
+
+	return false
+}
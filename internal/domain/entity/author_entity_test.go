@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthor(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		want error
+	}{
+		"OK": {
+			name: "Alex Edwards",
+			want: nil,
+		},
+		"Empty Name": {
+			name: "",
+			want: ErrInvalidAuthor,
+		},
+		"Blank Name": {
+			name: "  ",
+			want: ErrInvalidAuthor,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAuthor(tc.name, "")
+			assert.Equal(t, tc.want, err)
+		})
+	}
+}
+
+func TestValidateBookAuthors(t *testing.T) {
+	tests := map[string]struct {
+		authors []BookAuthor
+		want    error
+	}{
+		"OK": {
+			authors: []BookAuthor{{AuthorID: 1, Role: AuthorRoleAuthor}, {AuthorID: 2, Role: AuthorRoleTranslator}},
+			want:    nil,
+		},
+		"No Authors": {
+			authors: nil,
+			want:    nil,
+		},
+		"Author And Editor": {
+			authors: []BookAuthor{{AuthorID: 1, Role: AuthorRoleAuthor}, {AuthorID: 1, Role: AuthorRoleEditor}},
+			want:    nil,
+		},
+		"Credited Twice": {
+			authors: []BookAuthor{{AuthorID: 1, Role: AuthorRoleAuthor}, {AuthorID: 1, Role: AuthorRoleAuthor}},
+			want:    ErrInvalidBookAuthors,
+		},
+		"Invalid Role": {
+			authors: []BookAuthor{{AuthorID: 1, Role: "illustrator"}},
+			want:    ErrInvalidBookAuthors,
+		},
+		"Invalid Author ID": {
+			authors: []BookAuthor{{AuthorID: 0, Role: AuthorRoleAuthor}},
+			want:    ErrInvalidBookAuthors,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ValidateBookAuthors(tc.authors))
+		})
+	}
+}
+
+func TestByline(t *testing.T) {
+	tests := map[string]struct {
+		authors []BookAuthor
+		want    string
+	}{
+		"Co-authors": {
+			authors: []BookAuthor{
+				{Name: "Alan Donovan", Role: AuthorRoleAuthor},
+				{Name: "Brian Kernighan", Role: AuthorRoleAuthor},
+			},
+			want: "Alan Donovan, Brian Kernighan",
+		},
+		"Translated": {
+			authors: []BookAuthor{
+				{Name: "Machado de Assis", Role: AuthorRoleAuthor},
+				{Name: "Flora Thomson-DeVeaux", Role: AuthorRoleTranslator},
+			},
+			want: "Machado de Assis",
+		},
+		"Anthology": {
+			authors: []BookAuthor{{Name: "Ellen Datlow", Role: AuthorRoleEditor}},
+			want:    "Ellen Datlow",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Byline(tc.authors))
+		})
+	}
+}
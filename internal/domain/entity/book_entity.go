@@ -1,26 +1,55 @@
 package entity
 
 import (
+	"net/url"
+	"strings"
 	"time"
 )
 
 type Book struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title"`
-	Author    string `json:"author"`
-	Amount    int    `json:"amount"`
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	// Amount counts the available copies, a new book starts with Amount copies
+	Amount int `json:"amount"`
+	BookMetadata
+	// Authors credits the authors in order, Author is their byline
+	Authors []BookAuthor `json:"authors"`
+	// Subjects tags the book with genres and topics
+	Subjects  []Subject `json:"subjects"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// Version counts the updates of the book, an update of an older version fails
+	Version int
 }
 
-// NewBook creates a new book entity
-func NewBook(title, author string, amount int) (*Book, error) {
+// BookMetadata is the bibliographic data of a book, every field is optional
+// and zero values mean unknown
+type BookMetadata struct {
+	ISBN          string `json:"isbn"`
+	Publisher     string `json:"publisher"`
+	PublishedYear int    `json:"published_year"`
+	Language      string `json:"language"`
+	Pages         int    `json:"pages"`
+	Description   string `json:"description"`
+	CoverURL      string `json:"cover_url"`
+}
+
+// NewBook creates a new book entity with amount copies, the ISBN is stored without separators
+func NewBook(title, author string, amount int, meta BookMetadata) (*Book, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidBook
+	}
+
+	meta.ISBN = NormalizeISBN(meta.ISBN)
+
 	book := &Book{
-		Title:     title,
-		Author:    author,
-		Amount:    amount,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Time{},
+		Title:        title,
+		Author:       author,
+		Amount:       amount,
+		BookMetadata: meta,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Time{},
 	}
 
 	if err := book.Validate(); err != nil {
@@ -32,9 +61,201 @@ func NewBook(title, author string, amount int) (*Book, error) {
 
 // Validate validates the book entity.
 func (book *Book) Validate() error {
-	if book.Title == "" || book.Author == "" || book.Amount <= 0 {
+	if book.Title == "" || book.Author == "" || book.Amount < 0 {
 		return ErrInvalidBook
 	}
 
+	if err := ValidateBookAuthors(book.Authors); err != nil {
+		return err
+	}
+
+	if err := ValidateBookSubjects(book.Subjects); err != nil {
+		return err
+	}
+
+	return book.BookMetadata.Validate()
+}
+
+// Validate validates the bibliographic data, empty fields are not checked.
+func (meta *BookMetadata) Validate() error {
+	if meta.ISBN != "" && !ValidISBN(meta.ISBN) {
+		return ErrInvalidISBN
+	}
+
+	// books are catalogued up to a year before being published
+	if meta.PublishedYear < 0 || meta.PublishedYear > time.Now().Year()+1 {
+		return ErrInvalidPublishedYear
+	}
+
+	if meta.Language != "" && !validLanguage(meta.Language) {
+		return ErrInvalidLanguage
+	}
+
+	if meta.Pages < 0 {
+		return ErrInvalidPages
+	}
+
+	if meta.CoverURL != "" {
+		u, err := url.Parse(meta.CoverURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidCoverURL
+		}
+	}
+
 	return nil
 }
+
+// BookPatch is a partial update of a book, nil fields keep the stored value
+// and the others replace it, zero values included
+type BookPatch struct {
+	Title         *string
+	Author        *string
+	Amount        *int
+	Authors       *[]BookAuthor
+	Subjects      *[]Subject
+	ISBN          *string
+	Publisher     *string
+	PublishedYear *int
+	Language      *string
+	Pages         *int
+	Description   *string
+	CoverURL      *string
+}
+
+// Apply sets the fields of the patch on the book, the amount is left to the
+// copies and an amount other than the stored one is rejected by the use case
+func (p *BookPatch) Apply(book *Book) {
+	if p.Title != nil {
+		book.Title = *p.Title
+	}
+	if p.Author != nil {
+		book.Author = *p.Author
+	}
+	if p.Authors != nil {
+		book.Authors = append([]BookAuthor{}, *p.Authors...)
+	}
+	if p.Subjects != nil {
+		book.Subjects = append([]Subject{}, *p.Subjects...)
+	}
+	if p.ISBN != nil {
+		book.ISBN = *p.ISBN
+	}
+	if p.Publisher != nil {
+		book.Publisher = *p.Publisher
+	}
+	if p.PublishedYear != nil {
+		book.PublishedYear = *p.PublishedYear
+	}
+	if p.Language != nil {
+		book.Language = *p.Language
+	}
+	if p.Pages != nil {
+		book.Pages = *p.Pages
+	}
+	if p.Description != nil {
+		book.Description = *p.Description
+	}
+	if p.CoverURL != nil {
+		book.CoverURL = *p.CoverURL
+	}
+}
+
+// NormalizeISBN removes the hyphens and spaces of an ISBN and uppercases the X check digit,
+// a valid ISBN-10 becomes its ISBN-13 so a book has a single ISBN whichever form is sent
+func NormalizeISBN(isbn string) string {
+	isbn = strings.NewReplacer("-", "", " ", "").Replace(isbn)
+	isbn = strings.ToUpper(isbn)
+
+	if len(isbn) == 10 && validISBN10(isbn) {
+		isbn = "978" + isbn[:9]
+		isbn += string(isbn13CheckDigit(isbn))
+	}
+
+	return isbn
+}
+
+// ValidISBN reports whether a normalized ISBN-10 or ISBN-13 has a valid check digit
+func ValidISBN(isbn string) bool {
+	switch len(isbn) {
+	case 10:
+		return validISBN10(isbn)
+	case 13:
+		return validISBN13(isbn)
+	}
+
+	return false
+}
+
+// validISBN10 checks the weighted sum of the digits is a multiple of 11,
+// the check digit X stands for 10
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i, c := range isbn {
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case c == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+
+		sum += (10 - i) * digit
+	}
+
+	return sum%11 == 0
+}
+
+// validISBN13 checks the digits weighted alternately by 1 and 3 sum to a multiple of 10
+func validISBN13(isbn string) bool {
+	if !strings.HasPrefix(isbn, "978") && !strings.HasPrefix(isbn, "979") {
+		return false
+	}
+
+	sum := 0
+	for i, c := range isbn {
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += weight * int(c-'0')
+	}
+
+	return sum%10 == 0
+}
+
+// isbn13CheckDigit computes the check digit following the first 12 digits of an ISBN-13
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i, c := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += weight * int(c-'0')
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+// validLanguage reports whether the language is a lowercase ISO 639-1 or 639-2 code
+func validLanguage(language string) bool {
+	if len(language) != 2 && len(language) != 3 {
+		return false
+	}
+
+	for _, c := range language {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -11,13 +12,23 @@ func TestNewBook(t *testing.T) {
 		title  string
 		author string
 		amount int
+		meta   BookMetadata
 		want   error
 	}{
 		"OK": {
 			title:  "Let's Go Further!",
 			author: "Alex Edwards",
 			amount: 5,
-			want:   nil,
+			meta: BookMetadata{
+				ISBN:          "978-0-306-40615-7",
+				Publisher:     "Alex Edwards",
+				PublishedYear: 2021,
+				Language:      "en",
+				Pages:         583,
+				Description:   "Advanced patterns for building APIs and web applications in Go",
+				CoverURL:      "https://example.com/covers/lets-go-further.jpg",
+			},
+			want: nil,
 		},
 		"Empty Fields": {
 			title:  "",
@@ -31,10 +42,17 @@ func TestNewBook(t *testing.T) {
 			amount: 0,
 			want:   ErrInvalidBook,
 		},
+		"Invalid ISBN": {
+			title:  "Let's Go Further!",
+			author: "Alex Edwards",
+			amount: 5,
+			meta:   BookMetadata{ISBN: "978-0-306-40615-8"},
+			want:   ErrInvalidISBN,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			b, err := NewBook(tc.title, tc.author, tc.amount)
+			b, err := NewBook(tc.title, tc.author, tc.amount, tc.meta)
 
 			assert.Equal(t, tc.want, err)
 
@@ -42,7 +60,149 @@ func TestNewBook(t *testing.T) {
 				assert.Equal(t, tc.title, b.Title)
 				assert.Equal(t, tc.author, b.Author)
 				assert.Equal(t, tc.amount, b.Amount)
+				assert.Equal(t, "9780306406157", b.ISBN)
+				assert.Equal(t, tc.meta.Description, b.Description)
 			}
 		})
 	}
 }
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := map[string]struct {
+		isbn string
+		want string
+	}{
+		"ISBN-13 With Hyphens":  {isbn: "978-0-306-40615-7", want: "9780306406157"},
+		"ISBN-10":               {isbn: "0-306-40615-2", want: "9780306406157"},
+		"ISBN-10 X Check Digit": {isbn: "0-8044-2957-x", want: "9780804429573"},
+		"Invalid ISBN-10":       {isbn: "0306406153", want: "0306406153"},
+		"Empty":                 {isbn: "", want: ""},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, NormalizeISBN(tc.isbn))
+		})
+	}
+}
+
+func TestValidISBN(t *testing.T) {
+	tests := map[string]struct {
+		isbn string
+		want bool
+	}{
+		"ISBN-10":                 {isbn: "0306406152", want: true},
+		"ISBN-10 X Check Digit":   {isbn: "080442957X", want: true},
+		"ISBN-10 Wrong Check":     {isbn: "0306406153", want: false},
+		"ISBN-10 X Not Last":      {isbn: "03064X6152", want: false},
+		"ISBN-13":                 {isbn: "9780306406157", want: true},
+		"ISBN-13 979 Prefix":      {isbn: "9791090636071", want: true},
+		"ISBN-13 Wrong Check":     {isbn: "9780306406158", want: false},
+		"ISBN-13 Wrong Prefix":    {isbn: "1234567890128", want: false},
+		"ISBN-13 With Letters":    {isbn: "978030640615X", want: false},
+		"Wrong Length":            {isbn: "12345", want: false},
+		"Not Normalized":          {isbn: "0-306-40615-2", want: false},
+		"Normalized With Hyphens": {isbn: NormalizeISBN("0-8044-2957-x"), want: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ValidISBN(tc.isbn))
+		})
+	}
+}
+
+func TestBookMetadataValidate(t *testing.T) {
+	tests := map[string]struct {
+		meta BookMetadata
+		want error
+	}{
+		"Empty": {
+			meta: BookMetadata{},
+			want: nil,
+		},
+		"Next Year": {
+			meta: BookMetadata{PublishedYear: time.Now().Year() + 1},
+			want: nil,
+		},
+		"Far Future Year": {
+			meta: BookMetadata{PublishedYear: time.Now().Year() + 2},
+			want: ErrInvalidPublishedYear,
+		},
+		"Negative Year": {
+			meta: BookMetadata{PublishedYear: -1},
+			want: ErrInvalidPublishedYear,
+		},
+		"Three Letter Language": {
+			meta: BookMetadata{Language: "por"},
+			want: nil,
+		},
+		"Language Name": {
+			meta: BookMetadata{Language: "English"},
+			want: ErrInvalidLanguage,
+		},
+		"Negative Pages": {
+			meta: BookMetadata{Pages: -10},
+			want: ErrInvalidPages,
+		},
+		"Relative Cover URL": {
+			meta: BookMetadata{CoverURL: "/covers/1.jpg"},
+			want: ErrInvalidCoverURL,
+		},
+		"Cover URL Scheme": {
+			meta: BookMetadata{CoverURL: "javascript:alert(1)"},
+			want: ErrInvalidCoverURL,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.meta.Validate())
+		})
+	}
+}
+
+func TestBookPatchApply(t *testing.T) {
+	newBook := func() *Book {
+		return &Book{
+			ID:           1,
+			Title:        "Let's Go",
+			Author:       "Alex Edwards",
+			Amount:       2,
+			BookMetadata: BookMetadata{ISBN: "9781617299537", Pages: 300},
+			Authors:      []BookAuthor{{AuthorID: 1, Name: "Alex Edwards", Role: AuthorRoleAuthor}},
+			Subjects:     []Subject{{ID: 2}},
+		}
+	}
+
+	t.Run("Provided Fields", func(t *testing.T) {
+		title := "Let's Go Further!"
+		pages := 0
+		amount := 5
+
+		b := newBook()
+		p := &BookPatch{Title: &title, Pages: &pages, Amount: &amount}
+		p.Apply(b)
+
+		want := newBook()
+		want.Title = title
+		want.Pages = 0
+		assert.Equal(t, want, b, "the other fields and the amount are kept")
+	})
+	t.Run("Cleared Lists", func(t *testing.T) {
+		authors := []BookAuthor(nil)
+		subjects := []Subject{}
+
+		b := newBook()
+		p := &BookPatch{Authors: &authors, Subjects: &subjects}
+		p.Apply(b)
+
+		assert.NotNil(t, b.Authors)
+		assert.Empty(t, b.Authors)
+		assert.NotNil(t, b.Subjects)
+		assert.Empty(t, b.Subjects)
+	})
+	t.Run("Empty Patch", func(t *testing.T) {
+		b := newBook()
+		(&BookPatch{}).Apply(b)
+
+		assert.Equal(t, newBook(), b)
+	})
+}
@@ -0,0 +1,323 @@
+package entity
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+type BookExportFormat string
+
+// Formats of a catalog export
+const (
+	// BookExportCSV is a CSV file with the import columns and the book id
+	BookExportCSV BookExportFormat = "csv"
+	// BookExportJSONL is a JSON Lines file with a book object per line
+	BookExportJSONL BookExportFormat = "jsonl"
+	// BookExportMARCXML is a MARC 21 XML collection with a record per book
+	BookExportMARCXML BookExportFormat = "marcxml"
+)
+
+// marcXMLNamespace is the namespace of MARC 21 XML collections
+const marcXMLNamespace = "http://www.loc.gov/MARC21/slim"
+
+// BookExporter writes books in an export format one at a time, Flush sends
+// the books written so far and Close ends the export
+type BookExporter interface {
+	Write(b *Book) error
+	Flush() error
+	Close() error
+}
+
+// IsValid reports whether the format is a known export format
+func (f BookExportFormat) IsValid() bool {
+	switch f {
+	case BookExportCSV, BookExportJSONL, BookExportMARCXML:
+		return true
+	}
+
+	return false
+}
+
+// NewBookExporter creates an exporter writing books to w in the format, the
+// books are buffered and reach w when the buffer fills up or on Flush
+func NewBookExporter(w io.Writer, format BookExportFormat) (BookExporter, error) {
+	switch format {
+	case BookExportCSV:
+		return newBookCSVExporter(w)
+	case BookExportJSONL:
+		return newBookJSONLExporter(w), nil
+	case BookExportMARCXML:
+		return newBookMARCXMLExporter(w)
+	}
+
+	return nil, ErrInvalidBookExport
+}
+
+// bookCSVColumns are the columns of a CSV export, the import reads the same
+// columns and skips the id
+var bookCSVColumns = []string{"id", "title", "author", "amount", "isbn", "publisher", "published_year", "language", "pages", "description", "cover_url"}
+
+type bookCSVExporter struct {
+	w *csv.Writer
+}
+
+func newBookCSVExporter(w io.Writer) (*bookCSVExporter, error) {
+	e := &bookCSVExporter{w: csv.NewWriter(w)}
+	if err := e.w.Write(bookCSVColumns); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *bookCSVExporter) Write(b *Book) error {
+	return e.w.Write([]string{
+		strconv.Itoa(b.ID),
+		b.Title,
+		b.Author,
+		strconv.Itoa(b.Amount),
+		b.ISBN,
+		b.Publisher,
+		optionalNumber(b.PublishedYear),
+		b.Language,
+		optionalNumber(b.Pages),
+		b.Description,
+		b.CoverURL,
+	})
+}
+
+func (e *bookCSVExporter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *bookCSVExporter) Close() error {
+	return e.Flush()
+}
+
+// optionalNumber leaves unknown numbers empty
+func optionalNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
+
+// exportedBook is a book of a JSON Lines export
+type exportedBook struct {
+	ID            int          `json:"id"`
+	Title         string       `json:"title"`
+	Author        string       `json:"author"`
+	Amount        int          `json:"amount"`
+	ISBN          string       `json:"isbn,omitempty"`
+	Publisher     string       `json:"publisher,omitempty"`
+	PublishedYear int          `json:"published_year,omitempty"`
+	Language      string       `json:"language,omitempty"`
+	Pages         int          `json:"pages,omitempty"`
+	Description   string       `json:"description,omitempty"`
+	CoverURL      string       `json:"cover_url,omitempty"`
+	Authors       []BookAuthor `json:"authors"`
+	Subjects      []string     `json:"subjects"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     *time.Time   `json:"updated_at,omitempty"`
+}
+
+type bookJSONLExporter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newBookJSONLExporter(w io.Writer) *bookJSONLExporter {
+	buf := bufio.NewWriter(w)
+	return &bookJSONLExporter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (e *bookJSONLExporter) Write(b *Book) error {
+	book := exportedBook{
+		ID:            b.ID,
+		Title:         b.Title,
+		Author:        b.Author,
+		Amount:        b.Amount,
+		ISBN:          b.ISBN,
+		Publisher:     b.Publisher,
+		PublishedYear: b.PublishedYear,
+		Language:      b.Language,
+		Pages:         b.Pages,
+		Description:   b.Description,
+		CoverURL:      b.CoverURL,
+		Authors:       b.Authors,
+		Subjects:      make([]string, 0, len(b.Subjects)),
+		CreatedAt:     b.CreatedAt,
+	}
+	if book.Authors == nil {
+		book.Authors = []BookAuthor{}
+	}
+	for _, s := range b.Subjects {
+		book.Subjects = append(book.Subjects, s.Name)
+	}
+	if !b.UpdatedAt.IsZero() {
+		book.UpdatedAt = &b.UpdatedAt
+	}
+
+	// the encoder ends every book with a newline
+	return e.enc.Encode(book)
+}
+
+func (e *bookJSONLExporter) Flush() error {
+	return e.buf.Flush()
+}
+
+func (e *bookJSONLExporter) Close() error {
+	return e.Flush()
+}
+
+// marcRecord is a MARC 21 bibliographic record
+type marcRecord struct {
+	XMLName       xml.Name           `xml:"record"`
+	Leader        string             `xml:"leader"`
+	ControlFields []marcControlField `xml:"controlfield"`
+	DataFields    []marcDataField    `xml:"datafield"`
+}
+
+type marcControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcDataField struct {
+	Tag       string         `xml:"tag,attr"`
+	Ind1      string         `xml:"ind1,attr"`
+	Ind2      string         `xml:"ind2,attr"`
+	Subfields []marcSubfield `xml:"subfield"`
+}
+
+type marcSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+type bookMARCXMLExporter struct {
+	buf *bufio.Writer
+	enc *xml.Encoder
+}
+
+func newBookMARCXMLExporter(w io.Writer) (*bookMARCXMLExporter, error) {
+	e := &bookMARCXMLExporter{buf: bufio.NewWriter(w)}
+	e.enc = xml.NewEncoder(e.buf)
+
+	if _, err := e.buf.WriteString(xml.Header); err != nil {
+		return nil, err
+	}
+	err := e.enc.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: marcXMLNamespace}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *bookMARCXMLExporter) Write(b *Book) error {
+	return e.enc.Encode(newMARCRecord(b))
+}
+
+func (e *bookMARCXMLExporter) Flush() error {
+	if err := e.enc.Flush(); err != nil {
+		return err
+	}
+
+	return e.buf.Flush()
+}
+
+func (e *bookMARCXMLExporter) Close() error {
+	if err := e.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+
+	return e.Flush()
+}
+
+// newMARCRecord maps a book to a MARC 21 record, the first author credited as
+// author is the main entry (100) and the other credits are added entries (700).
+// Unknown fields are left out of the record
+func newMARCRecord(b *Book) marcRecord {
+	record := marcRecord{
+		// new record of a language material monograph in Unicode
+		Leader: "00000nam a2200000 a 4500",
+		ControlFields: []marcControlField{
+			{Tag: "001", Value: strconv.Itoa(b.ID)},
+		},
+	}
+
+	latest := b.CreatedAt
+	if !b.UpdatedAt.IsZero() {
+		latest = b.UpdatedAt
+	}
+	if !latest.IsZero() {
+		record.ControlFields = append(record.ControlFields, marcControlField{Tag: "005", Value: latest.UTC().Format("20060102150405.0")})
+	}
+
+	field := func(tag, ind1, ind2 string, subfields ...marcSubfield) {
+		var kept []marcSubfield
+		for _, s := range subfields {
+			if s.Value != "" {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) > 0 {
+			record.DataFields = append(record.DataFields, marcDataField{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: kept})
+		}
+	}
+
+	field("020", " ", " ", marcSubfield{Code: "a", Value: b.ISBN})
+	field("041", " ", " ", marcSubfield{Code: "a", Value: b.Language})
+
+	mainEntry := -1
+	for i, a := range b.Authors {
+		if a.Role == AuthorRoleAuthor {
+			mainEntry = i
+			break
+		}
+	}
+	if mainEntry >= 0 {
+		a := b.Authors[mainEntry]
+		field("100", "1", " ", marcSubfield{Code: "a", Value: a.Name}, marcSubfield{Code: "e", Value: string(a.Role)})
+	}
+
+	// the title is an added entry when there is no main entry
+	titleInd1 := "0"
+	if mainEntry >= 0 {
+		titleInd1 = "1"
+	}
+	field("245", titleInd1, "0", marcSubfield{Code: "a", Value: b.Title}, marcSubfield{Code: "c", Value: b.Author})
+	field("264", " ", "1", marcSubfield{Code: "b", Value: b.Publisher}, marcSubfield{Code: "c", Value: optionalNumber(b.PublishedYear)})
+	if b.Pages > 0 {
+		field("300", " ", " ", marcSubfield{Code: "a", Value: strconv.Itoa(b.Pages) + " pages"})
+	}
+	field("520", " ", " ", marcSubfield{Code: "a", Value: b.Description})
+
+	// local subjects, the thesaurus is not a standard one
+	for _, s := range b.Subjects {
+		field("650", " ", "4", marcSubfield{Code: "a", Value: s.Name})
+	}
+
+	for i, a := range b.Authors {
+		if i != mainEntry {
+			field("700", "1", " ", marcSubfield{Code: "a", Value: a.Name}, marcSubfield{Code: "e", Value: string(a.Role)})
+		}
+	}
+
+	if b.CoverURL != "" {
+		field("856", "4", "2", marcSubfield{Code: "3", Value: "Cover image"}, marcSubfield{Code: "u", Value: b.CoverURL})
+	}
+
+	return record
+}
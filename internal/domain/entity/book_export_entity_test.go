@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var exportedBooks = []*Book{
+	{
+		ID:     1,
+		Title:  "100 Go Mistakes and How to Avoid Them",
+		Author: "Teiva Harsanyi",
+		Amount: 3,
+		BookMetadata: BookMetadata{
+			ISBN:          "9781617299537",
+			Publisher:     "Manning",
+			PublishedYear: 2022,
+			Language:      "en",
+			Pages:         384,
+		},
+		Authors: []BookAuthor{
+			{AuthorID: 2, Name: "Jane Doe", Role: AuthorRoleEditor},
+			{AuthorID: 1, Name: "Teiva Harsanyi", Role: AuthorRoleAuthor},
+		},
+		Subjects:  []Subject{{ID: 2, Name: "Programming"}},
+		CreatedAt: time.Date(2023, 5, 1, 12, 30, 0, 0, time.UTC),
+	},
+	{
+		ID:        2,
+		Title:     "Let's Go",
+		Author:    "Alex Edwards",
+		CreatedAt: time.Date(2023, 5, 2, 8, 0, 0, 0, time.UTC),
+	},
+}
+
+func export(t *testing.T, format BookExportFormat) string {
+	var buf bytes.Buffer
+
+	exporter, err := NewBookExporter(&buf, format)
+	assert.NoError(t, err)
+	for _, b := range exportedBooks {
+		assert.NoError(t, exporter.Write(b))
+	}
+	assert.NoError(t, exporter.Close())
+
+	return buf.String()
+}
+
+func TestExportBooksCSV(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(export(t, BookExportCSV))).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, bookCSVColumns, records[0])
+	assert.Equal(t, []string{"1", "100 Go Mistakes and How to Avoid Them", "Teiva Harsanyi", "3", "9781617299537", "Manning", "2022", "en", "384", "", ""}, records[1])
+	assert.Equal(t, []string{"2", "Let's Go", "Alex Edwards", "0", "", "", "", "", "", "", ""}, records[2], "unknown numbers are left empty")
+}
+
+func TestExportBooksJSONL(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(export(t, BookExportJSONL)), "\n")
+	assert.Len(t, lines, 2)
+
+	var book map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &book))
+	assert.Equal(t, "9781617299537", book["isbn"])
+	assert.Equal(t, []any{"Programming"}, book["subjects"])
+	assert.Len(t, book["authors"], 2)
+	assert.NotContains(t, book, "updated_at")
+
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &book))
+	assert.Equal(t, []any{}, book["authors"])
+}
+
+func TestExportBooksMARCXML(t *testing.T) {
+	out := export(t, BookExportMARCXML)
+	assert.True(t, strings.HasPrefix(out, xml.Header))
+
+	var collection struct {
+		XMLName xml.Name     `xml:"http://www.loc.gov/MARC21/slim collection"`
+		Records []marcRecord `xml:"record"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(out), &collection))
+	assert.Len(t, collection.Records, 2)
+
+	tags := func(r marcRecord) []string {
+		var tags []string
+		for _, f := range r.DataFields {
+			tags = append(tags, f.Tag)
+		}
+		return tags
+	}
+
+	record := collection.Records[0]
+	assert.Len(t, record.Leader, 24)
+	assert.Equal(t, []marcControlField{{Tag: "001", Value: "1"}, {Tag: "005", Value: "20230501123000.0"}}, record.ControlFields)
+	assert.Equal(t, []string{"020", "041", "100", "245", "264", "300", "650", "700"}, tags(record))
+	assert.Equal(t, []marcSubfield{{Code: "a", Value: "Teiva Harsanyi"}, {Code: "e", Value: "author"}}, record.DataFields[2].Subfields, "the first author is the main entry")
+	assert.Equal(t, "1", record.DataFields[3].Ind1)
+	assert.Equal(t, []marcSubfield{{Code: "a", Value: "Jane Doe"}, {Code: "e", Value: "editor"}}, record.DataFields[7].Subfields)
+
+	record = collection.Records[1]
+	assert.Equal(t, []string{"245"}, tags(record), "unknown fields are left out")
+	assert.Equal(t, "0", record.DataFields[0].Ind1)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	formats := map[BookExportFormat]BookImportFormat{
+		BookExportCSV:   BookImportCSV,
+		BookExportJSONL: BookImportJSONL,
+	}
+	for exportFormat, importFormat := range formats {
+		t.Run(string(exportFormat), func(t *testing.T) {
+			rows, err := ParseBookImport(strings.NewReader(export(t, exportFormat)), importFormat)
+			assert.NoError(t, err)
+			assert.Len(t, rows, len(exportedBooks))
+
+			for i, row := range rows {
+				b := exportedBooks[i]
+				assert.Empty(t, row.Status, "the exported fields the import skips don't reject the row")
+				assert.Equal(t, b.Title, row.Book.Title)
+				assert.Equal(t, b.Author, row.Book.Author)
+				assert.Equal(t, b.Amount, row.Book.Amount)
+				assert.Equal(t, b.BookMetadata, row.Book.BookMetadata)
+				assert.Zero(t, row.Book.ID, "the catalog sets the id")
+			}
+		})
+	}
+}
+
+func TestNewBookExporter(t *testing.T) {
+	_, err := NewBookExporter(&bytes.Buffer{}, "marc")
+	assert.Equal(t, ErrInvalidBookExport, err)
+}
@@ -0,0 +1,338 @@
+package entity
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxBookImportRows is the largest number of rows of an import, larger
+// catalogs are imported in several files
+const MaxBookImportRows = 5000
+
+type BookImportFormat string
+
+// Formats of a book import
+const (
+	// BookImportCSV is a CSV file with a header row naming the columns
+	BookImportCSV BookImportFormat = "csv"
+	// BookImportJSONL is a JSON Lines file with a book object per line
+	BookImportJSONL BookImportFormat = "jsonl"
+)
+
+type BookImportStatus string
+
+// Outcomes of an imported row
+const (
+	// BookImportCreated rows created a new book
+	BookImportCreated BookImportStatus = "created"
+	// BookImportUpdated rows updated the book with the same ISBN
+	BookImportUpdated BookImportStatus = "updated"
+	// BookImportRejected rows were invalid and left the catalog untouched
+	BookImportRejected BookImportStatus = "rejected"
+)
+
+// BookImportRow is a row of an import, rows are numbered by the line they
+// start on and rejected rows have the error they were rejected for
+type BookImportRow struct {
+	Line   int
+	Status BookImportStatus
+	Err    error
+	Book   *Book
+	// Fields holds the fields the row supplies, a row updating a book only
+	// writes them and a row without an amount can't create a book
+	Fields *BookPatch
+}
+
+// BookImportReport tells what happened to every row of an import
+type BookImportReport struct {
+	Created  int
+	Updated  int
+	Rejected int
+	Rows     []BookImportRow
+}
+
+// bookImportFields are the columns of a CSV import and the fields of a JSON
+// Lines import, the fields missing from the row are nil
+type bookImportFields struct {
+	Title         *string `json:"title"`
+	Author        *string `json:"author"`
+	Amount        *int    `json:"amount"`
+	ISBN          *string `json:"isbn"`
+	Publisher     *string `json:"publisher"`
+	PublishedYear *int    `json:"published_year"`
+	Language      *string `json:"language"`
+	Pages         *int    `json:"pages"`
+	Description   *string `json:"description"`
+	CoverURL      *string `json:"cover_url"`
+}
+
+// exportOnlyBookFields are the fields of a JSON Lines export the import skips,
+// the catalog sets them so an exported catalog imports back as it is
+type exportOnlyBookFields struct {
+	ID        json.RawMessage `json:"id"`
+	Authors   json.RawMessage `json:"authors"`
+	Subjects  json.RawMessage `json:"subjects"`
+	CreatedAt json.RawMessage `json:"created_at"`
+	UpdatedAt json.RawMessage `json:"updated_at"`
+}
+
+// IsValid reports whether the format is a known import format
+func (f BookImportFormat) IsValid() bool {
+	switch f {
+	case BookImportCSV, BookImportJSONL:
+		return true
+	}
+
+	return false
+}
+
+// ParseBookImport reads the rows of an import, the books of the rows are not
+// validated yet. Rows that can't be read are rejected, a file that can't be
+// read at all returns ErrInvalidBookImport
+func ParseBookImport(r io.Reader, format BookImportFormat) ([]BookImportRow, error) {
+	switch format {
+	case BookImportCSV:
+		return parseBookCSV(r)
+	case BookImportJSONL:
+		return parseBookJSONL(r)
+	}
+
+	return nil, ErrInvalidBookImport
+}
+
+// parseBookCSV reads a CSV import, the header names the columns in any order
+// and only the title column is required
+func parseBookCSV(r io.Reader) ([]BookImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBookImport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if exportOnlyBookColumn(name) {
+			continue
+		}
+		if !knownBookImportColumn(name) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidBookImport, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: missing title column", ErrInvalidBookImport)
+	}
+
+	var rows []BookImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBookImport, err)
+		}
+		if len(rows) == MaxBookImportRows {
+			return nil, ErrBookImportTooLarge
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := BookImportRow{Line: line}
+
+		if len(record) != len(header) {
+			row.Reject(fmt.Errorf("the row has %d fields and the header %d", len(record), len(header)))
+			rows = append(rows, row)
+			continue
+		}
+
+		fields, err := csvBookFields(record, columns)
+		if err != nil {
+			row.Reject(err)
+		} else {
+			row.Book, row.Fields = fields.toBook()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvBookFields reads the fields of a CSV record, the fields of missing
+// columns are left nil and so is an empty amount
+func csvBookFields(record []string, columns map[string]int) (bookImportFields, error) {
+	var f bookImportFields
+
+	value := func(column string) *string {
+		if i, ok := columns[column]; ok {
+			v := strings.TrimSpace(record[i])
+			return &v
+		}
+		return nil
+	}
+	number := func(column string) (*int, error) {
+		v := value(column)
+		if v == nil {
+			return nil, nil
+		}
+		n := 0
+		if *v != "" {
+			var err error
+			if n, err = strconv.Atoi(*v); err != nil {
+				return nil, fmt.Errorf("%s must be an integer", column)
+			}
+		}
+		return &n, nil
+	}
+
+	var err error
+	f.Title = value("title")
+	f.Author = value("author")
+	f.ISBN = value("isbn")
+	f.Publisher = value("publisher")
+	f.Language = value("language")
+	f.Description = value("description")
+	f.CoverURL = value("cover_url")
+
+	if v := value("amount"); v != nil && *v != "" {
+		if f.Amount, err = number("amount"); err != nil {
+			return f, err
+		}
+	}
+	if f.PublishedYear, err = number("published_year"); err != nil {
+		return f, err
+	}
+	if f.Pages, err = number("pages"); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// parseBookJSONL reads a JSON Lines import, blank lines are skipped
+func parseBookJSONL(r io.Reader) ([]BookImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []BookImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == MaxBookImportRows {
+			return nil, ErrBookImportTooLarge
+		}
+
+		row := BookImportRow{Line: line}
+
+		var f struct {
+			bookImportFields
+			exportOnlyBookFields
+		}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&f); err != nil {
+			row.Reject(fmt.Errorf("invalid JSON: %w", err))
+		} else {
+			row.Book, row.Fields = f.toBook()
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line %d is too long", ErrInvalidBookImport, line+1)
+		}
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// toBook builds the book of a row and the patch of the fields it supplies,
+// the fields missing from the row are zero on the book
+func (f bookImportFields) toBook() (*Book, *BookPatch) {
+	p := &BookPatch{
+		Title:         trimmed(f.Title),
+		Author:        trimmed(f.Author),
+		Amount:        f.Amount,
+		ISBN:          f.ISBN,
+		Publisher:     f.Publisher,
+		PublishedYear: f.PublishedYear,
+		Language:      f.Language,
+		Pages:         f.Pages,
+		Description:   f.Description,
+		CoverURL:      f.CoverURL,
+	}
+
+	book := &Book{}
+	p.Apply(book)
+	if p.Amount != nil {
+		book.Amount = *p.Amount
+	}
+
+	return book, p
+}
+
+// trimmed returns the string without surrounding spaces, nil stays nil
+func trimmed(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	t := strings.TrimSpace(*s)
+	return &t
+}
+
+// knownBookImportColumn reports whether the column is a field of an imported book
+func knownBookImportColumn(name string) bool {
+	switch name {
+	case "title", "author", "amount", "isbn", "publisher", "published_year", "language", "pages", "description", "cover_url":
+		return true
+	}
+
+	return false
+}
+
+// exportOnlyBookColumn reports whether the column is written by the CSV export
+// and skipped by the import, the catalog sets it
+func exportOnlyBookColumn(name string) bool {
+	return name == "id"
+}
+
+// Reject rejects the row for the error
+func (row *BookImportRow) Reject(err error) {
+	row.Status = BookImportRejected
+	row.Err = err
+	row.Book = nil
+}
+
+// Add adds a row to the report and counts its outcome
+func (report *BookImportReport) Add(row BookImportRow) {
+	switch row.Status {
+	case BookImportCreated:
+		report.Created++
+	case BookImportUpdated:
+		report.Updated++
+	case BookImportRejected:
+		report.Rejected++
+	}
+
+	report.Rows = append(report.Rows, row)
+}
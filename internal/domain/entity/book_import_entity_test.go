@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBookImportCSV(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		file := "isbn,Title,author,amount,pages\n" +
+			"978-1-61729-953-7,100 Go Mistakes,Teiva Harsanyi,3,384\n" +
+			",Let's Go,Alex Edwards,,\n"
+
+		rows, err := ParseBookImport(strings.NewReader(file), BookImportCSV)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, "100 Go Mistakes", rows[0].Book.Title)
+		assert.Equal(t, "978-1-61729-953-7", rows[0].Book.ISBN)
+		assert.Equal(t, 3, rows[0].Book.Amount)
+		assert.Equal(t, 384, rows[0].Book.Pages)
+
+		assert.Equal(t, 3, rows[1].Line)
+		assert.Nil(t, rows[1].Fields.Amount, "an empty amount is not supplied")
+		assert.NotNil(t, rows[1].Fields.ISBN, "the empty cells of the other columns are")
+		assert.Nil(t, rows[1].Fields.Publisher, "missing columns are not supplied")
+	})
+	t.Run("Rejected Rows", func(t *testing.T) {
+		file := "title,author,amount\n" +
+			"Let's Go,Alex Edwards,two\n" +
+			"Let's Go,Alex Edwards\n" +
+			"\"Multi\nLine\",Alex Edwards,1\n"
+
+		rows, err := ParseBookImport(strings.NewReader(file), BookImportCSV)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+
+		assert.Equal(t, BookImportRejected, rows[0].Status)
+		assert.EqualError(t, rows[0].Err, "amount must be an integer")
+		assert.Nil(t, rows[0].Book)
+
+		assert.Equal(t, BookImportRejected, rows[1].Status)
+		assert.Equal(t, 3, rows[1].Line)
+
+		assert.Empty(t, rows[2].Status)
+		assert.Equal(t, 4, rows[2].Line)
+	})
+	t.Run("Unknown Column", func(t *testing.T) {
+		_, err := ParseBookImport(strings.NewReader("title,price\nLet's Go,10\n"), BookImportCSV)
+		assert.ErrorIs(t, err, ErrInvalidBookImport)
+	})
+	t.Run("Missing Title Column", func(t *testing.T) {
+		_, err := ParseBookImport(strings.NewReader("author\nAlex Edwards\n"), BookImportCSV)
+		assert.ErrorIs(t, err, ErrInvalidBookImport)
+	})
+	t.Run("Empty File", func(t *testing.T) {
+		_, err := ParseBookImport(strings.NewReader(""), BookImportCSV)
+		assert.ErrorIs(t, err, ErrInvalidBookImport)
+	})
+	t.Run("Too Many Rows", func(t *testing.T) {
+		file := "title\n" + strings.Repeat("Let's Go\n", MaxBookImportRows+1)
+
+		_, err := ParseBookImport(strings.NewReader(file), BookImportCSV)
+		assert.Equal(t, ErrBookImportTooLarge, err)
+	})
+}
+
+func TestParseBookImportJSONL(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		file := `{"title": "100 Go Mistakes", "author": "Teiva Harsanyi", "amount": 3, "isbn": "9781617299537"}` + "\n" +
+			"\n" +
+			`{"title": "Let's Go", "author": "Alex Edwards"}` + "\n"
+
+		rows, err := ParseBookImport(strings.NewReader(file), BookImportJSONL)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+
+		assert.Equal(t, 1, rows[0].Line)
+		assert.Equal(t, 3, rows[0].Book.Amount)
+		assert.Equal(t, "9781617299537", rows[0].Book.ISBN)
+
+		assert.Equal(t, 3, rows[1].Line, "blank lines are skipped")
+		assert.Nil(t, rows[1].Fields.Amount)
+		assert.Nil(t, rows[1].Fields.ISBN)
+		assert.Equal(t, "Alex Edwards", *rows[1].Fields.Author)
+	})
+	t.Run("Rejected Rows", func(t *testing.T) {
+		file := `{"title": "Let's Go",` + "\n" +
+			`{"title": "Let's Go", "price": 10}` + "\n"
+
+		rows, err := ParseBookImport(strings.NewReader(file), BookImportJSONL)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+
+		for _, row := range rows {
+			assert.Equal(t, BookImportRejected, row.Status)
+			assert.Error(t, row.Err)
+		}
+	})
+}
+
+func TestParseBookImportFormat(t *testing.T) {
+	_, err := ParseBookImport(strings.NewReader("title\n"), "xml")
+	assert.Equal(t, ErrInvalidBookImport, err)
+}
+
+func TestBookImportReport(t *testing.T) {
+	var report BookImportReport
+	report.Add(BookImportRow{Line: 2, Status: BookImportCreated})
+	report.Add(BookImportRow{Line: 3, Status: BookImportUpdated})
+	report.Add(BookImportRow{Line: 4, Status: BookImportRejected})
+	report.Add(BookImportRow{Line: 5, Status: BookImportCreated})
+
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Rejected)
+	assert.Len(t, report.Rows, 4)
+}
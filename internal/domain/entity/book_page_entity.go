@@ -0,0 +1,72 @@
+package entity
+
+import "strings"
+
+// Page sizes used when listing books
+const (
+	DefaultBookPageSize = 20
+	MaxBookPageSize     = 100
+)
+
+// BookSort is the field books are ordered by, a leading "-" sorts in descending order
+type BookSort string
+
+// Book sort fields
+const (
+	BookSortTitle     BookSort = "title"
+	BookSortAuthor    BookSort = "author"
+	BookSortCreatedAt BookSort = "created_at"
+)
+
+// BookQuery selects a page of books, the cursor comes from the previous page.
+// Query matches the title or the author, Title and Author match only their own field
+// and AuthorID selects the books crediting the author. SubjectID selects the books
+// tagged with the subject or any subject below it
+type BookQuery struct {
+	Query     string
+	Title     string
+	Author    string
+	AuthorID  int
+	SubjectID int
+	Available bool
+	Sort      BookSort
+	Limit     int
+	Cursor    string
+}
+
+// BookPage is a page of books along with the total of books matching the query,
+// NextCursor is empty on the last page
+type BookPage struct {
+	Books      []*Book
+	Total      int
+	NextCursor string
+}
+
+// Validate validates the book query.
+func (q *BookQuery) Validate() error {
+	if q.Limit < 1 || q.Limit > MaxBookPageSize || !q.Sort.IsValid() || q.AuthorID < 0 || q.SubjectID < 0 {
+		return ErrInvalidBookQuery
+	}
+
+	return nil
+}
+
+// Field returns the sorted field without the direction
+func (s BookSort) Field() BookSort {
+	return BookSort(strings.TrimPrefix(string(s), "-"))
+}
+
+// Descending reports whether the books are sorted in descending order
+func (s BookSort) Descending() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+// IsValid reports whether the books can be sorted by the field
+func (s BookSort) IsValid() bool {
+	switch s.Field() {
+	case BookSortTitle, BookSortAuthor, BookSortCreatedAt:
+		return true
+	}
+
+	return false
+}
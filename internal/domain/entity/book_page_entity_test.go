@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookQueryValidate(t *testing.T) {
+	tests := map[string]struct {
+		query BookQuery
+		want  error
+	}{
+		"OK": {
+			query: BookQuery{Sort: BookSortTitle, Limit: DefaultBookPageSize},
+			want:  nil,
+		},
+		"Descending": {
+			query: BookQuery{Sort: "-created_at", Limit: MaxBookPageSize},
+			want:  nil,
+		},
+		"Invalid Sort": {
+			query: BookQuery{Sort: "amount", Limit: DefaultBookPageSize},
+			want:  ErrInvalidBookQuery,
+		},
+		"Zero Limit": {
+			query: BookQuery{Sort: BookSortTitle, Limit: 0},
+			want:  ErrInvalidBookQuery,
+		},
+		"Limit Too Large": {
+			query: BookQuery{Sort: BookSortTitle, Limit: MaxBookPageSize + 1},
+			want:  ErrInvalidBookQuery,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.query.Validate())
+		})
+	}
+}
+
+func TestBookSort(t *testing.T) {
+	assert.Equal(t, BookSortAuthor, BookSort("-author").Field())
+	assert.True(t, BookSort("-author").Descending())
+	assert.False(t, BookSortAuthor.Descending())
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// BookSearch is a full-text search over the title, author and description of
+// the books, every word must match and the last letters of a word may be missing
+type BookSearch struct {
+	Query string
+	Limit int
+}
+
+// BookMatch is a book found by a search, matches are ordered by Rank and
+// Snippet is an excerpt of the book with the matched words highlighted
+type BookMatch struct {
+	Book    *Book
+	Rank    float64
+	Snippet string
+}
+
+// Terms splits the query into lowercase words, punctuation is dropped
+func (s *BookSearch) Terms() []string {
+	words := strings.FieldsFunc(s.Query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+
+	return words
+}
+
+// Validate validates the book search.
+func (s *BookSearch) Validate() error {
+	if len(s.Terms()) == 0 || s.Limit < 1 || s.Limit > MaxBookPageSize {
+		return ErrInvalidBookSearch
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookSearchTerms(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  []string
+	}{
+		"Single Word": {
+			query: "Go",
+			want:  []string{"go"},
+		},
+		"Multiple Words": {
+			query: "  Let's go   further! ",
+			want:  []string{"let", "s", "go", "further"},
+		},
+		"Operators Dropped": {
+			query: "go & !python | (rust:*)",
+			want:  []string{"go", "python", "rust"},
+		},
+		"Accented Letters": {
+			query: "José Saramago, Ensaio sobre a Cegueira",
+			want:  []string{"josé", "saramago", "ensaio", "sobre", "a", "cegueira"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := BookSearch{Query: tc.query}
+			assert.Equal(t, tc.want, s.Terms())
+		})
+	}
+}
+
+func TestBookSearchValidate(t *testing.T) {
+	tests := map[string]struct {
+		search BookSearch
+		want   error
+	}{
+		"OK": {
+			search: BookSearch{Query: "go", Limit: DefaultBookPageSize},
+			want:   nil,
+		},
+		"No Words": {
+			search: BookSearch{Query: " !? ", Limit: DefaultBookPageSize},
+			want:   ErrInvalidBookSearch,
+		},
+		"Zero Limit": {
+			search: BookSearch{Query: "go", Limit: 0},
+			want:   ErrInvalidBookSearch,
+		},
+		"Limit Too Large": {
+			search: BookSearch{Query: "go", Limit: MaxBookPageSize + 1},
+			want:   ErrInvalidBookSearch,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.search.Validate())
+		})
+	}
+}
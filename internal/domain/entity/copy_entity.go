@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type CopyStatus string
+
+// Copy statuses, only available copies can be borrowed
+const (
+	// CopyAvailable copies are on the shelf
+	CopyAvailable CopyStatus = "available"
+	// CopyOnLoan copies are with a patron, they change status by borrowing and returning
+	CopyOnLoan CopyStatus = "on_loan"
+	// CopyLost copies were not found or never returned
+	CopyLost CopyStatus = "lost"
+	// CopyInRepair copies are being repaired or rebound
+	CopyInRepair CopyStatus = "in_repair"
+)
+
+// Copy is a physical item of a book, identified by the barcode on its label
+type Copy struct {
+	ID        int        `json:"id"`
+	BookID    int        `json:"book_id"`
+	Barcode   string     `json:"barcode"`
+	Status    CopyStatus `json:"status"`
+	Location  string     `json:"location"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// NewCopy creates a new available copy entity
+func NewCopy(bookID int, barcode, location string) (*Copy, error) {
+	c := &Copy{
+		BookID:    bookID,
+		Barcode:   barcode,
+		Status:    CopyAvailable,
+		Location:  location,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Time{},
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Validate validates the copy entity.
+func (c *Copy) Validate() error {
+	if c.BookID <= 0 || c.Barcode == "" || strings.ContainsAny(c.Barcode, " \t\r\n") {
+		return ErrInvalidCopy
+	}
+
+	if !c.Status.IsValid() {
+		return ErrInvalidCopyStatus
+	}
+
+	return nil
+}
+
+// IsValid reports whether the status is a known copy status
+func (s CopyStatus) IsValid() bool {
+	switch s {
+	case CopyAvailable, CopyOnLoan, CopyLost, CopyInRepair:
+		return true
+	}
+
+	return false
+}
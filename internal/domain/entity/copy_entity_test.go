@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCopy(t *testing.T) {
+	tests := map[string]struct {
+		bookID  int
+		barcode string
+		want    error
+	}{
+		"OK": {
+			bookID:  1,
+			barcode: "B1-1",
+			want:    nil,
+		},
+		"Invalid BookID": {
+			bookID:  0,
+			barcode: "B1-1",
+			want:    ErrInvalidCopy,
+		},
+		"Empty Barcode": {
+			bookID:  1,
+			barcode: "",
+			want:    ErrInvalidCopy,
+		},
+		"Barcode With Spaces": {
+			bookID:  1,
+			barcode: "B1 1",
+			want:    ErrInvalidCopy,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewCopy(tc.bookID, tc.barcode, "A3")
+
+			assert.Equal(t, tc.want, err)
+
+			if name == "OK" {
+				assert.Equal(t, tc.bookID, c.BookID)
+				assert.Equal(t, tc.barcode, c.Barcode)
+				assert.Equal(t, CopyAvailable, c.Status)
+				assert.Equal(t, "A3", c.Location)
+			}
+		})
+	}
+}
+
+func TestCopyValidateStatus(t *testing.T) {
+	c := Copy{BookID: 1, Barcode: "B1-1", Status: CopyInRepair}
+	assert.NoError(t, c.Validate())
+
+	c.Status = "borrowed"
+	assert.Equal(t, ErrInvalidCopyStatus, c.Validate())
+}
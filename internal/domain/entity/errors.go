@@ -4,11 +4,36 @@ import "errors"
 
 // Entity Errors
 var (
-	ErrInvalidBook     = errors.New("invalid book")
-	ErrInvalidLoan     = errors.New("user ID and book ID can't be empty")
-	ErrEmptyUserField  = errors.New("username, password and email can't be empty")
-	ErrFieldWithSpaces = errors.New("username and password can't have spaces")
-	ErrShortPassword   = errors.New("password shorter than 6 characters")
-	ErrLongPassword    = errors.New("password longer than 72 characters")
-	ErrInvalidEmail    = errors.New("invalid email address")
+	ErrInvalidBook          = errors.New("invalid book")
+	ErrInvalidISBN          = errors.New("ISBN must be a valid ISBN-10 or ISBN-13")
+	ErrInvalidPublishedYear = errors.New("published year can't be negative or more than a year ahead")
+	ErrInvalidLanguage      = errors.New("language must be a lowercase ISO 639 code such as en or por")
+	ErrInvalidPages         = errors.New("pages can't be negative")
+	ErrInvalidCoverURL      = errors.New("cover URL must be an absolute http or https URL")
+	ErrInvalidBookQuery     = errors.New("limit must be between 1 and 100 and sort must be title, author or created_at")
+	ErrInvalidBookSearch    = errors.New("search needs at least one word and a limit between 1 and 100")
+	ErrInvalidBookImport    = errors.New("import must be a CSV file with a header of known columns including title, or a JSON Lines file")
+	ErrBookImportTooLarge   = errors.New("import has more rows than allowed in a single file")
+	ErrInvalidBookExport    = errors.New("export format must be csv, jsonl or marcxml")
+	ErrInvalidAuthor        = errors.New("author name can't be empty")
+	ErrInvalidBookAuthors   = errors.New("authors need an ID and a role of author, editor or translator, credited once per role")
+	ErrInvalidSubject       = errors.New("subject name can't be empty and a subject can't be its own parent")
+	ErrInvalidBookSubjects  = errors.New("subject IDs must be positive and tagged once")
+	ErrInvalidCopy          = errors.New("book ID and barcode can't be empty and the barcode can't have spaces")
+	ErrInvalidCopyStatus    = errors.New("status must be available, on_loan, lost or in_repair")
+	ErrInvalidMovement      = errors.New("book ID can't be empty, quantity must be between 1 and 100 and the reason can't be empty")
+	ErrInvalidMovementKind  = errors.New("kind must be acquired, lost, damaged, withdrawn or restored")
+	ErrInvalidLoan          = errors.New("user ID and book ID can't be empty")
+	ErrInvalidLoanPeriod    = errors.New("loan period must be positive")
+	ErrInvalidHold          = errors.New("user ID and book ID can't be empty")
+	ErrInvalidLedgerEntry   = errors.New("user ID can't be empty")
+	ErrInvalidAmount        = errors.New("amount must be a positive number of cents")
+	ErrEmptyUserField       = errors.New("username, password and email can't be empty")
+	ErrFieldWithSpaces      = errors.New("username and password can't have spaces")
+	ErrShortPassword        = errors.New("password shorter than 6 characters")
+	ErrLongPassword         = errors.New("password longer than 72 characters")
+	ErrInvalidEmail         = errors.New("invalid email address")
+	ErrInvalidCategory      = errors.New("category must be guest, student or staff")
+	ErrInvalidRole          = errors.New("role must be patron or librarian")
+	ErrInvalidResetToken    = errors.New("user ID can't be empty and the token must expire in the future")
 )
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+)
+
+type HoldStatus string
+
+// Hold statuses
+const (
+	// HoldWaiting holds are queued for the next copy returned
+	HoldWaiting HoldStatus = "waiting"
+	// HoldReady holds have a copy set aside until they expire
+	HoldReady HoldStatus = "ready"
+	// HoldFulfilled holds ended with the patron borrowing the book
+	HoldFulfilled HoldStatus = "fulfilled"
+	// HoldCancelled holds were withdrawn before being fulfilled
+	HoldCancelled HoldStatus = "cancelled"
+	// HoldExpired holds were not picked up in time
+	HoldExpired HoldStatus = "expired"
+)
+
+type Hold struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	BookID    int        `json:"book_id"`
+	Status    HoldStatus `json:"status"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// NewHold creates a new hold entity waiting in the queue
+func NewHold(userID, bookID int) (*Hold, error) {
+	hold := &Hold{
+		UserID:    userID,
+		BookID:    bookID,
+		Status:    HoldWaiting,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Time{},
+	}
+
+	if err := hold.Validate(); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// Validate validates the hold entity.
+func (hold *Hold) Validate() error {
+	if hold.UserID <= 0 || hold.BookID <= 0 {
+		return ErrInvalidHold
+	}
+
+	return nil
+}
+
+// IsActive reports whether the hold is still in the queue
+func (hold *Hold) IsActive() bool {
+	return hold.Status == HoldWaiting || hold.Status == HoldReady
+}
+
+// IsExpired reports whether a ready hold was not picked up in time
+func (hold *Hold) IsExpired(now time.Time) bool {
+	return hold.Status == HoldReady && now.After(hold.ExpiresAt)
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHold(t *testing.T) {
+	tests := map[string]struct {
+		userID int
+		bookID int
+		want   error
+	}{
+		"OK": {
+			userID: 1,
+			bookID: 1,
+			want:   nil,
+		},
+		"Invalid UserID": {
+			userID: 0,
+			bookID: 1,
+			want:   ErrInvalidHold,
+		},
+		"Invalid BookID": {
+			userID: 1,
+			bookID: 0,
+			want:   ErrInvalidHold,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, err := NewHold(tc.userID, tc.bookID)
+
+			assert.Equal(t, tc.want, err)
+
+			if name == "OK" {
+				assert.Equal(t, tc.userID, h.UserID)
+				assert.Equal(t, tc.bookID, h.BookID)
+				assert.Equal(t, HoldWaiting, h.Status)
+				assert.True(t, h.IsActive())
+			}
+		})
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		hold Hold
+		want bool
+	}{
+		"Waiting": {
+			hold: Hold{Status: HoldWaiting},
+			want: false,
+		},
+		"Ready": {
+			hold: Hold{Status: HoldReady, ExpiresAt: now.Add(time.Hour)},
+			want: false,
+		},
+		"Ready Expired": {
+			hold: Hold{Status: HoldReady, ExpiresAt: now.Add(-time.Hour)},
+			want: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.hold.IsExpired(now))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxMovementQuantity is the largest number of copies moved by a single stock adjustment
+const MaxMovementQuantity = 100
+
+type MovementKind string
+
+// Kinds of stock adjustments, only acquisitions and restorations add copies to the shelves
+const (
+	// MovementAcquired adds new available copies of the book
+	MovementAcquired MovementKind = "acquired"
+	// MovementLost marks available copies as lost
+	MovementLost MovementKind = "lost"
+	// MovementDamaged sends available copies to repair
+	MovementDamaged MovementKind = "damaged"
+	// MovementWithdrawn removes available copies from the collection
+	MovementWithdrawn MovementKind = "withdrawn"
+	// MovementRestored puts lost or repaired copies back on the shelves
+	MovementRestored MovementKind = "restored"
+)
+
+// InventoryMovement is a stock adjustment of a book, recorded with the
+// librarian who made it, the reason and the barcodes of the copies it moved
+type InventoryMovement struct {
+	ID        int          `json:"id"`
+	BookID    int          `json:"book_id"`
+	UserID    int          `json:"user_id"`
+	Kind      MovementKind `json:"kind"`
+	Quantity  int          `json:"quantity"`
+	Reason    string       `json:"reason"`
+	Barcodes  []string     `json:"barcodes"`
+	CreatedAt time.Time
+}
+
+// NewInventoryMovement creates a new stock adjustment entity
+func NewInventoryMovement(bookID, userID int, kind MovementKind, quantity int, reason string) (*InventoryMovement, error) {
+	m := &InventoryMovement{
+		BookID:    bookID,
+		UserID:    userID,
+		Kind:      kind,
+		Quantity:  quantity,
+		Reason:    strings.TrimSpace(reason),
+		CreatedAt: time.Now(),
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Validate validates the inventory movement entity.
+func (m *InventoryMovement) Validate() error {
+	if !m.Kind.IsValid() {
+		return ErrInvalidMovementKind
+	}
+
+	if m.BookID <= 0 || m.Quantity <= 0 || m.Quantity > MaxMovementQuantity || m.Reason == "" {
+		return ErrInvalidMovement
+	}
+
+	return nil
+}
+
+// IsValid reports whether the kind is a known stock adjustment
+func (k MovementKind) IsValid() bool {
+	switch k {
+	case MovementAcquired, MovementLost, MovementDamaged, MovementWithdrawn, MovementRestored:
+		return true
+	}
+
+	return false
+}
+
+// CopyStatus is the status the copies of a lost or damaged movement move to,
+// acquired and restored copies are available and withdrawn copies leave the collection
+func (k MovementKind) CopyStatus() CopyStatus {
+	switch k {
+	case MovementLost:
+		return CopyLost
+	case MovementDamaged:
+		return CopyInRepair
+	}
+
+	return CopyAvailable
+}
+
+// MovementTo is the kind of movement that puts a copy in the status, copies
+// only go on loan by borrowing them
+func MovementTo(status CopyStatus) (MovementKind, bool) {
+	switch status {
+	case CopyAvailable:
+		return MovementRestored, true
+	case CopyLost:
+		return MovementLost, true
+	case CopyInRepair:
+		return MovementDamaged, true
+	}
+
+	return "", false
+}
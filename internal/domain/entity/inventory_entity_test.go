@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInventoryMovement(t *testing.T) {
+	tests := map[string]struct {
+		bookID   int
+		kind     MovementKind
+		quantity int
+		reason   string
+		want     error
+	}{
+		"OK": {
+			bookID:   1,
+			kind:     MovementAcquired,
+			quantity: 3,
+			reason:   " donation ",
+			want:     nil,
+		},
+		"Invalid Kind": {
+			bookID:   1,
+			kind:     "stolen",
+			quantity: 1,
+			reason:   "missing after inventory",
+			want:     ErrInvalidMovementKind,
+		},
+		"Invalid BookID": {
+			bookID:   0,
+			kind:     MovementLost,
+			quantity: 1,
+			reason:   "missing after inventory",
+			want:     ErrInvalidMovement,
+		},
+		"Zero Quantity": {
+			bookID:   1,
+			kind:     MovementLost,
+			quantity: 0,
+			reason:   "missing after inventory",
+			want:     ErrInvalidMovement,
+		},
+		"Quantity Too Large": {
+			bookID:   1,
+			kind:     MovementWithdrawn,
+			quantity: MaxMovementQuantity + 1,
+			reason:   "weeding",
+			want:     ErrInvalidMovement,
+		},
+		"Empty Reason": {
+			bookID:   1,
+			kind:     MovementDamaged,
+			quantity: 1,
+			reason:   "  ",
+			want:     ErrInvalidMovement,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewInventoryMovement(tc.bookID, 7, tc.kind, tc.quantity, tc.reason)
+
+			assert.Equal(t, tc.want, err)
+
+			if name == "OK" {
+				assert.Equal(t, tc.bookID, m.BookID)
+				assert.Equal(t, 7, m.UserID)
+				assert.Equal(t, "donation", m.Reason)
+			}
+		})
+	}
+}
+
+func TestMovementKindCopyStatus(t *testing.T) {
+	assert.Equal(t, CopyLost, MovementLost.CopyStatus())
+	assert.Equal(t, CopyInRepair, MovementDamaged.CopyStatus())
+	assert.Equal(t, CopyAvailable, MovementAcquired.CopyStatus())
+	assert.Equal(t, CopyAvailable, MovementRestored.CopyStatus())
+}
+
+func TestMovementTo(t *testing.T) {
+	tests := map[CopyStatus]MovementKind{
+		CopyAvailable: MovementRestored,
+		CopyLost:      MovementLost,
+		CopyInRepair:  MovementDamaged,
+	}
+	for status, want := range tests {
+		t.Run(string(status), func(t *testing.T) {
+			got, ok := MovementTo(status)
+			assert.True(t, ok)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	_, ok := MovementTo(CopyOnLoan)
+	assert.False(t, ok)
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"time"
+)
+
+type EntryKind string
+
+// Ledger entry kinds
+const (
+	// EntryFine is a charge to the patron, such as a late-return fine
+	EntryFine EntryKind = "fine"
+	// EntryPayment is money received from the patron
+	EntryPayment EntryKind = "payment"
+)
+
+// LedgerEntry is a charge or payment in a patron balance, amounts are in cents
+type LedgerEntry struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	LoanID    int       `json:"loan_id"`
+	Kind      EntryKind `json:"kind"`
+	Amount    int       `json:"amount"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time
+}
+
+// Balance is how much a patron owes the library, in cents
+type Balance struct {
+	UserID  int            `json:"user_id"`
+	Amount  int            `json:"balance"`
+	Entries []*LedgerEntry `json:"entries"`
+}
+
+// NewFine creates a new ledger entry charging the patron for a loan
+func NewFine(userID, loanID, amount int, note string) (*LedgerEntry, error) {
+	entry := &LedgerEntry{
+		UserID:    userID,
+		LoanID:    loanID,
+		Kind:      EntryFine,
+		Amount:    amount,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// NewPayment creates a new ledger entry crediting a patron payment
+func NewPayment(userID, amount int, note string) (*LedgerEntry, error) {
+	entry := &LedgerEntry{
+		UserID:    userID,
+		Kind:      EntryPayment,
+		Amount:    amount,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Validate validates the ledger entry entity.
+func (entry *LedgerEntry) Validate() error {
+	if entry.UserID <= 0 {
+		return ErrInvalidLedgerEntry
+	}
+
+	if entry.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	return nil
+}
+
+// Signed returns the entry amount as it affects the balance
+func (entry *LedgerEntry) Signed() int {
+	if entry.Kind == EntryPayment {
+		return -entry.Amount
+	}
+
+	return entry.Amount
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFine(t *testing.T) {
+	tests := map[string]struct {
+		userID int
+		loanID int
+		amount int
+		want   error
+	}{
+		"OK": {
+			userID: 1,
+			loanID: 1,
+			amount: 150,
+			want:   nil,
+		},
+		"Invalid UserID": {
+			userID: 0,
+			loanID: 1,
+			amount: 150,
+			want:   ErrInvalidLedgerEntry,
+		},
+		"Invalid Amount": {
+			userID: 1,
+			loanID: 1,
+			amount: 0,
+			want:   ErrInvalidAmount,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewFine(tc.userID, tc.loanID, tc.amount, "late return")
+
+			assert.Equal(t, tc.want, err)
+
+			if name == "OK" {
+				assert.Equal(t, EntryFine, e.Kind)
+				assert.Equal(t, tc.amount, e.Signed())
+			}
+		})
+	}
+}
+
+func TestNewPayment(t *testing.T) {
+	tests := map[string]struct {
+		userID int
+		amount int
+		want   error
+	}{
+		"OK": {
+			userID: 1,
+			amount: 150,
+			want:   nil,
+		},
+		"Invalid UserID": {
+			userID: 0,
+			amount: 150,
+			want:   ErrInvalidLedgerEntry,
+		},
+		"Negative Amount": {
+			userID: 1,
+			amount: -150,
+			want:   ErrInvalidAmount,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewPayment(tc.userID, tc.amount, "cash")
+
+			assert.Equal(t, tc.want, err)
+
+			if name == "OK" {
+				assert.Equal(t, EntryPayment, e.Kind)
+				assert.Equal(t, -tc.amount, e.Signed())
+			}
+		})
+	}
+}
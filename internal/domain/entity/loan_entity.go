@@ -8,15 +8,24 @@ type Loan struct {
 	ID          int `json:"id"`
 	UserID      int `json:"user_id"`
 	BookID      int `json:"book_id"`
+	CopyID      int `json:"copy_id"`
 	Is_returned bool
+	DueDate     time.Time `json:"due_date"`
+	ReturnedAt  time.Time `json:"returned_at"`
+	Renewals    int       `json:"renewals"`
+	RenewedAt   time.Time `json:"renewed_at"`
 	CreatedAt   time.Time
 }
 
-// NewLoan creates a new loan entity
-func NewLoan(userID, bookID int) (*Loan, error) {
+// NewLoan creates a new loan entity due after the given loan period
+func NewLoan(userID, bookID int, loanPeriod time.Duration) (*Loan, error) {
+	now := time.Now()
+
 	loan := &Loan{
-		UserID: userID,
-		BookID: bookID,
+		UserID:    userID,
+		BookID:    bookID,
+		DueDate:   now.Add(loanPeriod),
+		CreatedAt: now,
 	}
 
 	if err := loan.Validate(); err != nil {
@@ -32,5 +41,26 @@ func (loan *Loan) Validate() error {
 		return ErrInvalidLoan
 	}
 
+	if !loan.DueDate.After(loan.CreatedAt) {
+		return ErrInvalidLoanPeriod
+	}
+
 	return nil
 }
+
+// OverdueDays returns how many started days the loan was kept past its due date
+func (loan *Loan) OverdueDays(at time.Time) int {
+	late := at.Sub(loan.DueDate)
+	if late <= 0 {
+		return 0
+	}
+
+	day := 24 * time.Hour
+
+	return int((late + day - 1) / day)
+}
+
+// IsOverdue reports whether the loan is still open after its due date
+func (loan *Loan) IsOverdue(now time.Time) bool {
+	return !loan.Is_returned && now.After(loan.DueDate)
+}
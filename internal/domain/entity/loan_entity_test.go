@@ -2,42 +2,109 @@ package entity
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewLoan(t *testing.T) {
 	tests := map[string]struct {
-		userID int
-		bookID int
-		want   error
+		userID     int
+		bookID     int
+		loanPeriod time.Duration
+		want       error
 	}{
 		"OK": {
-			userID: 1,
-			bookID: 1,
-			want:   nil,
+			userID:     1,
+			bookID:     1,
+			loanPeriod: 14 * 24 * time.Hour,
+			want:       nil,
 		},
 		"Invalid UserID": {
-			userID: 0,
-			bookID: 1,
-			want:   ErrInvalidLoan,
+			userID:     0,
+			bookID:     1,
+			loanPeriod: 14 * 24 * time.Hour,
+			want:       ErrInvalidLoan,
 		},
 		"Invalid BookID": {
-			userID: 1,
-			bookID: 0,
-			want:   ErrInvalidLoan,
+			userID:     1,
+			bookID:     0,
+			loanPeriod: 14 * 24 * time.Hour,
+			want:       ErrInvalidLoan,
+		},
+		"Invalid Loan Period": {
+			userID:     1,
+			bookID:     1,
+			loanPeriod: 0,
+			want:       ErrInvalidLoanPeriod,
 		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			l, err := NewLoan(tc.userID, tc.bookID)
+			l, err := NewLoan(tc.userID, tc.bookID, tc.loanPeriod)
 
 			assert.Equal(t, tc.want, err)
 
 			if name == "OK" {
 				assert.Equal(t, tc.userID, l.UserID)
 				assert.Equal(t, tc.bookID, l.BookID)
+				assert.Equal(t, l.CreatedAt.Add(tc.loanPeriod), l.DueDate)
 			}
 		})
 	}
 }
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		loan Loan
+		want bool
+	}{
+		"Not Due Yet": {
+			loan: Loan{DueDate: now.Add(time.Hour)},
+			want: false,
+		},
+		"Overdue": {
+			loan: Loan{DueDate: now.Add(-time.Hour)},
+			want: true,
+		},
+		"Returned": {
+			loan: Loan{DueDate: now.Add(-time.Hour), Is_returned: true},
+			want: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.loan.IsOverdue(now))
+		})
+	}
+}
+
+func TestOverdueDays(t *testing.T) {
+	due := time.Now()
+
+	tests := map[string]struct {
+		at   time.Time
+		want int
+	}{
+		"On Time": {
+			at:   due.Add(-time.Hour),
+			want: 0,
+		},
+		"Late By Hours": {
+			at:   due.Add(2 * time.Hour),
+			want: 1,
+		},
+		"Late By Days": {
+			at:   due.Add(72 * time.Hour),
+			want: 3,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loan := Loan{DueDate: due}
+			assert.Equal(t, tc.want, loan.OverdueDays(tc.at))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// ResetToken lets a user set a new password without knowing the current one,
+// only the hash of the token is stored, the token itself is mailed to the user
+type ResetToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
+
+// NewResetToken creates a new reset token entity valid for ttl, it returns the
+// entity along with the plaintext token that must be sent to the user
+func NewResetToken(userID int, ttl time.Duration) (*ResetToken, string, error) {
+	if userID <= 0 || ttl <= 0 {
+		return nil, "", ErrInvalidResetToken
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+
+	token := hex.EncodeToString(secret)
+	now := time.Now()
+
+	return &ResetToken{
+		UserID:    userID,
+		TokenHash: HashResetToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// HashResetToken hashes a plaintext reset token the way it is stored
+func HashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsUsable reports whether the token can still reset the password at the given time
+func (t *ResetToken) IsUsable(at time.Time) bool {
+	return t.UsedAt.IsZero() && at.Before(t.ExpiresAt)
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResetToken(t *testing.T) {
+	tests := map[string]struct {
+		userID int
+		ttl    time.Duration
+		want   error
+	}{
+		"OK": {
+			userID: 1,
+			ttl:    30 * time.Minute,
+			want:   nil,
+		},
+		"Invalid UserID": {
+			userID: 0,
+			ttl:    30 * time.Minute,
+			want:   ErrInvalidResetToken,
+		},
+		"Invalid TTL": {
+			userID: 1,
+			ttl:    0,
+			want:   ErrInvalidResetToken,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt, token, err := NewResetToken(tc.userID, tc.ttl)
+
+			assert.Equal(t, tc.want, err)
+
+			if name == "OK" {
+				assert.Equal(t, tc.userID, rt.UserID)
+				assert.Equal(t, HashResetToken(token), rt.TokenHash)
+				assert.NotEqual(t, token, rt.TokenHash)
+				assert.Equal(t, rt.CreatedAt.Add(tc.ttl), rt.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestResetTokenIsUsable(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		token ResetToken
+		want  bool
+	}{
+		"Usable": {
+			token: ResetToken{ExpiresAt: now.Add(time.Minute)},
+			want:  true,
+		},
+		"Expired": {
+			token: ResetToken{ExpiresAt: now.Add(-time.Minute)},
+			want:  false,
+		},
+		"Used": {
+			token: ResetToken{ExpiresAt: now.Add(time.Minute), UsedAt: now},
+			want:  false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.token.IsUsable(now))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Subject is a genre or topic of the catalog, subjects without a parent are
+// the top of the taxonomy
+type Subject struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	ParentID  int    `json:"parent_id"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// NewSubject creates a new subject entity, a zero parentID makes a top subject
+func NewSubject(name string, parentID int) (*Subject, error) {
+	s := &Subject{
+		Name:      strings.TrimSpace(name),
+		ParentID:  parentID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Time{},
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Validate validates the subject entity.
+func (s *Subject) Validate() error {
+	if strings.TrimSpace(s.Name) == "" || s.ParentID < 0 || (s.ID != 0 && s.ParentID == s.ID) {
+		return ErrInvalidSubject
+	}
+
+	return nil
+}
+
+// ValidateBookSubjects validates the subjects a book is tagged with
+func ValidateBookSubjects(subjects []Subject) error {
+	tagged := make(map[int]bool, len(subjects))
+	for _, s := range subjects {
+		if s.ID <= 0 || tagged[s.ID] {
+			return ErrInvalidBookSubjects
+		}
+		tagged[s.ID] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSubject(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		parentID int
+		want     error
+	}{
+		"OK": {
+			name: "Computing",
+			want: nil,
+		},
+		"Below Parent": {
+			name:     "Programming",
+			parentID: 1,
+			want:     nil,
+		},
+		"Blank Name": {
+			name: "  ",
+			want: ErrInvalidSubject,
+		},
+		"Negative Parent": {
+			name:     "Programming",
+			parentID: -1,
+			want:     ErrInvalidSubject,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSubject(tc.name, tc.parentID)
+			assert.Equal(t, tc.want, err)
+		})
+	}
+}
+
+func TestSubjectValidate(t *testing.T) {
+	s := Subject{ID: 1, Name: "Computing", ParentID: 1}
+	assert.Equal(t, ErrInvalidSubject, s.Validate())
+}
+
+func TestValidateBookSubjects(t *testing.T) {
+	tests := map[string]struct {
+		subjects []Subject
+		want     error
+	}{
+		"OK": {
+			subjects: []Subject{{ID: 1}, {ID: 2}},
+			want:     nil,
+		},
+		"No Subjects": {
+			subjects: nil,
+			want:     nil,
+		},
+		"Missing ID": {
+			subjects: []Subject{{Name: "Computing"}},
+			want:     ErrInvalidBookSubjects,
+		},
+		"Duplicated Subject": {
+			subjects: []Subject{{ID: 1}, {ID: 1}},
+			want:     ErrInvalidBookSubjects,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ValidateBookSubjects(tc.subjects))
+		})
+	}
+}
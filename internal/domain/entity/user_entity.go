@@ -6,21 +6,48 @@ import (
 	"time"
 )
 
+type Category string
+
+// Patron categories, each one with its own borrowing limits
+const (
+	CategoryGuest   Category = "guest"
+	CategoryStudent Category = "student"
+	CategoryStaff   Category = "staff"
+)
+
+type Role string
+
+// User roles, librarians manage the catalog and other users
+const (
+	RolePatron    Role = "patron"
+	RoleLibrarian Role = "librarian"
+)
+
 type User struct {
-	ID        int    `json:"id"`
-	Username  string `json:"username"`
-	Password  string `json:"password"`
-	Email     string `json:"email"`
+	ID        int      `json:"id"`
+	Username  string   `json:"username"`
+	Password  string   `json:"-"`
+	Email     string   `json:"email"`
+	Category  Category `json:"category"`
+	Role      Role     `json:"role"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// Version counts the updates of the user, an update of an older version fails
+	Version int
 }
 
-// NewUser creates a new user entity
-func NewUser(username, password, email string) (*User, error) {
+// NewUser creates a new patron user entity, users without a category are guests
+func NewUser(username, password, email string, category Category) (*User, error) {
+	if category == "" {
+		category = CategoryGuest
+	}
+
 	user := &User{
 		Username:  username,
 		Password:  password,
 		Email:     email,
+		Category:  category,
+		Role:      RolePatron,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Time{},
 	}
@@ -32,28 +59,90 @@ func NewUser(username, password, email string) (*User, error) {
 	return user, nil
 }
 
-// Validate validates the user entity.
+// Validate validates the user entity, including its plaintext password.
 func (user *User) Validate() error {
-	if user.Username == "" || user.Password == "" || user.Email == "" {
+	if err := user.ValidateProfile(); err != nil {
+		return err
+	}
+
+	return ValidatePassword(user.Password)
+}
+
+// ValidateProfile validates every user field except the password.
+func (user *User) ValidateProfile() error {
+	if user.Username == "" || user.Email == "" {
+		return ErrEmptyUserField
+	}
+
+	if strings.ContainsAny(user.Username, " \t\r\n") {
+		return ErrFieldWithSpaces
+	}
+
+	_, err := mail.ParseAddress(user.Email)
+	if err != nil {
+		return ErrInvalidEmail
+	}
+
+	if !user.Category.IsValid() {
+		return ErrInvalidCategory
+	}
+
+	return nil
+}
+
+// UserPatch is a partial update of the profile of a user, nil fields keep the
+// stored value. The password and the role have their own use cases
+type UserPatch struct {
+	Username *string
+	Email    *string
+	Category *Category
+}
+
+// Apply sets the fields of the patch on the user
+func (p *UserPatch) Apply(user *User) {
+	if p.Username != nil {
+		user.Username = *p.Username
+	}
+	if p.Email != nil {
+		user.Email = *p.Email
+	}
+	if p.Category != nil {
+		user.Category = *p.Category
+	}
+}
+
+// ValidatePassword validates a plaintext password before it is hashed.
+func ValidatePassword(password string) error {
+	if password == "" {
 		return ErrEmptyUserField
 	}
 
-	if strings.ContainsAny(user.Username, " \t\r\n") || strings.ContainsAny(user.Password, " \t\r\n") {
+	if strings.ContainsAny(password, " \t\r\n") {
 		return ErrFieldWithSpaces
 	}
 
-	if len(user.Password) < 6 {
+	if len(password) < 6 {
 		return ErrShortPassword
 	}
 
-	if len(user.Password) > 72 {
+	if len(password) > 72 {
 		return ErrLongPassword
 	}
 
-	_, err := mail.ParseAddress(user.Email)
-	if err != nil {
-		return ErrInvalidEmail
+	return nil
+}
+
+// IsValid reports whether the category is one of the known patron categories
+func (c Category) IsValid() bool {
+	switch c {
+	case CategoryGuest, CategoryStudent, CategoryStaff:
+		return true
 	}
 
-	return nil
+	return false
+}
+
+// IsValid reports whether the role is one of the known user roles
+func (r Role) IsValid() bool {
+	return r == RolePatron || r == RoleLibrarian
 }
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ func TestNewUser(t *testing.T) {
 		username string
 		password string
 		email    string
+		category Category
 		want     error
 	}{
 		"OK": {
@@ -49,10 +51,24 @@ func TestNewUser(t *testing.T) {
 			email:    "luigiEmail.com",
 			want:     ErrInvalidEmail,
 		},
+		"Student": {
+			username: "luigi",
+			password: "secret",
+			email:    "luigi@email.com",
+			category: CategoryStudent,
+			want:     nil,
+		},
+		"Invalid Category": {
+			username: "luigi",
+			password: "secret",
+			email:    "luigi@email.com",
+			category: "admin",
+			want:     ErrInvalidCategory,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			u, err := NewUser(tc.username, tc.password, tc.email)
+			u, err := NewUser(tc.username, tc.password, tc.email, tc.category)
 
 			assert.Equal(t, tc.want, err)
 
@@ -60,7 +76,46 @@ func TestNewUser(t *testing.T) {
 				assert.Equal(t, tc.username, u.Username)
 				assert.Equal(t, tc.password, u.Password)
 				assert.Equal(t, tc.email, u.Email)
+				assert.Equal(t, CategoryGuest, u.Category)
+				assert.Equal(t, RolePatron, u.Role)
 			}
 		})
 	}
 }
+
+func TestValidatePassword(t *testing.T) {
+	tests := map[string]struct {
+		password string
+		want     error
+	}{
+		"OK":             {password: "secret", want: nil},
+		"Empty":          {password: "", want: ErrEmptyUserField},
+		"With Spaces":    {password: "Pass Word", want: ErrFieldWithSpaces},
+		"Short Password": {password: "short", want: ErrShortPassword},
+		"Long Password":  {password: strings.Repeat("a", 73), want: ErrLongPassword},
+		"Maximum Length": {password: strings.Repeat("a", 72), want: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ValidatePassword(tc.password))
+		})
+	}
+}
+
+func TestRoleIsValid(t *testing.T) {
+	assert.True(t, RolePatron.IsValid())
+	assert.True(t, RoleLibrarian.IsValid())
+	assert.False(t, Role("admin").IsValid())
+	assert.False(t, Role("").IsValid())
+}
+
+func TestUserPatchApply(t *testing.T) {
+	email := "luigi@library.com"
+	category := CategoryStaff
+
+	u := &User{ID: 1, Username: "luigi", Password: "hash", Email: "luigi@email.com", Category: CategoryStudent, Role: RolePatron}
+	p := &UserPatch{Email: &email, Category: &category}
+	p.Apply(u)
+
+	assert.Equal(t, &User{ID: 1, Username: "luigi", Password: "hash", Email: email, Category: CategoryStaff, Role: RolePatron}, u)
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	svc "github.com/LuigiAzevedo/public-library-v2/internal/ports/service"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+// dummyHash is compared against the password of unknown usernames so logging in
+// takes as long for them as for registered users
+const dummyHash = "$2a$10$7Cn3.J38L6BnyJs3etbgluDI2G6RNwb3llfXcgVto.YXC0qf.09uG"
+
+type authUseCase struct {
+	userRepo  r.UserRepository
+	resetRepo r.ResetTokenRepository
+	txManager r.TxManager
+	tokens    svc.TokenService
+	mail      svc.MailSender
+	resetTTL  time.Duration
+}
+
+// NewAuthUseCase creates a new instance of authUseCase, password reset tokens are valid for resetTTL
+func NewAuthUseCase(user r.UserRepository, reset r.ResetTokenRepository, tx r.TxManager, tokens svc.TokenService, mail svc.MailSender, resetTTL time.Duration) u.AuthUsecase {
+	return &authUseCase{
+		userRepo:  user,
+		resetRepo: reset,
+		txManager: tx,
+		tokens:    tokens,
+		mail:      mail,
+		resetTTL:  resetTTL,
+	}
+}
+
+func (s *authUseCase) Login(ctx context.Context, username, password string) (*entity.Token, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if err == r.ErrUserNotFound {
+			_ = bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	token, err := s.tokens.Issue(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *authUseCase) Authenticate(ctx context.Context, token string) (*entity.Claims, error) {
+	claims, err := s.tokens.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// tokens of deleted users are no longer valid
+	user, err := s.userRepo.Get(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the role is read on every request so promotions and demotions apply at once
+	claims.Role = user.Role
+
+	return claims, nil
+}
+
+func (s *authUseCase) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		// callers must not learn which emails are registered
+		if err == r.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	resetToken, token, err := entity.NewResetToken(user.ID, s.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.resetRepo.Create(ctx, resetToken)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"use the token below to reset your password, it expires in %d minutes:\n\n"+
+		"%s\n\n"+
+		"If you did not ask for a password reset you can ignore this email.\n",
+		user.Username, int(s.resetTTL.Minutes()), token)
+
+	return s.mail.Send(ctx, user.Email, "Password reset", body)
+}
+
+func (s *authUseCase) ResetPassword(ctx context.Context, token, password string) error {
+	resetToken, err := s.resetRepo.GetByHash(ctx, entity.HashResetToken(token))
+	if err != nil {
+		if err == r.ErrResetTokenNotFound {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if !resetToken.IsUsable(time.Now()) {
+		return ErrInvalidResetToken
+	}
+
+	err = entity.ValidatePassword(password)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// the token is burnt with the password change so a failed change leaves it usable
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// burn the token first so it can't be replayed by a concurrent request
+		err := s.resetRepo.MarkUsed(ctx, resetToken.ID)
+		if err != nil {
+			if err == r.ErrResetTokenNotFound {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		return s.userRepo.UpdatePassword(ctx, resetToken.UserID, string(hashedPassword))
+	})
+}
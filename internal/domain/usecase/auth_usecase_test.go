@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/auth"
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mail"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+func TestLogin(t *testing.T) {
+	repo := mock.NewMockUserRepository()
+	uc := NewAuthUseCase(repo, mock.NewMockResetTokenRepository(), mock.NewMockTxManager(), auth.NewJWTService("secret", time.Hour), mail.NewMemorySender(), 30*time.Minute)
+	ctx := context.Background()
+
+	// passwords are stored hashed by the user use case
+	_, err := NewUserUseCase(repo).CreateUser(ctx, &entity.User{
+		Username: "UserThree",
+		Password: "PasswordThree",
+		Email:    "three@email.com",
+	})
+	assert.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		token, err := uc.Login(ctx, "UserThree", "PasswordThree")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.Value)
+
+		claims, err := uc.Authenticate(ctx, token.Value)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, claims.UserID)
+	})
+	t.Run("Wrong Password", func(t *testing.T) {
+		token, err := uc.Login(ctx, "UserThree", "WrongPassword")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Nil(t, token)
+	})
+	t.Run("Unknown User", func(t *testing.T) {
+		token, err := uc.Login(ctx, "Nobody", "PasswordThree")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Nil(t, token)
+
+		// the dummy hash costs as much to compare as the stored passwords
+		cost, err := bcrypt.Cost([]byte(dummyHash))
+		assert.NoError(t, err)
+		assert.Equal(t, bcrypt.DefaultCost, cost)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	repo := mock.NewMockUserRepository()
+	tokens := auth.NewJWTService("secret", time.Hour)
+	uc := NewAuthUseCase(repo, mock.NewMockResetTokenRepository(), mock.NewMockTxManager(), tokens, mail.NewMemorySender(), 30*time.Minute)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		token, err := tokens.Issue(&entity.User{ID: 1, Username: "UserOne"})
+		assert.NoError(t, err)
+
+		claims, err := uc.Authenticate(ctx, token.Value)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, claims.UserID)
+		assert.True(t, claims.IsLibrarian())
+	})
+	t.Run("Invalid Token", func(t *testing.T) {
+		claims, err := uc.Authenticate(ctx, "invalid")
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+	t.Run("Deleted User", func(t *testing.T) {
+		token, err := tokens.Issue(&entity.User{ID: 10, Username: "Deleted"})
+		assert.NoError(t, err)
+
+		claims, err := uc.Authenticate(ctx, token.Value)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+}
+
+// resetTokenPattern finds the reset token in the body of the email
+var resetTokenPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+func TestForgotPassword(t *testing.T) {
+	sender := mail.NewMemorySender()
+	uc := NewAuthUseCase(mock.NewMockUserRepository(), mock.NewMockResetTokenRepository(), mock.NewMockTxManager(), auth.NewJWTService("secret", time.Hour), sender, 30*time.Minute)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.ForgotPassword(ctx, "two@email.com")
+		assert.NoError(t, err)
+
+		messages := sender.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "two@email.com", messages[0].To)
+		assert.Regexp(t, resetTokenPattern, messages[0].Body)
+	})
+	t.Run("Unknown Email", func(t *testing.T) {
+		err := uc.ForgotPassword(ctx, "nobody@email.com")
+		assert.NoError(t, err)
+		assert.Len(t, sender.Messages(), 1)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	repo := mock.NewMockUserRepository()
+	resets := mock.NewMockResetTokenRepository()
+	sender := mail.NewMemorySender()
+	uc := NewAuthUseCase(repo, resets, mock.NewMockTxManager(), auth.NewJWTService("secret", time.Hour), sender, 30*time.Minute)
+	ctx := context.Background()
+
+	err := uc.ForgotPassword(ctx, "two@email.com")
+	assert.NoError(t, err)
+	token := resetTokenPattern.FindString(sender.Messages()[0].Body)
+
+	t.Run("Invalid Password", func(t *testing.T) {
+		err := uc.ResetPassword(ctx, token, "short")
+		assert.ErrorIs(t, err, entity.ErrShortPassword)
+	})
+	t.Run("OK", func(t *testing.T) {
+		err := uc.ResetPassword(ctx, token, "PasswordNew")
+		assert.NoError(t, err)
+
+		u, err := repo.Get(ctx, 2)
+		assert.NoError(t, err)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("PasswordNew")))
+	})
+	t.Run("Already Used", func(t *testing.T) {
+		err := uc.ResetPassword(ctx, token, "PasswordOther")
+		assert.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+	t.Run("Unknown Token", func(t *testing.T) {
+		err := uc.ResetPassword(ctx, "unknown", "PasswordOther")
+		assert.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		expired, token, err := entity.NewResetToken(2, time.Minute)
+		assert.NoError(t, err)
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		_, err = resets.Create(ctx, expired)
+		assert.NoError(t, err)
+
+		err = uc.ResetPassword(ctx, token, "PasswordOther")
+		assert.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+}
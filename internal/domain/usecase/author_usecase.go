@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type authorUseCase struct {
+	authorRepo r.AuthorRepository
+}
+
+// NewAuthorUseCase creates a new instance of authorUseCase
+func NewAuthorUseCase(repository r.AuthorRepository) u.AuthorUsecase {
+	return &authorUseCase{
+		authorRepo: repository,
+	}
+}
+
+func (s *authorUseCase) GetAuthor(ctx context.Context, id int) (*entity.Author, error) {
+	a, err := s.authorRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (s *authorUseCase) ListAuthors(ctx context.Context) ([]*entity.Author, error) {
+	authors, err := s.authorRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+func (s *authorUseCase) CreateAuthor(ctx context.Context, a *entity.Author) (int, error) {
+	author, err := entity.NewAuthor(a.Name, a.Bio)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.authorRepo.Create(ctx, author)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *authorUseCase) UpdateAuthor(ctx context.Context, a *entity.Author) error {
+	a.Name = strings.TrimSpace(a.Name)
+	a.UpdatedAt = time.Now()
+
+	err := a.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = s.authorRepo.Update(ctx, a)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authorUseCase) DeleteAuthor(ctx context.Context, id int) error {
+	err := s.authorRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+func TestCreateAuthor(t *testing.T) {
+	repo := mock.NewMockAuthorRepository()
+	uc := NewAuthorUseCase(repo)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		id, err := uc.CreateAuthor(ctx, &entity.Author{Name: "  Author Three "})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+
+		a, err := uc.GetAuthor(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Author Three", a.Name)
+	})
+	t.Run("Invalid Author", func(t *testing.T) {
+		_, err := uc.CreateAuthor(ctx, &entity.Author{Name: " "})
+		assert.ErrorIs(t, err, entity.ErrInvalidAuthor)
+	})
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	repo := mock.NewMockAuthorRepository()
+	uc := NewAuthorUseCase(repo)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.UpdateAuthor(ctx, &entity.Author{ID: 2, Name: "Author Two", Bio: "Writes books"})
+		assert.NoError(t, err)
+
+		a, err := uc.GetAuthor(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "Writes books", a.Bio)
+	})
+	t.Run("Invalid Author", func(t *testing.T) {
+		err := uc.UpdateAuthor(ctx, &entity.Author{ID: 2})
+		assert.ErrorIs(t, err, entity.ErrInvalidAuthor)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.UpdateAuthor(ctx, &entity.Author{ID: 5, Name: "Author Five"})
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	repo := mock.NewMockAuthorRepository()
+	uc := NewAuthorUseCase(repo)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.DeleteAuthor(ctx, 2)
+		assert.NoError(t, err)
+
+		authors, err := uc.ListAuthors(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, authors, 1)
+	})
+	t.Run("Has Books", func(t *testing.T) {
+		err := uc.DeleteAuthor(ctx, 1)
+		assert.Error(t, err)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.DeleteAuthor(ctx, 5)
+		assert.Error(t, err)
+	})
+}
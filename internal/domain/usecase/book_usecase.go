@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
@@ -9,14 +10,22 @@ import (
 	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
 )
 
+// exportBatchSize is the number of books read from the catalog at a time
+// during an export, each batch is sent before the next one is read
+const exportBatchSize = 500
+
 type bookUseCase struct {
-	bookRepo r.BookRepository
+	bookRepo    r.BookRepository
+	authorRepo  r.AuthorRepository
+	subjectRepo r.SubjectRepository
 }
 
 // NewBookUseCase creates a new instance of bookUseCase
-func NewBookUseCase(repository r.BookRepository) u.BookUsecase {
+func NewBookUseCase(book r.BookRepository, author r.AuthorRepository, subject r.SubjectRepository) u.BookUsecase {
 	return &bookUseCase{
-		bookRepo: repository,
+		bookRepo:    book,
+		authorRepo:  author,
+		subjectRepo: subject,
 	}
 }
 
@@ -29,30 +38,69 @@ func (s *bookUseCase) GetBook(ctx context.Context, id int) (*entity.Book, error)
 	return book, nil
 }
 
-func (s *bookUseCase) SearchBooks(ctx context.Context, query string) ([]*entity.Book, error) {
-	books, err := s.bookRepo.Search(ctx, query)
+func (s *bookUseCase) SearchBooks(ctx context.Context, search entity.BookSearch) ([]*entity.BookMatch, error) {
+	if search.Limit == 0 {
+		search.Limit = entity.DefaultBookPageSize
+	}
+
+	err := search.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.bookRepo.Search(ctx, search)
 	if err != nil {
 		return nil, err
 	}
 
-	return books, nil
+	return matches, nil
 }
 
-func (s *bookUseCase) ListBooks(ctx context.Context) ([]*entity.Book, error) {
-	books, err := s.bookRepo.List(ctx)
+func (s *bookUseCase) ListBooks(ctx context.Context, q entity.BookQuery) (*entity.BookPage, error) {
+	return listBooks(ctx, s.bookRepo, q)
+}
+
+// listBooks lists a page of books, filling in the default page size and sort
+// of the book listing
+func listBooks(ctx context.Context, repo r.BookRepository, q entity.BookQuery) (*entity.BookPage, error) {
+	if q.Limit == 0 {
+		q.Limit = entity.DefaultBookPageSize
+	}
+	if q.Sort == "" {
+		q.Sort = entity.BookSortTitle
+	}
+
+	err := q.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := repo.List(ctx, q)
 	if err != nil {
 		return nil, err
 	}
 
-	return books, nil
+	return page, nil
 }
 
 func (s *bookUseCase) CreateBook(ctx context.Context, b *entity.Book) (int, error) {
-	book, err := entity.NewBook(b.Title, b.Author, b.Amount)
+	err := s.creditAuthors(ctx, b)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.tagSubjects(ctx, b)
 	if err != nil {
 		return 0, err
 	}
 
+	book, err := entity.NewBook(b.Title, b.Author, b.Amount, b.BookMetadata)
+	if err != nil {
+		return 0, err
+	}
+	book.Authors = b.Authors
+	book.Subjects = b.Subjects
+
 	id, err := s.bookRepo.Create(ctx, book)
 	if err != nil {
 		return 0, err
@@ -61,10 +109,34 @@ func (s *bookUseCase) CreateBook(ctx context.Context, b *entity.Book) (int, erro
 	return id, nil
 }
 
+// UpdateBook updates the catalog data of a book. The amount counts the
+// available copies, it changes through stock adjustments and an amount other
+// than the current one is rejected, a zero amount keeps it
 func (s *bookUseCase) UpdateBook(ctx context.Context, b *entity.Book) error {
+	stored, err := s.bookRepo.Get(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+
+	if b.Amount != 0 && b.Amount != stored.Amount {
+		return ErrAmountChange
+	}
+	b.Amount = stored.Amount
+
 	b.UpdatedAt = time.Now()
+	b.ISBN = entity.NormalizeISBN(b.ISBN)
 
-	err := b.Validate()
+	err = s.creditAuthors(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	err = s.tagSubjects(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	err = b.Validate()
 	if err != nil {
 		return err
 	}
@@ -77,11 +149,192 @@ func (s *bookUseCase) UpdateBook(ctx context.Context, b *entity.Book) error {
 	return nil
 }
 
-func (s *bookUseCase) DeleteBook(ctx context.Context, id int) error {
-	err := s.bookRepo.Delete(ctx, id)
+// PatchBook applies a partial update on the book at the version it was read,
+// the patched book is validated like an update and only the patched fields are written
+func (s *bookUseCase) PatchBook(ctx context.Context, id, version int, p *entity.BookPatch) (*entity.Book, error) {
+	b, err := s.bookRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the patch was made on an older version of the book
+	if b.Version != version {
+		return nil, r.ErrVersionConflict
+	}
+
+	if p.Amount != nil && *p.Amount != b.Amount {
+		return nil, ErrAmountChange
+	}
+
+	p.Apply(b)
+	b.UpdatedAt = time.Now()
+	b.ISBN = entity.NormalizeISBN(b.ISBN)
+
+	err = s.creditAuthors(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// new authors without a byline of their own get the byline of their names,
+	// the stored byline is kept when every author is removed
+	if p.Authors != nil && p.Author == nil && len(b.Authors) > 0 {
+		b.Author = entity.Byline(b.Authors)
+		p.Author = &b.Author
+	}
+
+	err = s.tagSubjects(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.bookRepo.Patch(ctx, b, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// ImportBooks imports the books of a CSV or JSON Lines file, every row is
+// validated like a new book and the valid rows are imported together
+func (s *bookUseCase) ImportBooks(ctx context.Context, format entity.BookImportFormat, r io.Reader) (*entity.BookImportReport, error) {
+	rows, err := entity.ParseBookImport(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var valid []*entity.BookImportRow
+	for i := range rows {
+		row := &rows[i]
+		if row.Status == entity.BookImportRejected {
+			continue
+		}
+
+		// the author and the amount only matter to the rows creating a book,
+		// the repository rejects the ones without them. An exported book has
+		// no amount when all its copies are out
+		author, amount := row.Book.Author, row.Book.Amount
+		if row.Fields.Author == nil {
+			author = "unknown"
+		}
+		if amount == 0 {
+			amount = 1
+		}
+
+		book, err := entity.NewBook(row.Book.Title, author, amount, row.Book.BookMetadata)
+		if err != nil {
+			row.Reject(err)
+			continue
+		}
+		book.Author, book.Amount = row.Book.Author, row.Book.Amount
+
+		row.Book = book
+		valid = append(valid, row)
+	}
+
+	err = s.bookRepo.Import(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.BookImportReport{}
+	for _, row := range rows {
+		report.Add(row)
+	}
+
+	return report, nil
+}
+
+// ExportBooks writes the whole catalog to w in the format, walking the books
+// in id order a batch at a time so the catalog is never held in memory. It
+// returns how many books were written, also when the export fails midway
+func (s *bookUseCase) ExportBooks(ctx context.Context, format entity.BookExportFormat, w io.Writer) (int, error) {
+	exporter, err := entity.NewBookExporter(w, format)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	afterID := 0
+	for {
+		books, err := s.bookRepo.Export(ctx, afterID, exportBatchSize)
+		if err != nil {
+			return n, err
+		}
+
+		for _, b := range books {
+			if err := exporter.Write(b); err != nil {
+				return n, err
+			}
+			n++
+		}
+
+		if len(books) < exportBatchSize {
+			break
+		}
+
+		if err := exporter.Flush(); err != nil {
+			return n, err
+		}
+		afterID = books[len(books)-1].ID
+	}
+
+	return n, exporter.Close()
+}
+
+func (s *bookUseCase) DeleteBook(ctx context.Context, id, version int) error {
+	err := s.bookRepo.Delete(ctx, id, version)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// creditAuthors checks the authors of the book exist and fills in their names,
+// the byline defaults to the names of the authors
+func (s *bookUseCase) creditAuthors(ctx context.Context, b *entity.Book) error {
+	err := entity.ValidateBookAuthors(b.Authors)
 	if err != nil {
 		return err
 	}
 
+	for i := range b.Authors {
+		a, err := s.authorRepo.Get(ctx, b.Authors[i].AuthorID)
+		if err != nil {
+			return err
+		}
+
+		b.Authors[i].Name = a.Name
+	}
+
+	if b.Author == "" {
+		b.Author = entity.Byline(b.Authors)
+	}
+
+	return nil
+}
+
+// tagSubjects checks the subjects of the book exist and fills them in
+func (s *bookUseCase) tagSubjects(ctx context.Context, b *entity.Book) error {
+	err := entity.ValidateBookSubjects(b.Subjects)
+	if err != nil {
+		return err
+	}
+
+	for i := range b.Subjects {
+		subject, err := s.subjectRepo.Get(ctx, b.Subjects[i].ID)
+		if err != nil {
+			return err
+		}
+
+		b.Subjects[i] = *subject
+	}
+
 	return nil
 }
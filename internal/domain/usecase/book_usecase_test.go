@@ -1,18 +1,21 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 func TestGetBook(t *testing.T) {
 	repo := mock.NewMockBookRepository()
-	uc := NewBookUseCase(repo)
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
@@ -28,38 +31,92 @@ func TestGetBook(t *testing.T) {
 
 func TestSearchBooks(t *testing.T) {
 	repo := mock.NewMockBookRepository()
-	uc := NewBookUseCase(repo)
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
-		b, err := uc.SearchBooks(ctx, "two")
+		m, err := uc.SearchBooks(ctx, entity.BookSearch{Query: "two"})
 		assert.NoError(t, err)
-		assert.Equal(t, "Book Two", b[0].Title)
+		assert.Equal(t, "Book Two", m[0].Book.Title)
+	})
+	t.Run("Every Word Prefix", func(t *testing.T) {
+		m, err := uc.SearchBooks(ctx, entity.BookSearch{Query: "Auth On"})
+		assert.NoError(t, err)
+		assert.Len(t, m, 1)
+		assert.Equal(t, "Book One", m[0].Book.Title)
 	})
 	t.Run("Not Found", func(t *testing.T) {
-		_, err := uc.SearchBooks(ctx, "five")
+		_, err := uc.SearchBooks(ctx, entity.BookSearch{Query: "five"})
 		assert.Error(t, err)
 	})
+	t.Run("Invalid Search", func(t *testing.T) {
+		_, err := uc.SearchBooks(ctx, entity.BookSearch{Query: "?!"})
+		assert.ErrorIs(t, err, entity.ErrInvalidBookSearch)
+	})
 }
 
 func TestListBooks(t *testing.T) {
 	repo := mock.NewMockBookRepository()
-	uc := NewBookUseCase(repo)
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
-		b, err := uc.ListBooks(ctx)
+		page, err := uc.ListBooks(ctx, entity.BookQuery{})
 		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
 
-		for _, book := range b {
+		for _, book := range page.Books {
 			assert.NotEmpty(t, book)
 		}
 	})
+	t.Run("Next Page", func(t *testing.T) {
+		page, err := uc.ListBooks(ctx, entity.BookQuery{Sort: "-title", Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "Book Two", page.Books[0].Title)
+		assert.NotEmpty(t, page.NextCursor)
+
+		page, err = uc.ListBooks(ctx, entity.BookQuery{Sort: "-title", Limit: 1, Cursor: page.NextCursor})
+		assert.NoError(t, err)
+		assert.Equal(t, "Book One", page.Books[0].Title)
+		assert.Empty(t, page.NextCursor)
+	})
+	t.Run("Search Title Or Author", func(t *testing.T) {
+		page, err := uc.ListBooks(ctx, entity.BookQuery{Query: "author two"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, "Book Two", page.Books[0].Title)
+
+		page, err = uc.ListBooks(ctx, entity.BookQuery{Title: "book", Author: "one"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, "Book One", page.Books[0].Title)
+	})
+	t.Run("Author ID", func(t *testing.T) {
+		page, err := uc.ListBooks(ctx, entity.BookQuery{AuthorID: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, "Book Two", page.Books[0].Title)
+	})
+	t.Run("Subject ID", func(t *testing.T) {
+		page, err := uc.ListBooks(ctx, entity.BookQuery{SubjectID: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, "Book One", page.Books[0].Title)
+	})
+	t.Run("Available Only", func(t *testing.T) {
+		page, err := uc.ListBooks(ctx, entity.BookQuery{Available: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+	})
+	t.Run("Invalid Query", func(t *testing.T) {
+		_, err := uc.ListBooks(ctx, entity.BookQuery{Sort: "amount"})
+		assert.ErrorIs(t, err, entity.ErrInvalidBookQuery)
+	})
 }
 
 func TestCreateBook(t *testing.T) {
 	repo := mock.NewMockBookRepository()
-	uc := NewBookUseCase(repo)
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
@@ -73,6 +130,81 @@ func TestCreateBook(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 3, id)
 	})
+	t.Run("Authors", func(t *testing.T) {
+		b := &entity.Book{
+			Title:  "Book Four",
+			Amount: 1,
+			Authors: []entity.BookAuthor{
+				{AuthorID: 2, Role: entity.AuthorRoleAuthor},
+				{AuthorID: 1, Role: entity.AuthorRoleTranslator},
+			},
+		}
+
+		id, err := uc.CreateBook(ctx, b)
+		assert.NoError(t, err)
+
+		got, err := uc.GetBook(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Author Two", got.Author)
+		assert.Equal(t, "Author One", got.Authors[1].Name)
+	})
+	t.Run("Author Not Found", func(t *testing.T) {
+		b := &entity.Book{
+			Title:   "Book Five",
+			Amount:  1,
+			Authors: []entity.BookAuthor{{AuthorID: 5, Role: entity.AuthorRoleAuthor}},
+		}
+
+		_, err := uc.CreateBook(ctx, b)
+		assert.Error(t, err)
+	})
+	t.Run("Subjects", func(t *testing.T) {
+		b := &entity.Book{
+			Title:    "Book Six",
+			Author:   "Author One",
+			Amount:   1,
+			Subjects: []entity.Subject{{ID: 1}, {ID: 2}},
+		}
+
+		id, err := uc.CreateBook(ctx, b)
+		assert.NoError(t, err)
+
+		got, err := uc.GetBook(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Subject Two", got.Subjects[1].Name)
+	})
+	t.Run("Subject Not Found", func(t *testing.T) {
+		b := &entity.Book{
+			Title:    "Book Five",
+			Author:   "Author One",
+			Amount:   1,
+			Subjects: []entity.Subject{{ID: 5}},
+		}
+
+		_, err := uc.CreateBook(ctx, b)
+		assert.Error(t, err)
+	})
+	t.Run("Duplicated Subject", func(t *testing.T) {
+		b := &entity.Book{
+			Title:    "Book Five",
+			Author:   "Author One",
+			Amount:   1,
+			Subjects: []entity.Subject{{ID: 1}, {ID: 1}},
+		}
+
+		_, err := uc.CreateBook(ctx, b)
+		assert.ErrorIs(t, err, entity.ErrInvalidBookSubjects)
+	})
+	t.Run("Invalid Author Role", func(t *testing.T) {
+		b := &entity.Book{
+			Title:   "Book Five",
+			Amount:  1,
+			Authors: []entity.BookAuthor{{AuthorID: 1, Role: "illustrator"}},
+		}
+
+		_, err := uc.CreateBook(ctx, b)
+		assert.ErrorIs(t, err, entity.ErrInvalidBookAuthors)
+	})
 	t.Run("Invalid Book", func(t *testing.T) {
 		b := &entity.Book{}
 
@@ -83,19 +215,77 @@ func TestCreateBook(t *testing.T) {
 
 func TestUpdateBook(t *testing.T) {
 	repo := mock.NewMockBookRepository()
-	uc := NewBookUseCase(repo)
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
 		b := &entity.Book{
-			ID:     2,
-			Title:  "Book Three",
-			Author: "Author Three",
+			ID:      2,
+			Title:   "Book Three",
+			Author:  "Author Three",
+			Version: 1,
+		}
+
+		err := uc.UpdateBook(ctx, b)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, b.Amount, "the amount is kept")
+		assert.Equal(t, 2, b.Version)
+	})
+	t.Run("Amount Change", func(t *testing.T) {
+		b := &entity.Book{
+			ID:     1,
+			Title:  "Book One",
+			Author: "Author One",
 			Amount: 5,
 		}
 
 		err := uc.UpdateBook(ctx, b)
+		assert.Equal(t, ErrAmountChange, err)
+
+		got, err := uc.GetBook(ctx, 1)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, got.Amount)
+	})
+	t.Run("Normalized ISBN", func(t *testing.T) {
+		b := &entity.Book{
+			ID:           1,
+			Title:        "Book One",
+			Author:       "Author One",
+			Amount:       2,
+			BookMetadata: entity.BookMetadata{ISBN: "0-8044-2957-x"},
+			Version:      1,
+		}
+
+		err := uc.UpdateBook(ctx, b)
+		assert.NoError(t, err)
+
+		got, err := uc.GetBook(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "9780804429573", got.ISBN)
+		assert.Len(t, got.Authors, 1, "the authors are kept when the book has none")
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		b := &entity.Book{
+			ID:      1,
+			Title:   "Book One",
+			Author:  "Author One",
+			Version: 1,
+		}
+
+		err := uc.UpdateBook(ctx, b)
+		assert.Equal(t, repoErr.ErrVersionConflict, err)
+	})
+	t.Run("Invalid ISBN", func(t *testing.T) {
+		b := &entity.Book{
+			ID:           1,
+			Title:        "Book One",
+			Author:       "Author One",
+			Amount:       2,
+			BookMetadata: entity.BookMetadata{ISBN: "0-8044-2957-1"},
+		}
+
+		err := uc.UpdateBook(ctx, b)
+		assert.ErrorIs(t, err, entity.ErrInvalidISBN)
 	})
 	t.Run("Invalid Book", func(t *testing.T) {
 		b := &entity.Book{}
@@ -116,18 +306,209 @@ func TestUpdateBook(t *testing.T) {
 	})
 }
 
+func TestPatchBook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		title := "Book One Revised"
+		isbn := "0-8044-2957-x"
+
+		b, err := uc.PatchBook(ctx, 1, 1, &entity.BookPatch{Title: &title, ISBN: &isbn})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, b.Version)
+
+		got, err := uc.GetBook(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, title, got.Title)
+		assert.Equal(t, "9780804429573", got.ISBN)
+		assert.Equal(t, "Author One", got.Author, "the fields left out are kept")
+		assert.Equal(t, 2, got.Amount)
+		assert.Len(t, got.Authors, 1)
+	})
+	t.Run("Cleared Author", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		author := ""
+
+		b, err := uc.PatchBook(ctx, 1, 1, &entity.BookPatch{Author: &author})
+		assert.NoError(t, err)
+		assert.Equal(t, "Author One", b.Author, "the byline follows the authors")
+	})
+	t.Run("New Authors", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		authors := []entity.BookAuthor{{AuthorID: 2, Role: entity.AuthorRoleAuthor}}
+		p := &entity.BookPatch{Authors: &authors}
+
+		b, err := uc.PatchBook(ctx, 1, 1, p)
+		assert.NoError(t, err)
+		assert.Equal(t, "Author Two", b.Author, "the byline follows the new authors")
+		if assert.NotNil(t, p.Author, "the byline is written with the authors") {
+			assert.Equal(t, "Author Two", *p.Author)
+		}
+	})
+	t.Run("New Authors With Byline", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		author := "A. Two"
+		authors := []entity.BookAuthor{{AuthorID: 2, Role: entity.AuthorRoleAuthor}}
+
+		b, err := uc.PatchBook(ctx, 1, 1, &entity.BookPatch{Author: &author, Authors: &authors})
+		assert.NoError(t, err)
+		assert.Equal(t, author, b.Author)
+	})
+	t.Run("Cleared Title", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		title := ""
+
+		_, err := uc.PatchBook(ctx, 1, 1, &entity.BookPatch{Title: &title})
+		assert.Equal(t, entity.ErrInvalidBook, err)
+	})
+	t.Run("Amount Change", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		amount := 5
+
+		_, err := uc.PatchBook(ctx, 1, 1, &entity.BookPatch{Amount: &amount})
+		assert.Equal(t, ErrAmountChange, err)
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		_, err := uc.PatchBook(ctx, 1, 2, &entity.BookPatch{})
+		assert.Equal(t, repoErr.ErrVersionConflict, err)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		uc := NewBookUseCase(mock.NewMockBookRepository(), mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+
+		_, err := uc.PatchBook(ctx, 5, 1, &entity.BookPatch{})
+		assert.Equal(t, repoErr.ErrBookNotFound, err)
+	})
+}
+
+func TestImportBooks(t *testing.T) {
+	repo := mock.NewMockBookRepository()
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		file := "title,author,amount,isbn,publisher\n" +
+			"Book Three,Author Three,2,978-1-61729-953-7,Publisher Three\n" +
+			",Author Four,1,,\n" +
+			"Book Three Revised,Author Three,1,9781617299537,Publisher Three\n" +
+			"Book Five,Author Five,0,,\n" +
+			"Book Six,Author Six,,,\n"
+
+		report, err := uc.ImportBooks(ctx, entity.BookImportCSV, strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 3, report.Rejected)
+
+		assert.Equal(t, entity.BookImportCreated, report.Rows[0].Status)
+		assert.Equal(t, entity.BookImportRejected, report.Rows[1].Status)
+		assert.Equal(t, entity.ErrInvalidBook, report.Rows[1].Err)
+		assert.Equal(t, entity.BookImportUpdated, report.Rows[2].Status)
+		assert.Equal(t, report.Rows[0].Book.ID, report.Rows[2].Book.ID)
+		assert.Equal(t, repoErr.ErrNewBookIncomplete, report.Rows[4].Err, "new books need an author and an amount")
+
+		got, err := uc.GetBook(ctx, report.Rows[0].Book.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Book Three Revised", got.Title)
+		assert.Equal(t, 2, got.Amount, "updated books keep their copies")
+	})
+	t.Run("Supplied Columns", func(t *testing.T) {
+		file := "isbn,title\n" +
+			"9781617299537,Book Three Second Edition\n"
+
+		report, err := uc.ImportBooks(ctx, entity.BookImportCSV, strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+
+		got, err := uc.GetBook(ctx, report.Rows[0].Book.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Book Three Second Edition", got.Title)
+		assert.Equal(t, "Author Three", got.Author, "the columns missing from the file are kept")
+		assert.Equal(t, "Publisher Three", got.Publisher)
+		assert.Equal(t, 2, got.Amount)
+	})
+	t.Run("Invalid Import", func(t *testing.T) {
+		_, err := uc.ImportBooks(ctx, entity.BookImportCSV, strings.NewReader("name\nBook Six\n"))
+		assert.ErrorIs(t, err, entity.ErrInvalidBookImport)
+	})
+}
+
+func TestExportBooks(t *testing.T) {
+	repo := mock.NewMockBookRepository()
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		n, err := uc.ExportBooks(ctx, entity.BookExportCSV, &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[1], "1,Book One,"))
+		assert.True(t, strings.HasPrefix(lines[2], "2,Book Two,"))
+	})
+	t.Run("Imported Back", func(t *testing.T) {
+		// the exported books are matched back by their ISBN, book 2 has no copy on the shelves
+		for id, isbn := range map[int]string{1: "9781617299537", 2: "9781718500884"} {
+			b, err := repo.Get(ctx, id)
+			assert.NoError(t, err)
+			b.ISBN = isbn
+		}
+
+		formats := map[entity.BookExportFormat]entity.BookImportFormat{
+			entity.BookExportCSV:   entity.BookImportCSV,
+			entity.BookExportJSONL: entity.BookImportJSONL,
+		}
+		for exportFormat, importFormat := range formats {
+			var buf bytes.Buffer
+
+			_, err := uc.ExportBooks(ctx, exportFormat, &buf)
+			assert.NoError(t, err)
+
+			report, err := uc.ImportBooks(ctx, importFormat, &buf)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, report.Updated, exportFormat)
+			assert.Zero(t, report.Rejected, exportFormat)
+		}
+
+		got, err := uc.GetBook(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "Book Two", got.Title)
+		assert.Equal(t, "Author Two", got.Author)
+	})
+	t.Run("Invalid Format", func(t *testing.T) {
+		_, err := uc.ExportBooks(ctx, "xlsx", &bytes.Buffer{})
+		assert.Equal(t, entity.ErrInvalidBookExport, err)
+	})
+}
+
 func TestDeleteBook(t *testing.T) {
 	repo := mock.NewMockBookRepository()
-	uc := NewBookUseCase(repo)
+	uc := NewBookUseCase(repo, mock.NewMockAuthorRepository(), mock.NewMockSubjectRepository())
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
-		err := uc.DeleteBook(ctx, 2)
+		err := uc.DeleteBook(ctx, 2, 1)
 		assert.NoError(t, err)
 	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		err := uc.DeleteBook(ctx, 1, 2)
+		assert.Equal(t, repoErr.ErrVersionConflict, err)
+	})
 
 	t.Run("Not Found", func(t *testing.T) {
-		err := uc.DeleteBook(ctx, 5)
+		err := uc.DeleteBook(ctx, 5, 1)
 		assert.Error(t, err)
 	})
 }
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+// reasons recorded with the movements of the single copy endpoints
+const (
+	reasonCopyAdded   = "copy added"
+	reasonCopyStatus  = "copy status changed"
+	reasonCopyDeleted = "copy deleted"
+)
+
+type copyUseCase struct {
+	copyRepo  r.CopyRepository
+	bookRepo  r.BookRepository
+	holdRepo  r.HoldRepository
+	txManager r.TxManager
+	policy    LoanPolicy
+}
+
+// NewCopyUseCase creates a new instance of copyUseCase
+func NewCopyUseCase(cp r.CopyRepository, book r.BookRepository, hold r.HoldRepository, tx r.TxManager, policy LoanPolicy) u.CopyUsecase {
+	return &copyUseCase{
+		copyRepo:  cp,
+		bookRepo:  book,
+		holdRepo:  hold,
+		txManager: tx,
+		policy:    policy,
+	}
+}
+
+func (s *copyUseCase) GetCopy(ctx context.Context, id int) (*entity.Copy, error) {
+	c, err := s.copyRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s *copyUseCase) ListBookCopies(ctx context.Context, bookID int) ([]*entity.Copy, error) {
+	_, err := s.bookRepo.Get(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := s.copyRepo.ListByBook(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return copies, nil
+}
+
+// AddCopy adds a copy to the shelves and records it as an acquisition of the librarian
+func (s *copyUseCase) AddCopy(ctx context.Context, c *entity.Copy, userID int) (int, error) {
+	_, err := s.bookRepo.Get(ctx, c.BookID)
+	if err != nil {
+		return 0, err
+	}
+
+	newCopy, err := entity.NewCopy(c.BookID, c.Barcode, c.Location)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.copyRepo.Create(ctx, newCopy)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordMovement(ctx, newCopy, userID, entity.MovementAcquired, reasonCopyAdded)
+		if err != nil {
+			return err
+		}
+
+		return s.allocateHolds(ctx, newCopy.BookID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return newCopy.ID, nil
+}
+
+// UpdateCopy updates a copy, a status change is recorded as a movement of the librarian
+func (s *copyUseCase) UpdateCopy(ctx context.Context, c *entity.Copy, userID int) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		stored, err := s.copyRepo.Get(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+
+		// copies only go on and off loan by borrowing and returning them
+		if (stored.Status == entity.CopyOnLoan) != (c.Status == entity.CopyOnLoan) {
+			return ErrCopyOnLoan
+		}
+
+		c.BookID = stored.BookID
+
+		err = c.Validate()
+		if err != nil {
+			return err
+		}
+
+		if stored.Status == entity.CopyAvailable && c.Status != entity.CopyAvailable {
+			err = s.keepReserved(ctx, c.BookID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.copyRepo.Update(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		if stored.Status == c.Status {
+			return nil
+		}
+
+		if kind, ok := entity.MovementTo(c.Status); ok {
+			err = s.recordMovement(ctx, c, userID, kind, reasonCopyStatus)
+			if err != nil {
+				return err
+			}
+		}
+
+		// a repaired or found copy goes to the hold queue first
+		if c.Status == entity.CopyAvailable {
+			return s.allocateHolds(ctx, c.BookID)
+		}
+
+		return nil
+	})
+}
+
+// DeleteCopy deletes a copy and records it as withdrawn by the librarian
+func (s *copyUseCase) DeleteCopy(ctx context.Context, id, userID int) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		c, err := s.copyRepo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if c.Status == entity.CopyOnLoan {
+			return ErrCopyOnLoan
+		}
+
+		if c.Status == entity.CopyAvailable {
+			err = s.keepReserved(ctx, c.BookID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.copyRepo.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.recordMovement(ctx, c, userID, entity.MovementWithdrawn, reasonCopyDeleted)
+	})
+}
+
+// AdjustStock adds, moves or withdraws copies of a book and records the
+// adjustment, acquired and restored copies go to the hold queue first
+func (s *copyUseCase) AdjustStock(ctx context.Context, m *entity.InventoryMovement) error {
+	_, err := s.bookRepo.Get(ctx, m.BookID)
+	if err != nil {
+		return err
+	}
+
+	movement, err := entity.NewInventoryMovement(m.BookID, m.UserID, m.Kind, m.Quantity, m.Reason)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.copyRepo.Adjust(ctx, movement)
+		if err != nil {
+			return err
+		}
+
+		if movement.Kind == entity.MovementAcquired || movement.Kind == entity.MovementRestored {
+			return s.allocateHolds(ctx, movement.BookID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*m = *movement
+
+	return nil
+}
+
+func (s *copyUseCase) ListStockMovements(ctx context.Context, bookID int) ([]*entity.InventoryMovement, error) {
+	_, err := s.bookRepo.Get(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.copyRepo.ListMovements(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// allocateHolds sets the available copies of the book aside for its hold queue
+func (s *copyUseCase) allocateHolds(ctx context.Context, bookID int) error {
+	book, err := s.bookRepo.Get(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	_, err = allocateHolds(ctx, s.holdRepo, book, s.policy.HoldExpiry)
+	return err
+}
+
+// keepReserved fails when taking an available copy of the book off the shelves
+// leaves fewer copies than the ready holds set aside, like the stock
+// adjustments do. The hold queue stays locked until the transaction ends
+func (s *copyUseCase) keepReserved(ctx context.Context, bookID int) error {
+	queue, err := s.holdRepo.ListActiveForUpdate(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	book, err := s.bookRepo.Get(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	var reserved int
+	for _, h := range queue {
+		if h.Status == entity.HoldReady {
+			reserved++
+		}
+	}
+
+	if book.Amount-1 < reserved {
+		return r.ErrNotEnoughCopies
+	}
+
+	return nil
+}
+
+// recordMovement records the change of a single copy made by the librarian
+func (s *copyUseCase) recordMovement(ctx context.Context, c *entity.Copy, userID int, kind entity.MovementKind, reason string) error {
+	m, err := entity.NewInventoryMovement(c.BookID, userID, kind, 1, reason)
+	if err != nil {
+		return err
+	}
+	m.Barcodes = []string{c.Barcode}
+
+	return s.copyRepo.RecordMovement(ctx, m)
+}
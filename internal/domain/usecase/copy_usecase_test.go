@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestAddCopy(t *testing.T) {
+	repoC := mock.NewMockCopyRepository()
+	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+
+	uc := NewCopyUseCase(repoC, repoB, repoH, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		id, err := uc.AddCopy(ctx, &entity.Copy{BookID: 1, Barcode: "B1-3", Location: "A3"}, 1)
+		assert.NoError(t, err)
+
+		c, err := uc.GetCopy(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.CopyAvailable, c.Status)
+
+		movements, err := uc.ListStockMovements(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, movements, 1)
+		assert.Equal(t, entity.MovementAcquired, movements[0].Kind)
+		assert.Equal(t, []string{"B1-3"}, movements[0].Barcodes)
+		assert.Equal(t, 1, movements[0].UserID)
+	})
+	t.Run("Barcode Already Exists", func(t *testing.T) {
+		_, err := uc.AddCopy(ctx, &entity.Copy{BookID: 1, Barcode: "B1-1"}, 1)
+		assert.Error(t, err)
+	})
+	t.Run("Invalid Barcode", func(t *testing.T) {
+		_, err := uc.AddCopy(ctx, &entity.Copy{BookID: 1, Barcode: "B1 4"}, 1)
+		assert.ErrorIs(t, err, entity.ErrInvalidCopy)
+	})
+	t.Run("Book Not Found", func(t *testing.T) {
+		_, err := uc.AddCopy(ctx, &entity.Copy{BookID: 5, Barcode: "B5-1"}, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateCopy(t *testing.T) {
+	repoC := mock.NewMockCopyRepository()
+	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+
+	uc := NewCopyUseCase(repoC, repoB, repoH, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 1, Barcode: "B1-1", Status: entity.CopyInRepair, Location: "Bindery"}, 1)
+		assert.NoError(t, err)
+
+		c, err := uc.GetCopy(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.CopyInRepair, c.Status)
+		assert.Equal(t, 1, c.BookID)
+
+		movements, err := uc.ListStockMovements(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, movements, 1)
+		assert.Equal(t, entity.MovementDamaged, movements[0].Kind)
+	})
+	t.Run("Restored", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 1, Barcode: "B1-1", Status: entity.CopyAvailable, Location: "A1"}, 1)
+		assert.NoError(t, err)
+
+		movements, err := uc.ListStockMovements(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, movements, 2)
+		assert.Equal(t, entity.MovementRestored, movements[0].Kind)
+	})
+	t.Run("Same Status", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 1, Barcode: "B1-1", Status: entity.CopyAvailable, Location: "A2"}, 1)
+		assert.NoError(t, err)
+
+		movements, err := uc.ListStockMovements(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, movements, 2, "moving a copy on the shelves is not a movement")
+	})
+	t.Run("Put On Loan", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 2, Barcode: "B1-2", Status: entity.CopyOnLoan}, 1)
+		assert.ErrorIs(t, err, ErrCopyOnLoan)
+	})
+	t.Run("Copy On Loan", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 3, Barcode: "B2-1", Status: entity.CopyAvailable}, 1)
+		assert.ErrorIs(t, err, ErrCopyOnLoan)
+	})
+	t.Run("Invalid Status", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 2, Barcode: "B1-2", Status: "stolen"}, 1)
+		assert.ErrorIs(t, err, entity.ErrInvalidCopyStatus)
+	})
+	t.Run("Copy Reserved", func(t *testing.T) {
+		// both copies of book 1 are set aside for ready holds
+		for _, userID := range []int{1, 2} {
+			_, err := repoH.Create(ctx, &entity.Hold{UserID: userID, BookID: 1, Status: entity.HoldReady, ExpiresAt: time.Now().Add(policy.HoldExpiry)})
+			assert.NoError(t, err)
+		}
+
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 2, Barcode: "B1-2", Status: entity.CopyLost}, 1)
+		assert.ErrorIs(t, err, repoErr.ErrNotEnoughCopies)
+
+		c, err := uc.GetCopy(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.CopyAvailable, c.Status)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.UpdateCopy(ctx, &entity.Copy{ID: 10, Barcode: "B1-9", Status: entity.CopyLost}, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteCopy(t *testing.T) {
+	repoC := mock.NewMockCopyRepository()
+	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+
+	uc := NewCopyUseCase(repoC, repoB, repoH, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.DeleteCopy(ctx, 2, 1)
+		assert.NoError(t, err)
+
+		copies, err := uc.ListBookCopies(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, copies, 1)
+
+		movements, err := uc.ListStockMovements(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, movements, 1)
+		assert.Equal(t, entity.MovementWithdrawn, movements[0].Kind)
+		assert.Equal(t, []string{"B1-2"}, movements[0].Barcodes)
+	})
+	t.Run("Copy On Loan", func(t *testing.T) {
+		err := uc.DeleteCopy(ctx, 3, 1)
+		assert.ErrorIs(t, err, ErrCopyOnLoan)
+	})
+	t.Run("Copy Reserved", func(t *testing.T) {
+		// both copies of book 1 are set aside for ready holds
+		for _, userID := range []int{1, 2} {
+			_, err := repoH.Create(ctx, &entity.Hold{UserID: userID, BookID: 1, Status: entity.HoldReady, ExpiresAt: time.Now().Add(policy.HoldExpiry)})
+			assert.NoError(t, err)
+		}
+
+		err := uc.DeleteCopy(ctx, 1, 1)
+		assert.ErrorIs(t, err, repoErr.ErrNotEnoughCopies)
+
+		copies, err := uc.ListBookCopies(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, copies, 1)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.DeleteCopy(ctx, 10, 1)
+		assert.Error(t, err)
+	})
+}
@@ -4,8 +4,24 @@ import "errors"
 
 // Use Case Errors
 var (
-	ErrBookNotFound        = errors.New("book not found")
-	ErrBookUnavailable     = errors.New("book unavailable at the moment")
-	ErrLoanAlreadyReturned = errors.New("loan does't exists or already returned")
-	ErrReturnBookFirst     = errors.New("return the book first before borrowing it again")
+	ErrBookNotFound          = errors.New("book not found")
+	ErrBookUnavailable       = errors.New("book unavailable at the moment")
+	ErrLoanAlreadyReturned   = errors.New("loan does't exists or already returned")
+	ErrReturnBookFirst       = errors.New("return the book first before borrowing it again")
+	ErrLoanOverdue           = errors.New("overdue loans can't be renewed")
+	ErrRenewalLimitReached   = errors.New("loan reached the maximum number of renewals")
+	ErrBookReserved          = errors.New("the available copies are reserved for patrons on the hold queue")
+	ErrBookAvailable         = errors.New("the book is available, borrow it instead of placing a hold")
+	ErrHoldAlreadyPlaced     = errors.New("user already has an active hold for this book")
+	ErrHoldNotActive         = errors.New("hold was already fulfilled, cancelled or expired")
+	ErrOutstandingBalance    = errors.New("user owes more than the allowed balance")
+	ErrPaymentExceedsDebt    = errors.New("payment is larger than the user balance")
+	ErrLoanLimitReached      = errors.New("user has reached the maximum number of loans")
+	ErrAmountChange          = errors.New("the amount counts the available copies, it changes through stock adjustments")
+	ErrCopyOnLoan            = errors.New("copies on loan change status only when borrowed or returned")
+	ErrParentSubjectNotFound = errors.New("parent subject not found")
+	ErrSubjectCycle          = errors.New("a subject can't be moved below itself")
+	ErrInvalidCredentials    = errors.New("invalid username or password")
+	ErrWrongPassword         = errors.New("current password is incorrect")
+	ErrInvalidResetToken     = errors.New("reset token is invalid, expired or already used")
 )
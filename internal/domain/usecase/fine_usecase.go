@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type fineUseCase struct {
+	ledgerRepo r.LedgerRepository
+	userRepo   r.UserRepository
+	txManager  r.TxManager
+}
+
+// NewFineUseCase creates a new instance of fineUseCase
+func NewFineUseCase(ledger r.LedgerRepository, user r.UserRepository, tx r.TxManager) u.FineUsecase {
+	return &fineUseCase{
+		ledgerRepo: ledger,
+		userRepo:   user,
+		txManager:  tx,
+	}
+}
+
+func (s *fineUseCase) GetBalance(ctx context.Context, userID int) (*entity.Balance, error) {
+	user, err := s.userRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ledgerRepo.List(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &entity.Balance{
+		UserID:  user.ID,
+		Entries: entries,
+	}
+
+	for _, e := range entries {
+		balance.Amount += e.Signed()
+	}
+
+	return balance, nil
+}
+
+func (s *fineUseCase) PayFine(ctx context.Context, userID, amount int, note string) (int, error) {
+	var id int
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = s.payFine(ctx, userID, amount, note)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// payFine records a payment of the patron when it doesn't exceed the balance,
+// the balance is read and the payment written in the same transaction
+func (s *fineUseCase) payFine(ctx context.Context, userID, amount int, note string) (int, error) {
+	// the patron row is locked first so concurrent payments of the same patron
+	// wait here and see each other's payments in the balance below
+	user, err := s.userRepo.GetForUpdate(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	payment, err := entity.NewPayment(user.ID, amount, note)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, err := s.ledgerRepo.Balance(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if payment.Amount > balance {
+		return 0, ErrPaymentExceedsDebt
+	}
+
+	id, err := s.ledgerRepo.Create(ctx, payment)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+func TestGetBalance(t *testing.T) {
+	repoF := mock.NewMockLedgerRepository()
+	repoU := mock.NewMockUserRepository()
+
+	uc := NewFineUseCase(repoF, repoU, mock.NewMockTxManager())
+	ctx := context.Background()
+
+	fine, err := entity.NewFine(1, 1, 300, "returned 12 day(s) late")
+	assert.NoError(t, err)
+	_, err = repoF.Create(ctx, fine)
+	assert.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		balance, err := uc.GetBalance(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 300, balance.Amount)
+		assert.Len(t, balance.Entries, 1)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		balance, err := uc.GetBalance(ctx, 2)
+		assert.NoError(t, err)
+		assert.Zero(t, balance.Amount)
+		assert.Empty(t, balance.Entries)
+	})
+	t.Run("User Not Found", func(t *testing.T) {
+		balance, err := uc.GetBalance(ctx, 5)
+		assert.Error(t, err)
+		assert.Nil(t, balance)
+	})
+}
+
+func TestPayFine(t *testing.T) {
+	repoF := mock.NewMockLedgerRepository()
+	repoU := mock.NewMockUserRepository()
+
+	uc := NewFineUseCase(repoF, repoU, mock.NewMockTxManager())
+	ctx := context.Background()
+
+	fine, err := entity.NewFine(1, 1, 300, "returned 12 day(s) late")
+	assert.NoError(t, err)
+	_, err = repoF.Create(ctx, fine)
+	assert.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		id, err := uc.PayFine(ctx, 1, 200, "cash")
+		assert.NoError(t, err)
+		assert.NotZero(t, id)
+
+		balance, err := uc.GetBalance(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 100, balance.Amount)
+	})
+	t.Run("Exceeds Balance", func(t *testing.T) {
+		_, err := uc.PayFine(ctx, 1, 200, "cash")
+		assert.ErrorIs(t, err, ErrPaymentExceedsDebt)
+	})
+	t.Run("Invalid Amount", func(t *testing.T) {
+		_, err := uc.PayFine(ctx, 1, 0, "cash")
+		assert.ErrorIs(t, err, entity.ErrInvalidAmount)
+	})
+	t.Run("User Not Found", func(t *testing.T) {
+		_, err := uc.PayFine(ctx, 5, 100, "cash")
+		assert.Error(t, err)
+	})
+}
+
+// serialTxManager runs one transaction at a time, like concurrent payments of
+// the same patron waiting on the locked patron row
+type serialTxManager struct {
+	mu sync.Mutex
+}
+
+func (m *serialTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return fn(ctx)
+}
+
+func TestPayFineConcurrently(t *testing.T) {
+	repoF := mock.NewMockLedgerRepository()
+	repoU := mock.NewMockUserRepository()
+
+	uc := NewFineUseCase(repoF, repoU, &serialTxManager{})
+	ctx := context.Background()
+
+	fine, err := entity.NewFine(1, 1, 300, "returned 12 day(s) late")
+	assert.NoError(t, err)
+	_, err = repoF.Create(ctx, fine)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = uc.PayFine(ctx, 1, 100, "cash")
+		}(i)
+	}
+	wg.Wait()
+
+	paid := 0
+	for _, err := range errs {
+		if err == nil {
+			paid++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrPaymentExceedsDebt)
+	}
+	assert.Equal(t, 3, paid)
+
+	balance, err := uc.GetBalance(ctx, 1)
+	assert.NoError(t, err)
+	assert.Zero(t, balance.Amount)
+}
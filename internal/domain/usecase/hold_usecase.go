@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type holdUseCase struct {
+	holdRepo  r.HoldRepository
+	userRepo  r.UserRepository
+	bookRepo  r.BookRepository
+	txManager r.TxManager
+	policy    LoanPolicy
+}
+
+// NewHoldUseCase creates a new instance of holdUseCase
+func NewHoldUseCase(hold r.HoldRepository, user r.UserRepository, book r.BookRepository, tx r.TxManager, policy LoanPolicy) u.HoldUsecase {
+	return &holdUseCase{
+		holdRepo:  hold,
+		userRepo:  user,
+		bookRepo:  book,
+		txManager: tx,
+		policy:    policy,
+	}
+}
+
+func (s *holdUseCase) GetHold(ctx context.Context, id int) (*entity.Hold, error) {
+	hold, err := s.holdRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (s *holdUseCase) PlaceHold(ctx context.Context, userID, bookID int) (int, error) {
+	var id int
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = s.placeHold(ctx, userID, bookID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// placeHold queues the user up for the book while the hold queue is locked, so
+// the active hold check and the new hold see the holds placed at the same time
+func (s *holdUseCase) placeHold(ctx context.Context, userID, bookID int) (int, error) {
+	user, err := s.userRepo.Get(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	book, err := s.bookRepo.Get(ctx, bookID)
+	if err != nil {
+		return 0, err
+	}
+
+	queue, err := allocateHolds(ctx, s.holdRepo, book, s.policy.HoldExpiry)
+	if err != nil {
+		return 0, err
+	}
+
+	hold, reserved := findHold(queue, user.ID)
+	if hold != nil {
+		return 0, ErrHoldAlreadyPlaced
+	}
+
+	if book.Amount > reserved {
+		return 0, ErrBookAvailable
+	}
+
+	hold, err = entity.NewHold(user.ID, book.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.holdRepo.Create(ctx, hold)
+	if err != nil {
+		// the queue was empty so nothing was locked and the user placed a hold at the same time
+		if err == r.ErrHoldActive {
+			return 0, ErrHoldAlreadyPlaced
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *holdUseCase) CancelHold(ctx context.Context, id int) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return s.cancelHold(ctx, id)
+	})
+}
+
+// cancelHold cancels the hold and hands its copy on in the same transaction
+func (s *holdUseCase) cancelHold(ctx context.Context, id int) error {
+	hold, err := s.holdRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !hold.IsActive() {
+		return ErrHoldNotActive
+	}
+
+	wasReady := hold.Status == entity.HoldReady
+
+	hold.Status = entity.HoldCancelled
+	err = s.holdRepo.Update(ctx, hold)
+	if err != nil {
+		return err
+	}
+
+	// hand the copy set aside for this hold to the next patron in the queue
+	if wasReady {
+		book, err := s.bookRepo.Get(ctx, hold.BookID)
+		if err != nil {
+			return err
+		}
+
+		_, err = allocateHolds(ctx, s.holdRepo, book, s.policy.HoldExpiry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *holdUseCase) ListBookHolds(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	holds, err := s.holdRepo.ListActive(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// allocateHolds expires ready holds that were not picked up in time and sets the
+// available copies of the book aside for the oldest waiting holds, returning the
+// holds still active in queue order. The queue stays locked until the
+// transaction ends so concurrent borrows cannot hand out the same reserved copy
+func allocateHolds(ctx context.Context, repo r.HoldRepository, book *entity.Book, expiry time.Duration) ([]*entity.Hold, error) {
+	queue, err := repo.ListActiveForUpdate(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	var active []*entity.Hold
+	var ready int
+	for _, h := range queue {
+		if h.IsExpired(now) {
+			h.Status = entity.HoldExpired
+			if err := repo.Update(ctx, h); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if h.Status == entity.HoldReady {
+			ready++
+		}
+
+		active = append(active, h)
+	}
+
+	for _, h := range active {
+		if ready >= book.Amount {
+			break
+		}
+
+		if h.Status == entity.HoldWaiting {
+			h.Status = entity.HoldReady
+			h.ExpiresAt = now.Add(expiry)
+			if err := repo.Update(ctx, h); err != nil {
+				return nil, err
+			}
+			ready++
+		}
+	}
+
+	return active, nil
+}
+
+// findHold returns the active hold of the user, if any, and how many copies
+// are set aside for ready holds
+func findHold(queue []*entity.Hold, userID int) (*entity.Hold, int) {
+	var hold *entity.Hold
+	var reserved int
+
+	for _, h := range queue {
+		if h.UserID == userID {
+			hold = h
+		}
+		if h.Status == entity.HoldReady {
+			reserved++
+		}
+	}
+
+	return hold, reserved
+}
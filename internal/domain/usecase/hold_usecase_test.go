@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+func TestPlaceHold(t *testing.T) {
+	repoH := mock.NewMockHoldRepository()
+	repoU := mock.NewMockUserRepository()
+	repoB := mock.NewMockBookRepository()
+
+	uc := NewHoldUseCase(repoH, repoU, repoB, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		id, err := uc.PlaceHold(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+	})
+	t.Run("Already Placed", func(t *testing.T) {
+		_, err := uc.PlaceHold(ctx, 1, 2)
+		assert.ErrorIs(t, err, ErrHoldAlreadyPlaced)
+	})
+	t.Run("Placed At The Same Time", func(t *testing.T) {
+		// the other hold was placed after the queue was read
+		uc := NewHoldUseCase(racingHoldRepository{repoH}, repoU, repoB, mock.NewMockTxManager(), policy)
+
+		_, err := uc.PlaceHold(ctx, 1, 2)
+		assert.ErrorIs(t, err, ErrHoldAlreadyPlaced)
+	})
+	t.Run("Book Available", func(t *testing.T) {
+		_, err := uc.PlaceHold(ctx, 1, 1)
+		assert.ErrorIs(t, err, ErrBookAvailable)
+	})
+	t.Run("Book Not Found", func(t *testing.T) {
+		_, err := uc.PlaceHold(ctx, 1, 5)
+		assert.Error(t, err)
+	})
+	t.Run("User Not Found", func(t *testing.T) {
+		_, err := uc.PlaceHold(ctx, 5, 2)
+		assert.Error(t, err)
+	})
+}
+
+// racingHoldRepository hides the active holds from the queue like a hold placed
+// by a concurrent request that isn't committed yet
+type racingHoldRepository struct {
+	r.HoldRepository
+}
+
+func (h racingHoldRepository) ListActiveForUpdate(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	return []*entity.Hold{}, nil
+}
+
+func TestCancelHold(t *testing.T) {
+	repoH := mock.NewMockHoldRepository()
+	repoU := mock.NewMockUserRepository()
+	repoB := mock.NewMockBookRepository()
+
+	uc := NewHoldUseCase(repoH, repoU, repoB, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	id, err := uc.PlaceHold(ctx, 1, 2)
+	assert.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.CancelHold(ctx, id)
+		assert.NoError(t, err)
+
+		holds, err := uc.ListBookHolds(ctx, 2)
+		assert.NoError(t, err)
+		assert.Empty(t, holds)
+	})
+	t.Run("Not Active", func(t *testing.T) {
+		err := uc.CancelHold(ctx, id)
+		assert.ErrorIs(t, err, ErrHoldNotActive)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.CancelHold(ctx, 5)
+		assert.Error(t, err)
+	})
+}
+
+func TestHoldQueue(t *testing.T) {
+	repoL := mock.NewMockLoanRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoU := mock.NewMockUserRepository()
+	repoB := mock.NewMockBookRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
+
+	loanUC := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
+	holdUC := NewHoldUseCase(repoH, repoU, repoB, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	// user 2 has the only copy of book 2 and user 1 queues up for it
+	id, err := holdUC.PlaceHold(ctx, 1, 2)
+	assert.NoError(t, err)
+
+	t.Run("Copy Set Aside On Return", func(t *testing.T) {
+		err := loanUC.ReturnBook(ctx, 2, 2)
+		assert.NoError(t, err)
+
+		holds, err := holdUC.ListBookHolds(ctx, 2)
+		assert.NoError(t, err)
+		assert.Len(t, holds, 1)
+		assert.Equal(t, entity.HoldReady, holds[0].Status)
+		assert.False(t, holds[0].ExpiresAt.IsZero())
+	})
+	t.Run("Reserved For Head Of Queue", func(t *testing.T) {
+		err := loanUC.BorrowBook(ctx, 2, 2)
+		assert.ErrorIs(t, err, ErrBookReserved)
+	})
+	t.Run("Head Of Queue Borrows", func(t *testing.T) {
+		err := loanUC.BorrowBook(ctx, 1, 2)
+		assert.NoError(t, err)
+
+		hold, err := repoH.Get(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.HoldFulfilled, hold.Status)
+	})
+}
@@ -2,37 +2,127 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
 )
 
+// LoanPolicy holds the library rules applied when lending books
+type LoanPolicy struct {
+	// LoanPeriod is how long a patron can keep a book before it is overdue
+	LoanPeriod time.Duration
+	// MaxRenewals is how many times a loan can be extended by another loan period
+	MaxRenewals int
+	// HoldExpiry is how long a copy stays set aside for a patron on the hold queue
+	HoldExpiry time.Duration
+	// FinePerDay is charged in cents for each day a book is returned late
+	FinePerDay int
+	// MaxBalance is the highest balance in cents a patron can owe and still borrow
+	MaxBalance int
+	// Categories holds the borrowing limits of each patron category
+	Categories map[entity.Category]CategoryPolicy
+}
+
+// CategoryPolicy holds the borrowing limits of a patron category
+type CategoryPolicy struct {
+	// MaxLoans is how many books a patron can have at the same time, zero means no limit
+	MaxLoans int
+	// LoanPeriod replaces the library loan period when set
+	LoanPeriod time.Duration
+}
+
+// loanPeriod returns how long a patron of the category can keep a book
+func (p LoanPolicy) loanPeriod(c entity.Category) time.Duration {
+	if cp, ok := p.Categories[c]; ok && cp.LoanPeriod > 0 {
+		return cp.LoanPeriod
+	}
+
+	return p.LoanPeriod
+}
+
 type loanUseCase struct {
-	loanRepo r.LoanRepository
-	userRepo r.UserRepository
-	bookRepo r.BookRepository
+	loanRepo   r.LoanRepository
+	userRepo   r.UserRepository
+	bookRepo   r.BookRepository
+	copyRepo   r.CopyRepository
+	holdRepo   r.HoldRepository
+	ledgerRepo r.LedgerRepository
+	txManager  r.TxManager
+	policy     LoanPolicy
 }
 
 // NewLoanUseCase creates a new instance of loanUseCase
-func NewLoanUseCase(loan r.LoanRepository, user r.UserRepository, book r.BookRepository) u.LoanUsecase {
+func NewLoanUseCase(loan r.LoanRepository, user r.UserRepository, book r.BookRepository, copy r.CopyRepository, hold r.HoldRepository, ledger r.LedgerRepository, tx r.TxManager, policy LoanPolicy) u.LoanUsecase {
 	return &loanUseCase{
-		loanRepo: loan,
-		userRepo: user,
-		bookRepo: book,
+		loanRepo:   loan,
+		userRepo:   user,
+		bookRepo:   book,
+		copyRepo:   copy,
+		holdRepo:   hold,
+		ledgerRepo: ledger,
+		txManager:  tx,
+		policy:     policy,
 	}
 }
 
+func (s *loanUseCase) GetLoan(ctx context.Context, id int) (*entity.Loan, error) {
+	loan, err := s.loanRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
 func (s *loanUseCase) BorrowBook(ctx context.Context, userID, bookID int) error {
-	exists, err := s.loanRepo.CheckNotReturned(ctx, userID, bookID)
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return s.borrowBook(ctx, userID, bookID)
+	})
+}
+
+// borrowBook lends a copy of the book when the library rules allow it, the
+// copy, the loan and the hold queue change together or not at all
+func (s *loanUseCase) borrowBook(ctx context.Context, userID, bookID int) error {
+	// the patron row is locked first so concurrent borrows of the same patron
+	// wait here and see each other's loans and fines in the checks below
+	user, err := s.userRepo.GetForUpdate(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	open, err := s.loanRepo.GetNotReturned(ctx, user.ID, bookID)
 	if err != nil {
 		return err
 	}
-	if exists {
+	if open != nil {
 		return ErrReturnBookFirst
 	}
 
-	user, err := s.userRepo.Get(ctx, userID)
+	balance, err := s.ledgerRepo.Balance(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if balance > s.policy.MaxBalance {
+		return ErrOutstandingBalance
+	}
+
+	if limit := s.policy.Categories[user.Category].MaxLoans; limit > 0 {
+		open, err := s.loanRepo.CountNotReturned(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if open >= limit {
+			return ErrLoanLimitReached
+		}
+	}
+
+	// the hold queue is locked before the copies are counted, a concurrent
+	// borrow holding it has lent its copy by the time the book is read so the
+	// reservation check below doesn't run against a stale amount
+	_, err = s.holdRepo.ListActiveForUpdate(ctx, bookID)
 	if err != nil {
 		return err
 	}
@@ -42,41 +132,139 @@ func (s *loanUseCase) BorrowBook(ctx context.Context, userID, bookID int) error
 		return err
 	}
 
-	book.Amount -= 1
-	if book.Amount < 0 {
+	queue, err := allocateHolds(ctx, s.holdRepo, book, s.policy.HoldExpiry)
+	if err != nil {
+		return err
+	}
+
+	// copies set aside for the hold queue can only go to the patrons holding them
+	hold, reserved := findHold(queue, user.ID)
+	if hold == nil || hold.Status != entity.HoldReady {
+		if reserved > 0 && book.Amount <= reserved {
+			return ErrBookReserved
+		}
+	}
+
+	if book.Amount < 1 {
 		return ErrBookUnavailable
 	}
 
-	err = s.loanRepo.BorrowTransaction(ctx, user, book)
+	loan, err := entity.NewLoan(user.ID, book.ID, s.policy.loanPeriod(user.Category))
+	if err != nil {
+		return err
+	}
+
+	loan.CopyID, err = s.copyRepo.Lend(ctx, book.ID)
 	if err != nil {
+		// another patron took the last copy since the book was read
+		if err == r.ErrNoCopyAvailable {
+			return ErrBookUnavailable
+		}
 		return err
 	}
 
+	_, err = s.loanRepo.Create(ctx, loan)
+	if err != nil {
+		// the same patron borrowed the book at the same time
+		if err == r.ErrLoanNotReturned {
+			return ErrReturnBookFirst
+		}
+		return err
+	}
+
+	if hold != nil {
+		hold.Status = entity.HoldFulfilled
+		err = s.holdRepo.Update(ctx, hold)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *loanUseCase) ReturnBook(ctx context.Context, userID, bookID int) error {
-	exists, err := s.loanRepo.CheckNotReturned(ctx, userID, bookID)
+	loan, err := s.loanRepo.GetNotReturned(ctx, userID, bookID)
 	if err != nil {
 		return err
 	}
-	if !exists {
+	if loan == nil {
 		return ErrLoanAlreadyReturned
 	}
 
-	user, err := s.userRepo.Get(ctx, userID)
+	return s.returnLoan(ctx, loan)
+}
+
+func (s *loanUseCase) ReturnLoan(ctx context.Context, loanID int) (*entity.Loan, error) {
+	loan, err := s.loanRepo.Get(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Is_returned {
+		return nil, ErrLoanAlreadyReturned
+	}
+
+	err = s.returnLoan(ctx, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// returnLoan closes an open loan, fines the patron when it is returned late and
+// sets the copy aside for the hold queue, all of it or nothing
+func (s *loanUseCase) returnLoan(ctx context.Context, loan *entity.Loan) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return s.closeLoan(ctx, loan)
+	})
+}
+
+func (s *loanUseCase) closeLoan(ctx context.Context, loan *entity.Loan) error {
+	user, err := s.userRepo.Get(ctx, loan.UserID)
 	if err != nil {
 		return err
 	}
 
-	book, err := s.bookRepo.Get(ctx, bookID)
+	book, err := s.bookRepo.Get(ctx, loan.BookID)
+	if err != nil {
+		return err
+	}
+
+	err = s.loanRepo.Return(ctx, loan)
 	if err != nil {
+		// the loan was returned since it was read
+		if err == r.ErrLoanReturned {
+			return ErrLoanAlreadyReturned
+		}
 		return err
 	}
 
+	if loan.CopyID != 0 {
+		err = s.copyRepo.Shelve(ctx, loan.CopyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the returned copy is on the shelf again
 	book.Amount += 1
 
-	err = s.loanRepo.ReturnTransaction(ctx, user, book)
+	// charge the patron for every day the book was kept past its due date
+	if days := loan.OverdueDays(loan.ReturnedAt); days > 0 && s.policy.FinePerDay > 0 {
+		fine, err := entity.NewFine(user.ID, loan.ID, days*s.policy.FinePerDay, fmt.Sprintf("returned %d day(s) late", days))
+		if err != nil {
+			return err
+		}
+
+		_, err = s.ledgerRepo.Create(ctx, fine)
+		if err != nil {
+			return err
+		}
+	}
+
+	// set the returned copy aside for the head of the hold queue
+	_, err = allocateHolds(ctx, s.holdRepo, book, s.policy.HoldExpiry)
 	if err != nil {
 		return err
 	}
@@ -84,6 +272,47 @@ func (s *loanUseCase) ReturnBook(ctx context.Context, userID, bookID int) error
 	return nil
 }
 
+func (s *loanUseCase) RenewLoan(ctx context.Context, loanID int) (*entity.Loan, error) {
+	loan, err := s.loanRepo.Get(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if loan.Is_returned {
+		return nil, ErrLoanAlreadyReturned
+	}
+
+	if loan.IsOverdue(now) {
+		return nil, ErrLoanOverdue
+	}
+
+	if loan.Renewals >= s.policy.MaxRenewals {
+		return nil, ErrRenewalLimitReached
+	}
+
+	user, err := s.userRepo.Get(ctx, loan.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	loan.DueDate = loan.DueDate.Add(s.policy.loanPeriod(user.Category))
+	loan.Renewals += 1
+	loan.RenewedAt = now
+
+	err = s.loanRepo.Renew(ctx, loan)
+	if err != nil {
+		// the loan was returned since it was read
+		if err == r.ErrLoanReturned {
+			return nil, ErrLoanAlreadyReturned
+		}
+		return nil, err
+	}
+
+	return loan, nil
+}
+
 func (s *loanUseCase) SearchUserLoans(ctx context.Context, userID int) ([]*entity.Loan, error) {
 	loans, err := s.loanRepo.Search(ctx, userID)
 	if err != nil {
@@ -92,3 +321,12 @@ func (s *loanUseCase) SearchUserLoans(ctx context.Context, userID int) ([]*entit
 
 	return loans, nil
 }
+
+func (s *loanUseCase) ListOverdueLoans(ctx context.Context) ([]*entity.Loan, error) {
+	loans, err := s.loanRepo.ListOverdue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return loans, nil
+}
@@ -2,28 +2,67 @@ package usecase
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
+var policy = LoanPolicy{
+	LoanPeriod:  14 * 24 * time.Hour,
+	MaxRenewals: 1,
+	HoldExpiry:  3 * 24 * time.Hour,
+	FinePerDay:  25,
+	MaxBalance:  500,
+	Categories: map[entity.Category]CategoryPolicy{
+		entity.CategoryGuest:   {MaxLoans: 1, LoanPeriod: 7 * 24 * time.Hour},
+		entity.CategoryStudent: {MaxLoans: 5},
+	},
+}
+
 func TestBorrowBook(t *testing.T) {
 	repoL := mock.NewMockLoanRepository()
 	repoU := mock.NewMockUserRepository()
 	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
 
-	uc := NewLoanUseCase(repoL, repoU, repoB)
+	uc := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
 	ctx := context.Background()
 
+	t.Run("Book Unavailable", func(t *testing.T) {
+		err := uc.BorrowBook(ctx, 1, 2)
+		assert.ErrorIs(t, err, ErrBookUnavailable)
+	})
 	t.Run("OK", func(t *testing.T) {
 		err := uc.BorrowBook(ctx, 1, 1)
 		assert.NoError(t, err)
+
+		loans, err := uc.SearchUserLoans(ctx, 1)
+		assert.NoError(t, err)
+
+		// user 1 is a guest
+		loan := loans[len(loans)-1]
+		assert.Equal(t, loan.CreatedAt.Add(policy.Categories[entity.CategoryGuest].LoanPeriod), loan.DueDate)
 	})
-	t.Run("Book Unavailable", func(t *testing.T) {
+	t.Run("Loan Limit Reached", func(t *testing.T) {
 		err := uc.BorrowBook(ctx, 1, 2)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrLoanLimitReached)
+	})
+	t.Run("Outstanding Balance", func(t *testing.T) {
+		fine, err := entity.NewFine(2, 3, policy.MaxBalance+1, "lost book")
+		assert.NoError(t, err)
+		_, err = repoF.Create(ctx, fine)
+		assert.NoError(t, err)
+
+		err = uc.BorrowBook(ctx, 2, 1)
+		assert.ErrorIs(t, err, ErrOutstandingBalance)
 	})
 	t.Run("Wrong ID", func(t *testing.T) {
 		err := uc.ReturnBook(ctx, 5, 5)
@@ -31,18 +70,177 @@ func TestBorrowBook(t *testing.T) {
 	})
 }
 
+func TestBorrowBookConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	// borrow runs the borrows at the same time, each pair is a user and a book,
+	// and returns the open loans of the borrowed books once they are all done
+	borrow := func(t *testing.T, repoU r.UserRepository, repoC r.CopyRepository, pairs [][2]int) ([]error, []*entity.Loan) {
+		repoL := mock.NewMockLoanRepository()
+		uc := NewLoanUseCase(repoL, repoU, mock.NewMockBookRepository(), repoC, mock.NewMockHoldRepository(), mock.NewMockLedgerRepository(), mock.NewMockTxManager(), policy)
+
+		errs := make([]error, len(pairs))
+
+		var wg sync.WaitGroup
+		for i, p := range pairs {
+			wg.Add(1)
+			go func(i, userID, bookID int) {
+				defer wg.Done()
+				errs[i] = uc.BorrowBook(ctx, userID, bookID)
+			}(i, p[0], p[1])
+		}
+		wg.Wait()
+
+		var open []*entity.Loan
+		for _, p := range pairs {
+			loan, err := repoL.GetNotReturned(ctx, p[0], p[1])
+			assert.NoError(t, err)
+			if loan != nil && !hasLoan(open, loan) {
+				open = append(open, loan)
+			}
+		}
+
+		return errs, open
+	}
+
+	t.Run("No Duplicate Loan", func(t *testing.T) {
+		pairs := make([][2]int, 20)
+		for i := range pairs {
+			pairs[i] = [2]int{2, 1}
+		}
+
+		errs, open := borrow(t, mock.NewMockUserRepository(), mock.NewMockCopyRepository(), pairs)
+
+		var borrowed int
+		for _, err := range errs {
+			if err == nil {
+				borrowed++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrReturnBookFirst)
+		}
+		assert.Equal(t, 1, borrowed)
+		assert.Len(t, open, 1)
+	})
+	t.Run("No Oversell", func(t *testing.T) {
+		repoU := mock.NewMockUserRepository()
+		repoC := mock.NewMockCopyRepository()
+
+		copies, err := repoC.ListByBook(ctx, 1)
+		assert.NoError(t, err)
+
+		// twenty students want the copies of book 1 at the same time
+		var pairs [][2]int
+		for i := 0; i < 20; i++ {
+			id, err := repoU.Create(ctx, &entity.User{Category: entity.CategoryStudent})
+			assert.NoError(t, err)
+			pairs = append(pairs, [2]int{id, 1})
+		}
+
+		errs, open := borrow(t, repoU, repoC, pairs)
+
+		var borrowed int
+		for _, err := range errs {
+			if err == nil {
+				borrowed++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrBookUnavailable)
+		}
+		assert.Equal(t, len(copies), borrowed)
+		assert.Len(t, open, borrowed)
+
+		// every loan has a copy of its own
+		lent := map[int]bool{}
+		for _, loan := range open {
+			assert.False(t, lent[loan.CopyID])
+			lent[loan.CopyID] = true
+		}
+	})
+}
+
+// hasLoan reports whether the loan is in the loans
+func hasLoan(loans []*entity.Loan, loan *entity.Loan) bool {
+	for _, l := range loans {
+		if l.ID == loan.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lockingHoldRepository and readingBookRepository record the order in which
+// the hold queue is locked and the book is read
+type lockingHoldRepository struct {
+	r.HoldRepository
+	calls *[]string
+}
+
+func (h lockingHoldRepository) ListActiveForUpdate(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	*h.calls = append(*h.calls, "lock queue")
+	return h.HoldRepository.ListActiveForUpdate(ctx, bookID)
+}
+
+type readingBookRepository struct {
+	r.BookRepository
+	calls *[]string
+}
+
+func (b readingBookRepository) Get(ctx context.Context, id int) (*entity.Book, error) {
+	*b.calls = append(*b.calls, "read book")
+	return b.BookRepository.Get(ctx, id)
+}
+
+func TestBorrowBookLocksQueueBeforeReadingBook(t *testing.T) {
+	var calls []string
+	repoH := lockingHoldRepository{mock.NewMockHoldRepository(), &calls}
+	repoB := readingBookRepository{mock.NewMockBookRepository(), &calls}
+
+	uc := NewLoanUseCase(mock.NewMockLoanRepository(), mock.NewMockUserRepository(), repoB, mock.NewMockCopyRepository(), repoH, mock.NewMockLedgerRepository(), mock.NewMockTxManager(), policy)
+
+	err := uc.BorrowBook(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lock queue", "read book"}, calls[:2])
+}
+
 func TestReturnBook(t *testing.T) {
 	repoL := mock.NewMockLoanRepository()
 	repoU := mock.NewMockUserRepository()
 	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
 
-	uc := NewLoanUseCase(repoL, repoU, repoB)
+	uc := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
 		err := uc.ReturnBook(ctx, 2, 2)
 		assert.NoError(t, err)
 	})
+	t.Run("Overdue Fine", func(t *testing.T) {
+		// loan 3 was due yesterday
+		loan, err := repoL.Get(ctx, 3)
+		assert.NoError(t, err)
+
+		entries, err := repoF.List(ctx, 2)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, loan.ID, entries[0].LoanID)
+		assert.Equal(t, loan.OverdueDays(loan.ReturnedAt)*policy.FinePerDay, entries[0].Amount)
+	})
+	t.Run("No Fine On Time", func(t *testing.T) {
+		err := uc.BorrowBook(ctx, 1, 1)
+		assert.NoError(t, err)
+
+		err = uc.ReturnBook(ctx, 1, 1)
+		assert.NoError(t, err)
+
+		balance, err := repoF.Balance(ctx, 1)
+		assert.NoError(t, err)
+		assert.Zero(t, balance)
+	})
 	t.Run("Already Returned", func(t *testing.T) {
 		err := uc.ReturnBook(ctx, 1, 1)
 		assert.Error(t, err)
@@ -53,12 +251,135 @@ func TestReturnBook(t *testing.T) {
 	})
 }
 
+func TestReturnLoan(t *testing.T) {
+	repoL := mock.NewMockLoanRepository()
+	repoU := mock.NewMockUserRepository()
+	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
+
+	uc := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		loan, err := uc.ReturnLoan(ctx, 3)
+		assert.NoError(t, err)
+		assert.True(t, loan.Is_returned)
+		assert.False(t, loan.ReturnedAt.IsZero())
+
+		// loan 3 was due yesterday
+		entries, err := repoF.List(ctx, 2)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, loan.ID, entries[0].LoanID)
+	})
+	t.Run("Already Returned", func(t *testing.T) {
+		loan, err := uc.ReturnLoan(ctx, 3)
+		assert.ErrorIs(t, err, ErrLoanAlreadyReturned)
+		assert.Nil(t, loan)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		loan, err := uc.ReturnLoan(ctx, 10)
+		assert.Error(t, err)
+		assert.Nil(t, loan)
+	})
+}
+
+func TestRenewLoan(t *testing.T) {
+	repoL := mock.NewMockLoanRepository()
+	repoU := mock.NewMockUserRepository()
+	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
+
+	uc := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	err := uc.BorrowBook(ctx, 1, 1)
+	assert.NoError(t, err)
+
+	loans, err := uc.SearchUserLoans(ctx, 1)
+	assert.NoError(t, err)
+	loan := loans[len(loans)-1]
+	dueDate := loan.DueDate
+
+	t.Run("OK", func(t *testing.T) {
+		l, err := uc.RenewLoan(ctx, loan.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, l.Renewals)
+		assert.Equal(t, dueDate.Add(policy.Categories[entity.CategoryGuest].LoanPeriod), l.DueDate)
+	})
+	t.Run("Renewal Limit Reached", func(t *testing.T) {
+		_, err := uc.RenewLoan(ctx, loan.ID)
+		assert.ErrorIs(t, err, ErrRenewalLimitReached)
+	})
+	t.Run("Overdue", func(t *testing.T) {
+		_, err := uc.RenewLoan(ctx, 3)
+		assert.ErrorIs(t, err, ErrLoanOverdue)
+	})
+	t.Run("Already Returned", func(t *testing.T) {
+		_, err := uc.RenewLoan(ctx, 1)
+		assert.ErrorIs(t, err, ErrLoanAlreadyReturned)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := uc.RenewLoan(ctx, 10)
+		assert.Error(t, err)
+	})
+}
+
+func TestRenewLoanConcurrently(t *testing.T) {
+	repoL := mock.NewMockLoanRepository()
+
+	uc := NewLoanUseCase(repoL, mock.NewMockUserRepository(), mock.NewMockBookRepository(), mock.NewMockCopyRepository(), mock.NewMockHoldRepository(), mock.NewMockLedgerRepository(), mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	err := uc.BorrowBook(ctx, 1, 1)
+	assert.NoError(t, err)
+
+	loans, err := uc.SearchUserLoans(ctx, 1)
+	assert.NoError(t, err)
+	loan := loans[len(loans)-1]
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = uc.RenewLoan(ctx, loan.ID)
+		}(i)
+	}
+	wg.Wait()
+
+	renewed := 0
+	for _, err := range errs {
+		if err == nil {
+			renewed++
+			continue
+		}
+		if err != r.ErrLoanRenewed {
+			assert.ErrorIs(t, err, ErrRenewalLimitReached)
+		}
+	}
+	// the renewal limit holds even when every renewal read the loan before any was written
+	assert.Equal(t, 1, renewed)
+
+	got, err := uc.GetLoan(ctx, loan.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.MaxRenewals, got.Renewals)
+}
+
 func TestSearchUserLoans(t *testing.T) {
 	repoL := mock.NewMockLoanRepository()
 	repoU := mock.NewMockUserRepository()
 	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
 
-	uc := NewLoanUseCase(repoL, repoU, repoB)
+	uc := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
@@ -77,3 +398,30 @@ func TestSearchUserLoans(t *testing.T) {
 		assert.Nil(t, loans)
 	})
 }
+
+func TestListOverdueLoans(t *testing.T) {
+	repoL := mock.NewMockLoanRepository()
+	repoU := mock.NewMockUserRepository()
+	repoB := mock.NewMockBookRepository()
+	repoH := mock.NewMockHoldRepository()
+	repoF := mock.NewMockLedgerRepository()
+	repoC := mock.NewMockCopyRepository()
+
+	uc := NewLoanUseCase(repoL, repoU, repoB, repoC, repoH, repoF, mock.NewMockTxManager(), policy)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		loans, err := uc.ListOverdueLoans(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, loans, 1)
+		assert.Equal(t, 3, loans[0].ID)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.ReturnBook(ctx, 2, 2)
+		assert.NoError(t, err)
+
+		loans, err := uc.ListOverdueLoans(ctx)
+		assert.Error(t, err)
+		assert.Nil(t, loans)
+	})
+}
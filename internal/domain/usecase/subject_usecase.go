@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	r "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+	u "github.com/LuigiAzevedo/public-library-v2/internal/ports/usecase"
+)
+
+type subjectUseCase struct {
+	subjectRepo r.SubjectRepository
+	bookRepo    r.BookRepository
+}
+
+// NewSubjectUseCase creates a new instance of subjectUseCase
+func NewSubjectUseCase(subject r.SubjectRepository, book r.BookRepository) u.SubjectUsecase {
+	return &subjectUseCase{
+		subjectRepo: subject,
+		bookRepo:    book,
+	}
+}
+
+func (s *subjectUseCase) GetSubject(ctx context.Context, id int) (*entity.Subject, error) {
+	subject, err := s.subjectRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return subject, nil
+}
+
+func (s *subjectUseCase) ListSubjects(ctx context.Context) ([]*entity.Subject, error) {
+	subjects, err := s.subjectRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return subjects, nil
+}
+
+// ListSubjectBooks lists a page of the books tagged with the subject or any
+// subject below it
+func (s *subjectUseCase) ListSubjectBooks(ctx context.Context, id int, q entity.BookQuery) (*entity.BookPage, error) {
+	_, err := s.subjectRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	q.SubjectID = id
+
+	return listBooks(ctx, s.bookRepo, q)
+}
+
+func (s *subjectUseCase) CreateSubject(ctx context.Context, sub *entity.Subject) (int, error) {
+	subject, err := entity.NewSubject(sub.Name, sub.ParentID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.checkParent(ctx, subject)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.subjectRepo.Create(ctx, subject)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *subjectUseCase) UpdateSubject(ctx context.Context, sub *entity.Subject) error {
+	sub.Name = strings.TrimSpace(sub.Name)
+	sub.UpdatedAt = time.Now()
+
+	err := sub.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = s.checkParent(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	err = s.subjectRepo.Update(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *subjectUseCase) DeleteSubject(ctx context.Context, id int) error {
+	err := s.subjectRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkParent checks the parent of the subject exists and walks up the
+// taxonomy from it, a subject found above itself would close a cycle
+func (s *subjectUseCase) checkParent(ctx context.Context, sub *entity.Subject) error {
+	parentID := sub.ParentID
+	for parentID != 0 {
+		if parentID == sub.ID {
+			return ErrSubjectCycle
+		}
+
+		parent, err := s.subjectRepo.Get(ctx, parentID)
+		if err != nil {
+			if err == r.ErrSubjectNotFound {
+				return ErrParentSubjectNotFound
+			}
+			return err
+		}
+
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
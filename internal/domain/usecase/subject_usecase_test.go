@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+)
+
+func TestListSubjectBooks(t *testing.T) {
+	uc := NewSubjectUseCase(mock.NewMockSubjectRepository(), mock.NewMockBookRepository())
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		page, err := uc.ListSubjectBooks(ctx, 2, entity.BookQuery{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, "Book One", page.Books[0].Title)
+	})
+	t.Run("Subjects Below", func(t *testing.T) {
+		page, err := uc.ListSubjectBooks(ctx, 1, entity.BookQuery{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+	})
+	t.Run("Invalid Query", func(t *testing.T) {
+		_, err := uc.ListSubjectBooks(ctx, 1, entity.BookQuery{Sort: "amount"})
+		assert.ErrorIs(t, err, entity.ErrInvalidBookQuery)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := uc.ListSubjectBooks(ctx, 5, entity.BookQuery{})
+		assert.Error(t, err)
+	})
+}
+
+func TestCreateSubject(t *testing.T) {
+	uc := NewSubjectUseCase(mock.NewMockSubjectRepository(), mock.NewMockBookRepository())
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		id, err := uc.CreateSubject(ctx, &entity.Subject{Name: " Subject Three ", ParentID: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+
+		s, err := uc.GetSubject(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Subject Three", s.Name)
+	})
+	t.Run("Parent Not Found", func(t *testing.T) {
+		_, err := uc.CreateSubject(ctx, &entity.Subject{Name: "Subject Four", ParentID: 5})
+		assert.ErrorIs(t, err, ErrParentSubjectNotFound)
+	})
+	t.Run("Invalid Subject", func(t *testing.T) {
+		_, err := uc.CreateSubject(ctx, &entity.Subject{Name: " "})
+		assert.ErrorIs(t, err, entity.ErrInvalidSubject)
+	})
+}
+
+func TestUpdateSubject(t *testing.T) {
+	uc := NewSubjectUseCase(mock.NewMockSubjectRepository(), mock.NewMockBookRepository())
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.UpdateSubject(ctx, &entity.Subject{ID: 2, Name: "Subject Two"})
+		assert.NoError(t, err)
+
+		s, err := uc.GetSubject(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, s.ParentID)
+	})
+	t.Run("Cycle", func(t *testing.T) {
+		err := uc.UpdateSubject(ctx, &entity.Subject{ID: 2, Name: "Subject Two", ParentID: 1})
+		assert.NoError(t, err)
+
+		err = uc.UpdateSubject(ctx, &entity.Subject{ID: 1, Name: "Subject One", ParentID: 2})
+		assert.ErrorIs(t, err, ErrSubjectCycle)
+	})
+	t.Run("Below Itself", func(t *testing.T) {
+		err := uc.UpdateSubject(ctx, &entity.Subject{ID: 1, Name: "Subject One", ParentID: 1})
+		assert.ErrorIs(t, err, entity.ErrInvalidSubject)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.UpdateSubject(ctx, &entity.Subject{ID: 5, Name: "Subject Five"})
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteSubject(t *testing.T) {
+	uc := NewSubjectUseCase(mock.NewMockSubjectRepository(), mock.NewMockBookRepository())
+	ctx := context.Background()
+
+	t.Run("Has Children", func(t *testing.T) {
+		err := uc.DeleteSubject(ctx, 1)
+		assert.Error(t, err)
+	})
+	t.Run("OK", func(t *testing.T) {
+		err := uc.DeleteSubject(ctx, 2)
+		assert.NoError(t, err)
+
+		subjects, err := uc.ListSubjects(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, subjects, 1)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.DeleteSubject(ctx, 5)
+		assert.Error(t, err)
+	})
+}
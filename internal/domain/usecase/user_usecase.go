@@ -32,7 +32,7 @@ func (s *userUseCase) GetUser(ctx context.Context, id int) (*entity.User, error)
 }
 
 func (s *userUseCase) CreateUser(ctx context.Context, u *entity.User) (int, error) {
-	user, err := entity.NewUser(u.Username, u.Password, u.Email)
+	user, err := entity.NewUser(u.Username, u.Password, u.Email, u.Category)
 	if err != nil {
 		return 0, err
 	}
@@ -55,7 +55,18 @@ func (s *userUseCase) CreateUser(ctx context.Context, u *entity.User) (int, erro
 func (s *userUseCase) UpdateUser(ctx context.Context, u *entity.User) error {
 	u.UpdatedAt = time.Now()
 
-	err := u.Validate()
+	// keep the current category when none is sent
+	if u.Category == "" {
+		user, err := s.userRepo.Get(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+
+		u.Category = user.Category
+	}
+
+	// the password is only changed by ChangePassword
+	err := u.ValidateProfile()
 	if err != nil {
 		return err
 	}
@@ -68,8 +79,79 @@ func (s *userUseCase) UpdateUser(ctx context.Context, u *entity.User) error {
 	return nil
 }
 
-func (s *userUseCase) DeleteUser(ctx context.Context, id int) error {
-	err := s.userRepo.Delete(ctx, id)
+// PatchUser applies a partial update on the profile of the user at the version
+// it was read, the stored password is kept and only the patched fields are written
+func (s *userUseCase) PatchUser(ctx context.Context, id, version int, p *entity.UserPatch) (*entity.User, error) {
+	user, err := s.userRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the patch was made on an older version of the user
+	if user.Version != version {
+		return nil, r.ErrVersionConflict
+	}
+
+	p.Apply(user)
+	user.UpdatedAt = time.Now()
+
+	err = user.ValidateProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.Patch(ctx, user, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userUseCase) ChangeRole(ctx context.Context, id int, role entity.Role) error {
+	if !role.IsValid() {
+		return entity.ErrInvalidRole
+	}
+
+	err := s.userRepo.UpdateRole(ctx, id, role)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userUseCase) ChangePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
+	user, err := s.userRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword))
+	if err != nil {
+		return ErrWrongPassword
+	}
+
+	err = entity.ValidatePassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	err = s.userRepo.UpdatePassword(ctx, id, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userUseCase) DeleteUser(ctx context.Context, id, version int) error {
+	err := s.userRepo.Delete(ctx, id, version)
 	if err != nil {
 		return err
 	}
@@ -5,9 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	"github.com/LuigiAzevedo/public-library-v2/internal/mock"
+	repoErr "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 func TestGetUser(t *testing.T) {
@@ -61,10 +63,41 @@ func TestUpdateUser(t *testing.T) {
 			Username: "UserFive",
 			Password: "PasswordFive",
 			Email:    "five@email.com",
+			Version:  1,
 		}
 
 		err := uc.UpdateUser(ctx, u)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, u.Version)
+		assert.Equal(t, entity.CategoryStudent, u.Category)
+
+		// profile updates leave the stored password alone
+		u, err = uc.GetUser(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "PasswordTwo", u.Password)
+	})
+	t.Run("Invalid Category", func(t *testing.T) {
+		u := &entity.User{
+			ID:       2,
+			Username: "UserFive",
+			Password: "PasswordFive",
+			Email:    "five@email.com",
+			Category: "admin",
+		}
+
+		err := uc.UpdateUser(ctx, u)
+		assert.ErrorIs(t, err, entity.ErrInvalidCategory)
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		u := &entity.User{
+			ID:       2,
+			Username: "UserFive",
+			Email:    "five@email.com",
+			Version:  1,
+		}
+
+		err := uc.UpdateUser(ctx, u)
+		assert.Equal(t, repoErr.ErrVersionConflict, err)
 	})
 	t.Run("Not Found", func(t *testing.T) {
 		u := &entity.User{
@@ -79,17 +112,119 @@ func TestUpdateUser(t *testing.T) {
 	})
 }
 
+func TestPatchUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		uc := NewUserUseCase(mock.NewMockUserRepository())
+
+		email := "two@library.com"
+
+		u, err := uc.PatchUser(ctx, 2, 1, &entity.UserPatch{Email: &email})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, u.Version)
+
+		u, err = uc.GetUser(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, email, u.Email)
+		assert.Equal(t, "UserTwo", u.Username, "the fields left out are kept")
+		assert.Equal(t, entity.CategoryStudent, u.Category)
+		assert.Equal(t, "PasswordTwo", u.Password)
+	})
+	t.Run("Cleared Email", func(t *testing.T) {
+		uc := NewUserUseCase(mock.NewMockUserRepository())
+
+		email := ""
+
+		_, err := uc.PatchUser(ctx, 2, 1, &entity.UserPatch{Email: &email})
+		assert.Equal(t, entity.ErrEmptyUserField, err)
+	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		uc := NewUserUseCase(mock.NewMockUserRepository())
+
+		_, err := uc.PatchUser(ctx, 2, 2, &entity.UserPatch{})
+		assert.Equal(t, repoErr.ErrVersionConflict, err)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		uc := NewUserUseCase(mock.NewMockUserRepository())
+
+		_, err := uc.PatchUser(ctx, 5, 1, &entity.UserPatch{})
+		assert.Equal(t, repoErr.ErrUserNotFound, err)
+	})
+}
+
+func TestChangeRole(t *testing.T) {
+	repo := mock.NewMockUserRepository()
+	uc := NewUserUseCase(repo)
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.ChangeRole(ctx, 2, entity.RoleLibrarian)
+		assert.NoError(t, err)
+
+		u, err := uc.GetUser(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleLibrarian, u.Role)
+	})
+	t.Run("Invalid Role", func(t *testing.T) {
+		err := uc.ChangeRole(ctx, 2, "admin")
+		assert.ErrorIs(t, err, entity.ErrInvalidRole)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.ChangeRole(ctx, 5, entity.RolePatron)
+		assert.Error(t, err)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	repo := mock.NewMockUserRepository()
+	uc := NewUserUseCase(repo)
+	ctx := context.Background()
+
+	id, err := uc.CreateUser(ctx, &entity.User{
+		Username: "UserSix",
+		Password: "PasswordSix",
+		Email:    "six@email.com",
+	})
+	assert.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		err := uc.ChangePassword(ctx, id, "PasswordSix", "PasswordSeven")
+		assert.NoError(t, err)
+
+		u, err := uc.GetUser(ctx, id)
+		assert.NoError(t, err)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("PasswordSeven")))
+	})
+	t.Run("Wrong Password", func(t *testing.T) {
+		err := uc.ChangePassword(ctx, id, "PasswordSix", "PasswordEight")
+		assert.ErrorIs(t, err, ErrWrongPassword)
+	})
+	t.Run("Invalid Password", func(t *testing.T) {
+		err := uc.ChangePassword(ctx, id, "PasswordSeven", "short")
+		assert.ErrorIs(t, err, entity.ErrShortPassword)
+	})
+	t.Run("Not Found", func(t *testing.T) {
+		err := uc.ChangePassword(ctx, 10, "PasswordSix", "PasswordSeven")
+		assert.Error(t, err)
+	})
+}
+
 func TestDeleteUser(t *testing.T) {
 	repo := mock.NewMockUserRepository()
 	uc := NewUserUseCase(repo)
 	ctx := context.Background()
 
 	t.Run("OK", func(t *testing.T) {
-		err := uc.DeleteUser(ctx, 2)
+		err := uc.DeleteUser(ctx, 2, 1)
 		assert.NoError(t, err)
 	})
+	t.Run("Version Conflict", func(t *testing.T) {
+		err := uc.DeleteUser(ctx, 1, 2)
+		assert.Equal(t, repoErr.ErrVersionConflict, err)
+	})
 	t.Run("Not Found", func(t *testing.T) {
-		err := uc.DeleteUser(ctx, 5)
+		err := uc.DeleteUser(ctx, 5, 1)
 		assert.Error(t, err)
 	})
 }
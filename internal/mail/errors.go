@@ -0,0 +1,9 @@
+package mail
+
+import "errors"
+
+// Mail Errors
+var (
+	ErrInvalidHeader = errors.New("mail headers can't have line breaks")
+	ErrSendMail      = errors.New("failed to send mail")
+)
@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMessage(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		msg, err := buildMessage("library@email.com", "luigi@email.com", "Hello", "line one\nline two")
+		assert.NoError(t, err)
+
+		want := "From: library@email.com\r\n" +
+			"To: luigi@email.com\r\n" +
+			"Subject: Hello\r\n" +
+			"MIME-Version: 1.0\r\n" +
+			"Content-Type: text/plain; charset=\"utf-8\"\r\n" +
+			"\r\n" +
+			"line one\r\nline two"
+		assert.Equal(t, want, string(msg))
+	})
+	t.Run("Header Injection", func(t *testing.T) {
+		_, err := buildMessage("library@email.com", "luigi@email.com\r\nBcc: all@email.com", "Hello", "body")
+		assert.Equal(t, ErrInvalidHeader, err)
+	})
+}
+
+func TestMemorySender(t *testing.T) {
+	sender := NewMemorySender()
+
+	t.Run("OK", func(t *testing.T) {
+		err := sender.Send(context.Background(), "luigi@email.com", "Hello", "body")
+		assert.NoError(t, err)
+		assert.Equal(t, []Message{{To: "luigi@email.com", Subject: "Hello", Body: "body"}}, sender.Messages())
+	})
+	t.Run("Canceled Context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := sender.Send(ctx, "luigi@email.com", "Hello", "body")
+		assert.Error(t, err)
+		assert.Len(t, sender.Messages(), 1)
+	})
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is an email kept by the MemorySender
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MemorySender keeps the emails in memory instead of delivering them,
+// it is meant for tests and local development
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemorySender creates a new instance of MemorySender
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (m *MemorySender) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{To: to, Subject: subject, Body: body})
+
+	return nil
+}
+
+// Messages returns the emails sent so far, from the oldest
+func (m *MemorySender) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+
+	return messages
+}
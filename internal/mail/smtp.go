@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	svc "github.com/LuigiAzevedo/public-library-v2/internal/ports/service"
+)
+
+type smtpSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPSender creates a mail sender that delivers through an SMTP server,
+// the server is used without authentication when the username is empty
+func NewSMTPSender(host string, port int, username, password, from string) svc.MailSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpSender{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpSender) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg, err := buildMessage(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrSendMail, err)
+	}
+
+	return nil
+}
+
+// buildMessage formats a plain text email with its headers
+func buildMessage(from, to, subject, body string) ([]byte, error) {
+	// line breaks in the headers would let a caller inject new headers
+	for _, header := range []string{from, to, subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,80 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockAuthorRepository struct {
+	authors []*entity.Author
+}
+
+func NewMockAuthorRepository() ports.AuthorRepository {
+	return &mockAuthorRepository{
+		authors: []*entity.Author{
+			{
+				ID:        1,
+				Name:      "Author One",
+				CreatedAt: time.Now(),
+			},
+			{
+				ID:        2,
+				Name:      "Author Two",
+				CreatedAt: time.Now(),
+			},
+		},
+	}
+}
+
+func (r *mockAuthorRepository) Get(ctx context.Context, id int) (*entity.Author, error) {
+	for _, a := range r.authors {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+
+	return nil, ports.ErrAuthorNotFound
+}
+
+func (r *mockAuthorRepository) List(ctx context.Context) ([]*entity.Author, error) {
+	return r.authors, nil
+}
+
+func (r *mockAuthorRepository) Create(ctx context.Context, a *entity.Author) (int, error) {
+	a.ID = r.authors[len(r.authors)-1].ID + 1
+	a.CreatedAt = time.Now()
+
+	r.authors = append(r.authors, a)
+
+	return a.ID, nil
+}
+
+func (r *mockAuthorRepository) Update(ctx context.Context, a *entity.Author) error {
+	for i, stored := range r.authors {
+		if stored.ID == a.ID {
+			r.authors[i] = a
+			return nil
+		}
+	}
+
+	return ports.ErrAuthorNotFound
+}
+
+// Delete deletes an author, the first author is credited on the first book
+func (r *mockAuthorRepository) Delete(ctx context.Context, id int) error {
+	if id == 1 {
+		return ports.ErrAuthorHasBooks
+	}
+
+	for i, a := range r.authors {
+		if a.ID == id {
+			r.authors = append(r.authors[:i], r.authors[i+1:]...)
+			return nil
+		}
+	}
+
+	return ports.ErrAuthorNotFound
+}
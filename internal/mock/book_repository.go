@@ -2,10 +2,11 @@ package mock
 
 import (
 	"context"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
-	err "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
@@ -18,18 +19,29 @@ func NewMockBookRepository() ports.BookRepository {
 	return &mockBookRepository{
 		books: []*entity.Book{
 			{
-				ID:        1,
-				Title:     "Book One",
-				Author:    "Author One",
-				Amount:    2,
+				ID:     1,
+				Title:  "Book One",
+				Author: "Author One",
+				Amount: 2,
+				Authors: []entity.BookAuthor{
+					{AuthorID: 1, Name: "Author One", Role: entity.AuthorRoleAuthor},
+				},
+				Subjects: []entity.Subject{
+					{ID: 2, Name: "Subject Two", ParentID: 1},
+				},
 				CreatedAt: time.Now(),
+				Version:   1,
 			},
 			{
-				ID:        2,
-				Title:     "Book Two",
-				Author:    "Author Two",
-				Amount:    0,
+				ID:     2,
+				Title:  "Book Two",
+				Author: "Author Two",
+				Amount: 0,
+				Authors: []entity.BookAuthor{
+					{AuthorID: 2, Name: "Author Two", Role: entity.AuthorRoleAuthor},
+				},
 				CreatedAt: time.Now(),
+				Version:   1,
 			},
 		},
 	}
@@ -42,24 +54,123 @@ func (r *mockBookRepository) Get(ctx context.Context, id int) (*entity.Book, err
 		}
 	}
 
-	return nil, err.ErrBookNotFound
+	return nil, ports.ErrBookNotFound
 }
 
-func (r *mockBookRepository) List(ctx context.Context) ([]*entity.Book, error) {
-	return r.books, nil
+// List pages through the books sorted by the query field, the cursor is the offset of the next page
+func (r *mockBookRepository) List(ctx context.Context, q entity.BookQuery) (*entity.BookPage, error) {
+	books := []*entity.Book{}
+	contains := func(s, substr string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+	}
+	for _, b := range r.books {
+		if q.Query != "" && !contains(b.Title, q.Query) && !contains(b.Author, q.Query) {
+			continue
+		}
+		if q.Title != "" && !contains(b.Title, q.Title) {
+			continue
+		}
+		if q.Author != "" && !contains(b.Author, q.Author) {
+			continue
+		}
+		if q.AuthorID != 0 && !credits(b, q.AuthorID) {
+			continue
+		}
+		if q.SubjectID != 0 && !tagged(b, q.SubjectID) {
+			continue
+		}
+		if q.Available && b.Amount <= 0 {
+			continue
+		}
+		books = append(books, b)
+	}
+
+	key := func(b *entity.Book) string {
+		switch q.Sort.Field() {
+		case entity.BookSortAuthor:
+			return b.Author
+		case entity.BookSortCreatedAt:
+			return b.CreatedAt.Format(time.RFC3339Nano)
+		default:
+			return b.Title
+		}
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		a, b := books[i], books[j]
+		if q.Sort.Descending() {
+			a, b = b, a
+		}
+		if key(a) != key(b) {
+			return key(a) < key(b)
+		}
+		return a.ID < b.ID
+	})
+
+	offset := 0
+	if q.Cursor != "" {
+		var e error
+		if offset, e = strconv.Atoi(q.Cursor); e != nil {
+			return nil, ports.ErrInvalidCursor
+		}
+	}
+
+	page := &entity.BookPage{Books: []*entity.Book{}, Total: len(books)}
+	if offset < len(books) {
+		end := offset + q.Limit
+		if end < len(books) {
+			page.NextCursor = strconv.Itoa(end)
+		} else {
+			end = len(books)
+		}
+		page.Books = books[offset:end]
+	}
+
+	return page, nil
 }
 
-func (r *mockBookRepository) Search(ctx context.Context, query string) ([]*entity.Book, error) {
-	var result []*entity.Book
+// Export lists the books after the id in id order
+func (r *mockBookRepository) Export(ctx context.Context, afterID, limit int) ([]*entity.Book, error) {
+	books := []*entity.Book{}
+	for _, b := range r.books {
+		if b.ID > afterID {
+			books = append(books, b)
+		}
+	}
+
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	if len(books) > limit {
+		books = books[:limit]
+	}
+
+	return books, nil
+}
+
+func (r *mockBookRepository) Search(ctx context.Context, s entity.BookSearch) ([]*entity.BookMatch, error) {
+	var result []*entity.BookMatch
 
 	for _, b := range r.books {
-		if strings.Contains(strings.ToLower(b.Title), strings.ToLower(query)) {
-			result = append(result, b)
+		words := strings.Fields(strings.ToLower(b.Title + " " + b.Author + " " + b.Description))
+
+		matched := 0
+		for _, term := range s.Terms() {
+			for _, w := range words {
+				if strings.HasPrefix(w, term) {
+					matched++
+					break
+				}
+			}
+		}
+
+		if matched == len(s.Terms()) {
+			result = append(result, &entity.BookMatch{Book: b, Rank: 1, Snippet: b.Title})
 		}
 	}
 
 	if len(result) == 0 {
-		return nil, err.ErrBookNotFound
+		return nil, ports.ErrBookNotFound
+	}
+	if len(result) > s.Limit {
+		result = result[:s.Limit]
 	}
 
 	return result, nil
@@ -68,30 +179,122 @@ func (r *mockBookRepository) Search(ctx context.Context, query string) ([]*entit
 func (r *mockBookRepository) Create(ctx context.Context, b *entity.Book) (int, error) {
 	b.ID = r.books[len(r.books)-1].ID + 1
 	b.CreatedAt = time.Now()
+	b.Version = 1
 
 	r.books = append(r.books, b)
 
 	return b.ID, nil
 }
 
+// Update updates a book, books without a list of authors or subjects keep theirs
 func (r *mockBookRepository) Update(ctx context.Context, b *entity.Book) error {
 	for i, book := range r.books {
 		if book.ID == b.ID {
+			if b.Version != book.Version {
+				return ports.ErrVersionConflict
+			}
+			b.Version++
+			if b.Authors == nil {
+				b.Authors = book.Authors
+			}
+			if b.Subjects == nil {
+				b.Subjects = book.Subjects
+			}
 			r.books[i] = b
 			return nil
 		}
 	}
 
-	return err.ErrBookNotFound
+	return ports.ErrBookNotFound
 }
 
-func (r *mockBookRepository) Delete(ctx context.Context, id int) error {
+// Patch stores the patched book, it was read from the repository so the
+// fields outside of the patch hold the stored values
+func (r *mockBookRepository) Patch(ctx context.Context, b *entity.Book, p *entity.BookPatch) error {
+	for i, book := range r.books {
+		if book.ID == b.ID {
+			if b.Version != book.Version {
+				return ports.ErrVersionConflict
+			}
+			b.Version++
+			r.books[i] = b
+			return nil
+		}
+	}
+
+	return ports.ErrBookNotFound
+}
+
+// Import updates the fields the rows supply on the books with the ISBN of a
+// stored book and creates the others, rows that can't be stored are rejected
+func (r *mockBookRepository) Import(ctx context.Context, rows []*entity.BookImportRow) error {
+	for _, row := range rows {
+		var stored *entity.Book
+		for _, b := range r.books {
+			if row.Book.ISBN != "" && b.ISBN == row.Book.ISBN {
+				stored = b
+			}
+		}
+
+		if stored != nil {
+			updated := *stored
+			row.Fields.Apply(&updated)
+			if e := r.Patch(ctx, &updated, row.Fields); e != nil {
+				row.Reject(e)
+				continue
+			}
+			row.Book = &updated
+			row.Status = entity.BookImportUpdated
+			continue
+		}
+
+		if row.Fields.Author == nil || row.Book.Amount < 1 {
+			row.Reject(ports.ErrNewBookIncomplete)
+			continue
+		}
+		if _, e := r.Create(ctx, row.Book); e != nil {
+			row.Reject(e)
+			continue
+		}
+		row.Status = entity.BookImportCreated
+	}
+
+	return nil
+}
+
+func (r *mockBookRepository) Delete(ctx context.Context, id, version int) error {
 	for i, book := range r.books {
 		if book.ID == id {
+			if book.Version != version {
+				return ports.ErrVersionConflict
+			}
 			r.books = append(r.books[:i], r.books[i+1:]...)
 			return nil
 		}
 	}
 
-	return err.ErrBookNotFound
+	return ports.ErrBookNotFound
+}
+
+// credits reports whether the author is credited on the book
+func credits(b *entity.Book, authorID int) bool {
+	for _, a := range b.Authors {
+		if a.AuthorID == authorID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagged reports whether the book is tagged with the subject or a subject right
+// below it, the mock taxonomy is only two levels deep
+func tagged(b *entity.Book, subjectID int) bool {
+	for _, s := range b.Subjects {
+		if s.ID == subjectID || s.ParentID == subjectID {
+			return true
+		}
+	}
+
+	return false
 }
@@ -6,6 +6,7 @@ package mock
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	entity "github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
@@ -51,17 +52,17 @@ func (mr *MockBookUsecaseMockRecorder) CreateBook(ctx, b interface{}) *gomock.Ca
 }
 
 // DeleteBook mocks base method.
-func (m *MockBookUsecase) DeleteBook(ctx context.Context, id int) error {
+func (m *MockBookUsecase) DeleteBook(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockBookUsecaseMockRecorder) DeleteBook(ctx, id interface{}) *gomock.Call {
+func (mr *MockBookUsecaseMockRecorder) DeleteBook(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookUsecase)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookUsecase)(nil).DeleteBook), ctx, id, version)
 }
 
 // GetBook mocks base method.
@@ -80,33 +81,33 @@ func (mr *MockBookUsecaseMockRecorder) GetBook(ctx, id interface{}) *gomock.Call
 }
 
 // ListBooks mocks base method.
-func (m *MockBookUsecase) ListBooks(ctx context.Context) ([]*entity.Book, error) {
+func (m *MockBookUsecase) ListBooks(ctx context.Context, q entity.BookQuery) (*entity.BookPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBooks", ctx)
-	ret0, _ := ret[0].([]*entity.Book)
+	ret := m.ctrl.Call(m, "ListBooks", ctx, q)
+	ret0, _ := ret[0].(*entity.BookPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListBooks indicates an expected call of ListBooks.
-func (mr *MockBookUsecaseMockRecorder) ListBooks(ctx interface{}) *gomock.Call {
+func (mr *MockBookUsecaseMockRecorder) ListBooks(ctx, q interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockBookUsecase)(nil).ListBooks), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockBookUsecase)(nil).ListBooks), ctx, q)
 }
 
 // SearchBooks mocks base method.
-func (m *MockBookUsecase) SearchBooks(ctx context.Context, query string) ([]*entity.Book, error) {
+func (m *MockBookUsecase) SearchBooks(ctx context.Context, s entity.BookSearch) ([]*entity.BookMatch, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchBooks", ctx, query)
-	ret0, _ := ret[0].([]*entity.Book)
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, s)
+	ret0, _ := ret[0].([]*entity.BookMatch)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchBooks indicates an expected call of SearchBooks.
-func (mr *MockBookUsecaseMockRecorder) SearchBooks(ctx, query interface{}) *gomock.Call {
+func (mr *MockBookUsecaseMockRecorder) SearchBooks(ctx, s interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookUsecase)(nil).SearchBooks), ctx, query)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookUsecase)(nil).SearchBooks), ctx, s)
 }
 
 // UpdateBook mocks base method.
@@ -122,3 +123,48 @@ func (mr *MockBookUsecaseMockRecorder) UpdateBook(ctx, b interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookUsecase)(nil).UpdateBook), ctx, b)
 }
+
+// PatchBook mocks base method.
+func (m *MockBookUsecase) PatchBook(ctx context.Context, id, version int, p *entity.BookPatch) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, id, version, p)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockBookUsecaseMockRecorder) PatchBook(ctx, id, version, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockBookUsecase)(nil).PatchBook), ctx, id, version, p)
+}
+
+// ImportBooks mocks base method.
+func (m *MockBookUsecase) ImportBooks(ctx context.Context, format entity.BookImportFormat, r io.Reader) (*entity.BookImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", ctx, format, r)
+	ret0, _ := ret[0].(*entity.BookImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockBookUsecaseMockRecorder) ImportBooks(ctx, format, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockBookUsecase)(nil).ImportBooks), ctx, format, r)
+}
+
+// ExportBooks mocks base method.
+func (m *MockBookUsecase) ExportBooks(ctx context.Context, format entity.BookExportFormat, w io.Writer) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, format, w)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockBookUsecaseMockRecorder) ExportBooks(ctx, format, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockBookUsecase)(nil).ExportBooks), ctx, format, w)
+}
@@ -0,0 +1,212 @@
+package mock
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockCopyRepository struct {
+	// mu makes lending and shelving atomic like the row locks of the database,
+	// concurrent loans race on them
+	mu        sync.Mutex
+	copies    []*entity.Copy
+	movements []*entity.InventoryMovement
+}
+
+func NewMockCopyRepository() ports.CopyRepository {
+	return &mockCopyRepository{
+		copies: []*entity.Copy{
+			{
+				ID:        1,
+				BookID:    1,
+				Barcode:   "B1-1",
+				Status:    entity.CopyAvailable,
+				CreatedAt: time.Now(),
+			},
+			{
+				ID:        2,
+				BookID:    1,
+				Barcode:   "B1-2",
+				Status:    entity.CopyAvailable,
+				CreatedAt: time.Now(),
+			},
+			{
+				ID:        3,
+				BookID:    2,
+				Barcode:   "B2-1",
+				Status:    entity.CopyOnLoan,
+				CreatedAt: time.Now(),
+			},
+		},
+	}
+}
+
+func (r *mockCopyRepository) Get(ctx context.Context, id int) (*entity.Copy, error) {
+	for _, c := range r.copies {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+
+	return nil, ports.ErrCopyNotFound
+}
+
+func (r *mockCopyRepository) ListByBook(ctx context.Context, bookID int) ([]*entity.Copy, error) {
+	copies := []*entity.Copy{}
+	for _, c := range r.copies {
+		if c.BookID == bookID {
+			copies = append(copies, c)
+		}
+	}
+
+	return copies, nil
+}
+
+func (r *mockCopyRepository) Create(ctx context.Context, c *entity.Copy) (int, error) {
+	for _, stored := range r.copies {
+		if stored.Barcode == c.Barcode {
+			return 0, ports.ErrBarcodeAlreadyExists
+		}
+	}
+
+	c.ID = r.copies[len(r.copies)-1].ID + 1
+	c.CreatedAt = time.Now()
+
+	r.copies = append(r.copies, c)
+
+	return c.ID, nil
+}
+
+func (r *mockCopyRepository) Update(ctx context.Context, c *entity.Copy) error {
+	for i, stored := range r.copies {
+		if stored.ID == c.ID {
+			r.copies[i] = c
+			return nil
+		}
+	}
+
+	return ports.ErrCopyNotFound
+}
+
+func (r *mockCopyRepository) Delete(ctx context.Context, id int) error {
+	for i, c := range r.copies {
+		if c.ID == id {
+			r.copies = append(r.copies[:i], r.copies[i+1:]...)
+			return nil
+		}
+	}
+
+	return ports.ErrCopyNotFound
+}
+
+func (r *mockCopyRepository) Lend(ctx context.Context, bookID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.copies {
+		if c.BookID == bookID && c.Status == entity.CopyAvailable {
+			c.Status = entity.CopyOnLoan
+			return c.ID, nil
+		}
+	}
+
+	return 0, ports.ErrNoCopyAvailable
+}
+
+func (r *mockCopyRepository) Shelve(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.copies {
+		if c.ID == id && c.Status == entity.CopyOnLoan {
+			c.Status = entity.CopyAvailable
+		}
+	}
+
+	return nil
+}
+
+// Adjust adds copies labelled after the copies of the book, restores the oldest
+// lost or in repair copies, or moves the newest available copies
+func (r *mockCopyRepository) Adjust(ctx context.Context, m *entity.InventoryMovement) error {
+	var moved []*entity.Copy
+	if m.Kind == entity.MovementAcquired {
+		n := 0
+		for _, c := range r.copies {
+			if c.BookID == m.BookID {
+				n++
+			}
+		}
+		for i := 1; i <= m.Quantity; i++ {
+			moved = append(moved, &entity.Copy{
+				BookID:  m.BookID,
+				Barcode: "B" + strconv.Itoa(m.BookID) + "-" + strconv.Itoa(n+i),
+				Status:  entity.CopyAvailable,
+			})
+		}
+	} else if m.Kind == entity.MovementRestored {
+		for _, c := range r.copies {
+			if len(moved) < m.Quantity && c.BookID == m.BookID && (c.Status == entity.CopyLost || c.Status == entity.CopyInRepair) {
+				moved = append(moved, c)
+			}
+		}
+		if len(moved) < m.Quantity {
+			return ports.ErrNotEnoughCopies
+		}
+	} else {
+		for i := len(r.copies) - 1; i >= 0 && len(moved) < m.Quantity; i-- {
+			c := r.copies[i]
+			if c.BookID == m.BookID && c.Status == entity.CopyAvailable {
+				moved = append(moved, c)
+			}
+		}
+		if len(moved) < m.Quantity {
+			return ports.ErrNotEnoughCopies
+		}
+	}
+
+	m.Barcodes = []string{}
+	for _, c := range moved {
+		switch m.Kind {
+		case entity.MovementAcquired:
+			if _, e := r.Create(ctx, c); e != nil {
+				return e
+			}
+		case entity.MovementWithdrawn:
+			if e := r.Delete(ctx, c.ID); e != nil {
+				return e
+			}
+		default:
+			c.Status = m.Kind.CopyStatus()
+		}
+
+		m.Barcodes = append(m.Barcodes, c.Barcode)
+	}
+
+	return r.RecordMovement(ctx, m)
+}
+
+func (r *mockCopyRepository) RecordMovement(ctx context.Context, m *entity.InventoryMovement) error {
+	m.ID = len(r.movements) + 1
+	m.CreatedAt = time.Now()
+	r.movements = append(r.movements, m)
+
+	return nil
+}
+
+// ListMovements lists the stock adjustments of a book, the latest first
+func (r *mockCopyRepository) ListMovements(ctx context.Context, bookID int) ([]*entity.InventoryMovement, error) {
+	movements := []*entity.InventoryMovement{}
+	for i := len(r.movements) - 1; i >= 0; i-- {
+		if r.movements[i].BookID == bookID {
+			movements = append(movements, r.movements[i])
+		}
+	}
+
+	return movements, nil
+}
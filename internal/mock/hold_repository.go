@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockHoldRepository struct {
+	holds []*entity.Hold
+}
+
+func NewMockHoldRepository() ports.HoldRepository {
+	return &mockHoldRepository{
+		holds: []*entity.Hold{},
+	}
+}
+
+func (r *mockHoldRepository) Get(ctx context.Context, id int) (*entity.Hold, error) {
+	for _, h := range r.holds {
+		if h.ID == id {
+			return h, nil
+		}
+	}
+
+	return nil, ports.ErrHoldNotFound
+}
+
+func (r *mockHoldRepository) ListActive(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	holds := []*entity.Hold{}
+	for _, h := range r.holds {
+		if h.BookID == bookID && h.IsActive() {
+			holds = append(holds, h)
+		}
+	}
+
+	return holds, nil
+}
+
+func (r *mockHoldRepository) ListActiveForUpdate(ctx context.Context, bookID int) ([]*entity.Hold, error) {
+	return r.ListActive(ctx, bookID)
+}
+
+func (r *mockHoldRepository) Create(ctx context.Context, h *entity.Hold) (int, error) {
+	for _, hold := range r.holds {
+		if hold.UserID == h.UserID && hold.BookID == h.BookID && hold.IsActive() {
+			return 0, ports.ErrHoldActive
+		}
+	}
+
+	h.ID = len(r.holds) + 1
+	h.CreatedAt = time.Now()
+
+	r.holds = append(r.holds, h)
+
+	return h.ID, nil
+}
+
+func (r *mockHoldRepository) Update(ctx context.Context, h *entity.Hold) error {
+	for i, hold := range r.holds {
+		if hold.ID == h.ID {
+			r.holds[i] = h
+			return nil
+		}
+	}
+
+	return ports.ErrHoldNotFound
+}
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockLedgerRepository struct {
+	entries []*entity.LedgerEntry
+}
+
+func NewMockLedgerRepository() ports.LedgerRepository {
+	return &mockLedgerRepository{
+		entries: []*entity.LedgerEntry{},
+	}
+}
+
+func (r *mockLedgerRepository) List(ctx context.Context, userID int) ([]*entity.LedgerEntry, error) {
+	entries := []*entity.LedgerEntry{}
+	for _, e := range r.entries {
+		if e.UserID == userID {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
+
+func (r *mockLedgerRepository) Balance(ctx context.Context, userID int) (int, error) {
+	var balance int
+	for _, e := range r.entries {
+		if e.UserID == userID {
+			balance += e.Signed()
+		}
+	}
+
+	return balance, nil
+}
+
+func (r *mockLedgerRepository) Create(ctx context.Context, e *entity.LedgerEntry) (int, error) {
+	e.ID = len(r.entries) + 1
+	e.CreatedAt = time.Now()
+
+	r.entries = append(r.entries, e)
+
+	return e.ID, nil
+}
@@ -2,14 +2,15 @@ package mock
 
 import (
 	"context"
+	"sync"
 	"time"
 
-	err "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
 
 type mockLoanRepository struct {
+	mu    sync.Mutex
 	loans []*entity.Loan
 }
 
@@ -34,24 +35,61 @@ func NewMockLoanRepository() ports.LoanRepository {
 				ID:          3,
 				UserID:      2,
 				BookID:      2,
+				CopyID:      3,
 				Is_returned: false,
-				CreatedAt:   time.Now(),
+				DueDate:     time.Now().AddDate(0, 0, -1),
+				CreatedAt:   time.Now().AddDate(0, 0, -15),
 			},
 		},
 	}
 }
 
-func (r *mockLoanRepository) CheckNotReturned(ctx context.Context, userID int, bookID int) (bool, error) {
+func (r *mockLoanRepository) Get(ctx context.Context, id int) (*entity.Loan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, l := range r.loans {
+		if l.ID == id {
+			// a copy, like a row read from the database
+			loan := *l
+			return &loan, nil
+		}
+	}
+
+	return nil, ports.ErrLoanIDNotFound
+}
+
+func (r *mockLoanRepository) GetNotReturned(ctx context.Context, userID int, bookID int) (*entity.Loan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for _, l := range r.loans {
 		if l.UserID == userID && l.BookID == bookID && !l.Is_returned {
-			return true, nil
+			return l, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *mockLoanRepository) CountNotReturned(ctx context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int
+	for _, l := range r.loans {
+		if l.UserID == userID && !l.Is_returned {
+			count++
 		}
 	}
 
-	return false, nil
+	return count, nil
 }
 
 func (r *mockLoanRepository) Search(ctx context.Context, userID int) ([]*entity.Loan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	var loans []*entity.Loan
 	for _, l := range r.loans {
 		if l.UserID == userID {
@@ -60,32 +98,88 @@ func (r *mockLoanRepository) Search(ctx context.Context, userID int) ([]*entity.
 	}
 
 	if len(loans) == 0 {
-		return nil, err.ErrLoanNotFound
+		return nil, ports.ErrLoanNotFound
+	}
+
+	return loans, nil
+}
+
+func (r *mockLoanRepository) ListOverdue(ctx context.Context) ([]*entity.Loan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var loans []*entity.Loan
+	for _, l := range r.loans {
+		if l.IsOverdue(time.Now()) {
+			loans = append(loans, l)
+		}
+	}
+
+	if len(loans) == 0 {
+		return nil, ports.ErrNoOverdueLoans
 	}
 
 	return loans, nil
 }
 
-func (r *mockLoanRepository) BorrowTransaction(ctx context.Context, u *entity.User, b *entity.Book) error {
-	loan := &entity.Loan{
-		ID:        4,
-		UserID:    u.ID,
-		BookID:    b.ID,
-		CreatedAt: time.Now(),
+func (r *mockLoanRepository) Renew(ctx context.Context, l *entity.Loan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, loan := range r.loans {
+		if loan.ID == l.ID {
+			if loan.Is_returned {
+				return ports.ErrLoanReturned
+			}
+			if loan.Renewals != l.Renewals-1 {
+				return ports.ErrLoanRenewed
+			}
+
+			renewed := *l
+			r.loans[i] = &renewed
+			return nil
+		}
+	}
+
+	return ports.ErrLoanIDNotFound
+}
+
+func (r *mockLoanRepository) Create(ctx context.Context, l *entity.Loan) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, loan := range r.loans {
+		if loan.UserID == l.UserID && loan.BookID == l.BookID && !loan.Is_returned {
+			return 0, ports.ErrLoanNotReturned
+		}
 	}
 
-	r.loans = append(r.loans, loan)
+	l.ID = r.loans[len(r.loans)-1].ID + 1
+
+	r.loans = append(r.loans, l)
 
-	return nil
+	return l.ID, nil
 }
 
-func (r *mockLoanRepository) ReturnTransaction(ctx context.Context, u *entity.User, b *entity.Book) error {
+func (r *mockLoanRepository) Return(ctx context.Context, loan *entity.Loan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for _, l := range r.loans {
-		if l.UserID == u.ID && l.BookID == b.ID && !l.Is_returned {
-			r.loans[l.ID-1].Is_returned = true
+		if l.ID == loan.ID {
+			if l.Is_returned {
+				return ports.ErrLoanReturned
+			}
+
+			l.Is_returned = true
+			l.ReturnedAt = time.Now()
+
+			loan.Is_returned = l.Is_returned
+			loan.ReturnedAt = l.ReturnedAt
+			loan.CopyID = l.CopyID
 			return nil
 		}
 	}
 
-	return err.ErrLoanNotFound
+	return ports.ErrLoanIDNotFound
 }
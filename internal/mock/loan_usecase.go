@@ -35,6 +35,21 @@ func (m *MockLoanUsecase) EXPECT() *MockLoanUsecaseMockRecorder {
 	return m.recorder
 }
 
+// GetLoan mocks base method.
+func (m *MockLoanUsecase) GetLoan(ctx context.Context, id int) (*entity.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoan", ctx, id)
+	ret0, _ := ret[0].(*entity.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoan indicates an expected call of GetLoan.
+func (mr *MockLoanUsecaseMockRecorder) GetLoan(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoan", reflect.TypeOf((*MockLoanUsecase)(nil).GetLoan), ctx, id)
+}
+
 // BorrowBook mocks base method.
 func (m *MockLoanUsecase) BorrowBook(ctx context.Context, userID, bookID int) error {
 	m.ctrl.T.Helper()
@@ -63,6 +78,36 @@ func (mr *MockLoanUsecaseMockRecorder) ReturnBook(ctx, userID, bookID interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockLoanUsecase)(nil).ReturnBook), ctx, userID, bookID)
 }
 
+// ReturnLoan mocks base method.
+func (m *MockLoanUsecase) ReturnLoan(ctx context.Context, loanID int) (*entity.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnLoan", ctx, loanID)
+	ret0, _ := ret[0].(*entity.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnLoan indicates an expected call of ReturnLoan.
+func (mr *MockLoanUsecaseMockRecorder) ReturnLoan(ctx, loanID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnLoan", reflect.TypeOf((*MockLoanUsecase)(nil).ReturnLoan), ctx, loanID)
+}
+
+// RenewLoan mocks base method.
+func (m *MockLoanUsecase) RenewLoan(ctx context.Context, loanID int) (*entity.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewLoan", ctx, loanID)
+	ret0, _ := ret[0].(*entity.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewLoan indicates an expected call of RenewLoan.
+func (mr *MockLoanUsecaseMockRecorder) RenewLoan(ctx, loanID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewLoan", reflect.TypeOf((*MockLoanUsecase)(nil).RenewLoan), ctx, loanID)
+}
+
 // SearchUserLoans mocks base method.
 func (m *MockLoanUsecase) SearchUserLoans(ctx context.Context, userID int) ([]*entity.Loan, error) {
 	m.ctrl.T.Helper()
@@ -77,3 +122,18 @@ func (mr *MockLoanUsecaseMockRecorder) SearchUserLoans(ctx, userID interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUserLoans", reflect.TypeOf((*MockLoanUsecase)(nil).SearchUserLoans), ctx, userID)
 }
+
+// ListOverdueLoans mocks base method.
+func (m *MockLoanUsecase) ListOverdueLoans(ctx context.Context) ([]*entity.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueLoans", ctx)
+	ret0, _ := ret[0].([]*entity.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueLoans indicates an expected call of ListOverdueLoans.
+func (mr *MockLoanUsecaseMockRecorder) ListOverdueLoans(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueLoans", reflect.TypeOf((*MockLoanUsecase)(nil).ListOverdueLoans), ctx)
+}
@@ -0,0 +1,49 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockResetTokenRepository struct {
+	tokens []*entity.ResetToken
+}
+
+func NewMockResetTokenRepository() ports.ResetTokenRepository {
+	return &mockResetTokenRepository{
+		tokens: []*entity.ResetToken{},
+	}
+}
+
+func (r *mockResetTokenRepository) GetByHash(ctx context.Context, hash string) (*entity.ResetToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+
+	return nil, ports.ErrResetTokenNotFound
+}
+
+func (r *mockResetTokenRepository) Create(ctx context.Context, t *entity.ResetToken) (int, error) {
+	t.ID = len(r.tokens) + 1
+	t.CreatedAt = time.Now()
+
+	r.tokens = append(r.tokens, t)
+
+	return t.ID, nil
+}
+
+func (r *mockResetTokenRepository) MarkUsed(ctx context.Context, id int) error {
+	for _, t := range r.tokens {
+		if t.ID == id && t.UsedAt.IsZero() {
+			t.UsedAt = time.Now()
+			return nil
+		}
+	}
+
+	return ports.ErrResetTokenNotFound
+}
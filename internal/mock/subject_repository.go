@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockSubjectRepository struct {
+	subjects []*entity.Subject
+}
+
+func NewMockSubjectRepository() ports.SubjectRepository {
+	return &mockSubjectRepository{
+		subjects: []*entity.Subject{
+			{
+				ID:        1,
+				Name:      "Subject One",
+				CreatedAt: time.Now(),
+			},
+			{
+				ID:        2,
+				Name:      "Subject Two",
+				ParentID:  1,
+				CreatedAt: time.Now(),
+			},
+		},
+	}
+}
+
+func (r *mockSubjectRepository) Get(ctx context.Context, id int) (*entity.Subject, error) {
+	for _, s := range r.subjects {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+
+	return nil, ports.ErrSubjectNotFound
+}
+
+func (r *mockSubjectRepository) List(ctx context.Context) ([]*entity.Subject, error) {
+	return r.subjects, nil
+}
+
+func (r *mockSubjectRepository) Create(ctx context.Context, s *entity.Subject) (int, error) {
+	s.ID = r.subjects[len(r.subjects)-1].ID + 1
+	s.CreatedAt = time.Now()
+
+	r.subjects = append(r.subjects, s)
+
+	return s.ID, nil
+}
+
+func (r *mockSubjectRepository) Update(ctx context.Context, s *entity.Subject) error {
+	for i, stored := range r.subjects {
+		if stored.ID == s.ID {
+			r.subjects[i] = s
+			return nil
+		}
+	}
+
+	return ports.ErrSubjectNotFound
+}
+
+// Delete deletes a subject, subjects with subjects below them can't be deleted
+func (r *mockSubjectRepository) Delete(ctx context.Context, id int) error {
+	for _, s := range r.subjects {
+		if s.ParentID == id {
+			return ports.ErrSubjectHasChildren
+		}
+	}
+
+	for i, s := range r.subjects {
+		if s.ID == id {
+			r.subjects = append(r.subjects[:i], r.subjects[i+1:]...)
+			return nil
+		}
+	}
+
+	return ports.ErrSubjectNotFound
+}
@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+
+	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
+)
+
+type mockTxManager struct{}
+
+// NewMockTxManager creates a transaction manager that runs the function
+// directly, the fake repositories have nothing to roll back
+func NewMockTxManager() ports.TxManager {
+	return &mockTxManager{}
+}
+
+func (m *mockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
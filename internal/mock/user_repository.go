@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	err "github.com/LuigiAzevedo/public-library-v2/internal/database/repository"
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 	ports "github.com/LuigiAzevedo/public-library-v2/internal/ports/repository"
 )
@@ -21,14 +20,20 @@ func NewMockUserRepository() ports.UserRepository {
 				Username:  "UserOne",
 				Password:  "PasswordOne",
 				Email:     "one@email.com",
+				Category:  entity.CategoryGuest,
+				Role:      entity.RoleLibrarian,
 				CreatedAt: time.Now(),
+				Version:   1,
 			},
 			{
 				ID:        2,
 				Username:  "UserTwo",
 				Password:  "PasswordTwo",
 				Email:     "two@email.com",
+				Category:  entity.CategoryStudent,
+				Role:      entity.RolePatron,
 				CreatedAt: time.Now(),
+				Version:   1,
 			},
 		},
 	}
@@ -41,12 +46,37 @@ func (r *mockUserRepository) Get(ctx context.Context, id int) (*entity.User, err
 		}
 	}
 
-	return nil, err.ErrUserNotFound
+	return nil, ports.ErrUserNotFound
+}
+
+func (r *mockUserRepository) GetForUpdate(ctx context.Context, id int) (*entity.User, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *mockUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+
+	return nil, ports.ErrUserNotFound
+}
+
+func (r *mockUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+
+	return nil, ports.ErrUserNotFound
 }
 
 func (r *mockUserRepository) Create(ctx context.Context, u *entity.User) (int, error) {
 	u.ID = r.users[len(r.users)-1].ID + 1
 	u.CreatedAt = time.Now()
+	u.Version = 1
 
 	r.users = append(r.users, u)
 
@@ -56,21 +86,72 @@ func (r *mockUserRepository) Create(ctx context.Context, u *entity.User) (int, e
 func (r *mockUserRepository) Update(ctx context.Context, u *entity.User) error {
 	for i, user := range r.users {
 		if user.ID == u.ID {
+			if u.Version != user.Version {
+				return ports.ErrVersionConflict
+			}
+			u.Version++
+			// the role and password have their own updates
+			u.Role = user.Role
+			u.Password = user.Password
 			r.users[i] = u
 			return nil
 		}
 	}
 
-	return err.ErrUserNotFound
+	return ports.ErrUserNotFound
+}
+
+// Patch stores the patched user, it was read from the repository so the
+// fields outside of the patch hold the stored values
+func (r *mockUserRepository) Patch(ctx context.Context, u *entity.User, p *entity.UserPatch) error {
+	for i, user := range r.users {
+		if user.ID == u.ID {
+			if u.Version != user.Version {
+				return ports.ErrVersionConflict
+			}
+			u.Version++
+			r.users[i] = u
+			return nil
+		}
+	}
+
+	return ports.ErrUserNotFound
+}
+
+func (r *mockUserRepository) UpdateRole(ctx context.Context, id int, role entity.Role) error {
+	for _, user := range r.users {
+		if user.ID == id {
+			user.Role = role
+			user.Version++
+			return nil
+		}
+	}
+
+	return ports.ErrUserNotFound
+}
+
+func (r *mockUserRepository) UpdatePassword(ctx context.Context, id int, hash string) error {
+	for _, user := range r.users {
+		if user.ID == id {
+			user.Password = hash
+			user.Version++
+			return nil
+		}
+	}
+
+	return ports.ErrUserNotFound
 }
 
-func (r *mockUserRepository) Delete(ctx context.Context, id int) error {
+func (r *mockUserRepository) Delete(ctx context.Context, id, version int) error {
 	for i, user := range r.users {
 		if user.ID == id {
+			if user.Version != version {
+				return ports.ErrVersionConflict
+			}
 			r.users = append(r.users[:i], r.users[i+1:]...)
 			return nil
 		}
 	}
 
-	return err.ErrUserNotFound
+	return ports.ErrUserNotFound
 }
@@ -51,17 +51,17 @@ func (mr *MockUserUsecaseMockRecorder) CreateUser(ctx, u interface{}) *gomock.Ca
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserUsecase) DeleteUser(ctx context.Context, id int) error {
+func (m *MockUserUsecase) DeleteUser(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserUsecaseMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call {
+func (mr *MockUserUsecaseMockRecorder) DeleteUser(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserUsecase)(nil).DeleteUser), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserUsecase)(nil).DeleteUser), ctx, id, version)
 }
 
 // GetUser mocks base method.
@@ -92,3 +92,46 @@ func (mr *MockUserUsecaseMockRecorder) UpdateUser(ctx, u interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserUsecase)(nil).UpdateUser), ctx, u)
 }
+
+// PatchUser mocks base method.
+func (m *MockUserUsecase) PatchUser(ctx context.Context, id, version int, p *entity.UserPatch) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", ctx, id, version, p)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchUser indicates an expected call of PatchUser.
+func (mr *MockUserUsecaseMockRecorder) PatchUser(ctx, id, version, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockUserUsecase)(nil).PatchUser), ctx, id, version, p)
+}
+
+// ChangeRole mocks base method.
+func (m *MockUserUsecase) ChangeRole(ctx context.Context, id int, role entity.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", ctx, id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockUserUsecaseMockRecorder) ChangeRole(ctx, id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockUserUsecase)(nil).ChangeRole), ctx, id, role)
+}
+
+// ChangePassword mocks base method.
+func (m *MockUserUsecase) ChangePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, id, currentPassword, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserUsecaseMockRecorder) ChangePassword(ctx, id, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserUsecase)(nil).ChangePassword), ctx, id, currentPassword, newPassword)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type AuthorRepository interface {
+	Get(ctx context.Context, id int) (*entity.Author, error)
+	List(ctx context.Context) ([]*entity.Author, error)
+	Create(ctx context.Context, a *entity.Author) (int, error)
+	Update(ctx context.Context, a *entity.Author) error
+	Delete(ctx context.Context, id int) error
+}
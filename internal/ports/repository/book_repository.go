@@ -8,9 +8,12 @@ import (
 
 type BookRepository interface {
 	Get(ctx context.Context, id int) (*entity.Book, error)
-	List(ctx context.Context) ([]*entity.Book, error)
-	Search(ctx context.Context, query string) ([]*entity.Book, error)
+	List(ctx context.Context, q entity.BookQuery) (*entity.BookPage, error)
+	Export(ctx context.Context, afterID, limit int) ([]*entity.Book, error)
+	Search(ctx context.Context, s entity.BookSearch) ([]*entity.BookMatch, error)
 	Create(ctx context.Context, b *entity.Book) (int, error)
 	Update(ctx context.Context, b *entity.Book) error
-	Delete(ctx context.Context, id int) error
+	Patch(ctx context.Context, b *entity.Book, p *entity.BookPatch) error
+	Import(ctx context.Context, rows []*entity.BookImportRow) error
+	Delete(ctx context.Context, id, version int) error
 }
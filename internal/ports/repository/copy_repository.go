@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type CopyRepository interface {
+	Get(ctx context.Context, id int) (*entity.Copy, error)
+	ListByBook(ctx context.Context, bookID int) ([]*entity.Copy, error)
+	Create(ctx context.Context, c *entity.Copy) (int, error)
+	Update(ctx context.Context, c *entity.Copy) error
+	Delete(ctx context.Context, id int) error
+	Lend(ctx context.Context, bookID int) (int, error)
+	Shelve(ctx context.Context, id int) error
+	Adjust(ctx context.Context, m *entity.InventoryMovement) error
+	RecordMovement(ctx context.Context, m *entity.InventoryMovement) error
+	ListMovements(ctx context.Context, bookID int) ([]*entity.InventoryMovement, error)
+}
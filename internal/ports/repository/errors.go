@@ -0,0 +1,32 @@
+package ports
+
+import "errors"
+
+// Repository Errors
+var (
+	ErrBookNotFound         = errors.New("book not found")
+	ErrLoanNotFound         = errors.New("user does not have any loans")
+	ErrNoOverdueLoans       = errors.New("no overdue loans found")
+	ErrLoanIDNotFound       = errors.New("loan not found")
+	ErrHoldNotFound         = errors.New("hold not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrAlreadyExists        = errors.New("username or email already exists")
+	ErrISBNAlreadyExists    = errors.New("another book has the same ISBN")
+	ErrNewBookIncomplete    = errors.New("the author and an amount of at least one copy are required to create a book")
+	ErrAuthorNotFound       = errors.New("author not found")
+	ErrAuthorHasBooks       = errors.New("author is credited on books")
+	ErrSubjectNotFound      = errors.New("subject not found")
+	ErrSubjectAlreadyExists = errors.New("the parent subject already has a subject with the same name")
+	ErrSubjectHasChildren   = errors.New("subject has subjects below it")
+	ErrCopyNotFound         = errors.New("copy not found")
+	ErrBarcodeAlreadyExists = errors.New("another copy has the same barcode")
+	ErrNotEnoughCopies      = errors.New("the book has fewer copies on the shelf than the adjustment removes")
+	ErrNoCopyAvailable      = errors.New("no copy of the book is available")
+	ErrLoanNotReturned      = errors.New("the user has an open loan of the book")
+	ErrHoldActive           = errors.New("the user has an active hold on the book")
+	ErrLoanReturned         = errors.New("the loan has already been returned")
+	ErrLoanRenewed          = errors.New("the loan was renewed since it was read")
+	ErrVersionConflict      = errors.New("the version is not the current one, it was changed since it was read")
+	ErrResetTokenNotFound   = errors.New("reset token not found or already used")
+	ErrInvalidCursor        = errors.New("cursor is malformed or belongs to another sort")
+)
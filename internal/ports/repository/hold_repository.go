@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type HoldRepository interface {
+	Get(ctx context.Context, id int) (*entity.Hold, error)
+	ListActive(ctx context.Context, bookID int) ([]*entity.Hold, error)
+	ListActiveForUpdate(ctx context.Context, bookID int) ([]*entity.Hold, error)
+	Create(ctx context.Context, h *entity.Hold) (int, error)
+	Update(ctx context.Context, h *entity.Hold) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type LedgerRepository interface {
+	List(ctx context.Context, userID int) ([]*entity.LedgerEntry, error)
+	Balance(ctx context.Context, userID int) (int, error)
+	Create(ctx context.Context, e *entity.LedgerEntry) (int, error)
+}
@@ -7,8 +7,12 @@ import (
 )
 
 type LoanRepository interface {
-	CheckNotReturned(ctx context.Context, userID, bookID int) (bool, error)
+	Get(ctx context.Context, id int) (*entity.Loan, error)
+	GetNotReturned(ctx context.Context, userID, bookID int) (*entity.Loan, error)
+	CountNotReturned(ctx context.Context, userID int) (int, error)
 	Search(ctx context.Context, userID int) ([]*entity.Loan, error)
-	BorrowTransaction(ctx context.Context, u *entity.User, b *entity.Book) error
-	ReturnTransaction(ctx context.Context, u *entity.User, b *entity.Book) error
+	ListOverdue(ctx context.Context) ([]*entity.Loan, error)
+	Renew(ctx context.Context, l *entity.Loan) error
+	Create(ctx context.Context, l *entity.Loan) (int, error)
+	Return(ctx context.Context, l *entity.Loan) error
 }
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type ResetTokenRepository interface {
+	GetByHash(ctx context.Context, hash string) (*entity.ResetToken, error)
+	Create(ctx context.Context, t *entity.ResetToken) (int, error)
+	MarkUsed(ctx context.Context, id int) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type SubjectRepository interface {
+	Get(ctx context.Context, id int) (*entity.Subject, error)
+	List(ctx context.Context) ([]*entity.Subject, error)
+	Create(ctx context.Context, s *entity.Subject) (int, error)
+	Update(ctx context.Context, s *entity.Subject) error
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// TxManager runs use case steps atomically, the repositories called with the
+// context given to fn take part in the same transaction
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -8,7 +8,13 @@ import (
 
 type UserRepository interface {
 	Get(ctx context.Context, id int) (*entity.User, error)
+	GetForUpdate(ctx context.Context, id int) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Create(ctx context.Context, u *entity.User) (int, error)
 	Update(ctx context.Context, u *entity.User) error
-	Delete(ctx context.Context, id int) error
+	Patch(ctx context.Context, u *entity.User, p *entity.UserPatch) error
+	UpdateRole(ctx context.Context, id int, role entity.Role) error
+	UpdatePassword(ctx context.Context, id int, hash string) error
+	Delete(ctx context.Context, id, version int) error
 }
@@ -0,0 +1,9 @@
+package ports
+
+import (
+	"context"
+)
+
+type MailSender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type TokenService interface {
+	Issue(u *entity.User) (*entity.Token, error)
+	Parse(token string) (*entity.Claims, error)
+}
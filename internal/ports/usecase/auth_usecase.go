@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type AuthUsecase interface {
+	Login(ctx context.Context, username, password string) (*entity.Token, error)
+	Authenticate(ctx context.Context, token string) (*entity.Claims, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type AuthorUsecase interface {
+	GetAuthor(ctx context.Context, id int) (*entity.Author, error)
+	ListAuthors(ctx context.Context) ([]*entity.Author, error)
+	CreateAuthor(ctx context.Context, a *entity.Author) (int, error)
+	UpdateAuthor(ctx context.Context, a *entity.Author) error
+	DeleteAuthor(ctx context.Context, id int) error
+}
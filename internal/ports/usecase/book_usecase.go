@@ -2,15 +2,19 @@ package ports
 
 import (
 	"context"
+	"io"
 
 	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
 )
 
 type BookUsecase interface {
 	GetBook(ctx context.Context, id int) (*entity.Book, error)
-	ListBooks(ctx context.Context) ([]*entity.Book, error)
-	SearchBooks(ctx context.Context, query string) ([]*entity.Book, error)
+	ListBooks(ctx context.Context, q entity.BookQuery) (*entity.BookPage, error)
+	SearchBooks(ctx context.Context, s entity.BookSearch) ([]*entity.BookMatch, error)
 	CreateBook(ctx context.Context, b *entity.Book) (int, error)
 	UpdateBook(ctx context.Context, b *entity.Book) error
-	DeleteBook(ctx context.Context, id int) error
+	PatchBook(ctx context.Context, id, version int, p *entity.BookPatch) (*entity.Book, error)
+	ImportBooks(ctx context.Context, format entity.BookImportFormat, r io.Reader) (*entity.BookImportReport, error)
+	ExportBooks(ctx context.Context, format entity.BookExportFormat, w io.Writer) (int, error)
+	DeleteBook(ctx context.Context, id, version int) error
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type CopyUsecase interface {
+	GetCopy(ctx context.Context, id int) (*entity.Copy, error)
+	ListBookCopies(ctx context.Context, bookID int) ([]*entity.Copy, error)
+	AddCopy(ctx context.Context, c *entity.Copy, userID int) (int, error)
+	UpdateCopy(ctx context.Context, c *entity.Copy, userID int) error
+	DeleteCopy(ctx context.Context, id, userID int) error
+	AdjustStock(ctx context.Context, movement *entity.InventoryMovement) error
+	ListStockMovements(ctx context.Context, bookID int) ([]*entity.InventoryMovement, error)
+}
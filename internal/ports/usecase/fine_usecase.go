@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type FineUsecase interface {
+	GetBalance(ctx context.Context, userID int) (*entity.Balance, error)
+	PayFine(ctx context.Context, userID, amount int, note string) (int, error)
+}
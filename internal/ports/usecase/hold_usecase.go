@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type HoldUsecase interface {
+	GetHold(ctx context.Context, id int) (*entity.Hold, error)
+	PlaceHold(ctx context.Context, userID, bookID int) (int, error)
+	CancelHold(ctx context.Context, id int) error
+	ListBookHolds(ctx context.Context, bookID int) ([]*entity.Hold, error)
+}
@@ -7,7 +7,11 @@ import (
 )
 
 type LoanUsecase interface {
+	GetLoan(ctx context.Context, id int) (*entity.Loan, error)
 	BorrowBook(ctx context.Context, userID, bookID int) error
 	ReturnBook(ctx context.Context, userID, bookID int) error
+	ReturnLoan(ctx context.Context, loanID int) (*entity.Loan, error)
+	RenewLoan(ctx context.Context, loanID int) (*entity.Loan, error)
 	SearchUserLoans(ctx context.Context, userID int) ([]*entity.Loan, error)
+	ListOverdueLoans(ctx context.Context) ([]*entity.Loan, error)
 }
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/LuigiAzevedo/public-library-v2/internal/domain/entity"
+)
+
+type SubjectUsecase interface {
+	GetSubject(ctx context.Context, id int) (*entity.Subject, error)
+	ListSubjects(ctx context.Context) ([]*entity.Subject, error)
+	ListSubjectBooks(ctx context.Context, id int, q entity.BookQuery) (*entity.BookPage, error)
+	CreateSubject(ctx context.Context, s *entity.Subject) (int, error)
+	UpdateSubject(ctx context.Context, s *entity.Subject) error
+	DeleteSubject(ctx context.Context, id int) error
+}
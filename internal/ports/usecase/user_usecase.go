@@ -10,5 +10,8 @@ type UserUsecase interface {
 	GetUser(ctx context.Context, id int) (*entity.User, error)
 	CreateUser(ctx context.Context, u *entity.User) (int, error)
 	UpdateUser(ctx context.Context, u *entity.User) error
-	DeleteUser(ctx context.Context, id int) error
+	PatchUser(ctx context.Context, id, version int, p *entity.UserPatch) (*entity.User, error)
+	ChangeRole(ctx context.Context, id int, role entity.Role) error
+	ChangePassword(ctx context.Context, id int, currentPassword, newPassword string) error
+	DeleteUser(ctx context.Context, id, version int) error
 }